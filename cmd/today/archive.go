@@ -0,0 +1,99 @@
+// Package main is the entry point for the today application.
+// This file contains the archive subcommand handler.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"today/internal/config"
+)
+
+// archiveHelpText is the help message for the archive subcommand.
+const archiveHelpText = `today archive - Move old completed tasks out of tasks.json
+
+USAGE:
+    today archive [OPTIONS]
+
+OPTIONS:
+    -d, --days N   Archive tasks completed more than N days ago
+                   (default: archive.after_days from config, or 30)
+    -h, --help     Show this help message
+
+DESCRIPTION:
+    Moves completed tasks into per-month files in ~/.today/archive/
+    (for example tasks-2025-01.json), keeping tasks.json small and the app
+    fast. Archived tasks are still included in historical reports.
+
+    Set "archive: {auto: true}" in the config file to archive automatically
+    each time the app starts.
+
+EXAMPLES:
+    # Archive tasks completed more than 30 days ago
+    today archive
+
+    # Archive everything completed before today
+    today archive --days 0
+`
+
+// runArchive handles the "today archive" subcommand.
+func runArchive(args []string) {
+	fs := flag.NewFlagSet("archive", flag.ExitOnError)
+
+	daysFlag := fs.Int("days", -1, "archive tasks completed more than N days ago")
+	fs.IntVar(daysFlag, "d", -1, "archive age in days (shorthand)")
+
+	helpFlag := fs.Bool("help", false, "show help message")
+	fs.BoolVar(helpFlag, "h", false, "show help message (shorthand)")
+
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, archiveHelpText)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *helpFlag {
+		fmt.Print(archiveHelpText)
+		os.Exit(0)
+	}
+
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unknown arguments: %v\n\n", fs.Args())
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Load config and storage
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	days := *daysFlag
+	if days < 0 {
+		days = cfg.Archive.AfterDays
+	}
+
+	store, err := openStorage(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+		os.Exit(1)
+	}
+
+	count, err := store.ArchiveTasks(days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error archiving tasks: %v\n", err)
+		os.Exit(1)
+	}
+
+	if count == 0 {
+		fmt.Printf("No tasks completed more than %d days ago.\n", days)
+		return
+	}
+	fmt.Printf("✓ Archived %d completed tasks to %s\n", count, filepath.Join(cfg.GetDataDir(), "archive"))
+}
@@ -0,0 +1,318 @@
+// Package main is the entry point for the today application.
+// This file contains the encrypt and decrypt subcommand handlers and the
+// passphrase handling shared by every command that opens the data.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"today/internal/config"
+	"today/internal/crypt"
+	"today/internal/storage"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// passphraseEnv holds the passphrase for encrypted data, for scripts and
+// agents that cannot answer a prompt.
+const passphraseEnv = "TODAY_PASSPHRASE"
+
+// maxPassphraseAttempts is how often a wrong passphrase can be retyped.
+const maxPassphraseAttempts = 3
+
+// encryptHelpText is the help message for the encrypt subcommand.
+const encryptHelpText = `today encrypt - Encrypt the data directory with a passphrase
+
+USAGE:
+    today encrypt
+
+OPTIONS:
+    -h, --help   Show this help message
+
+DESCRIPTION:
+    Encrypts tasks.json, habits.json, timer.json and trash.json together with
+    their .bak copies, archives, backups and the activity journal, using
+    AES-256-GCM with a key derived from your passphrase. Every later save is
+    encrypted too. encryption.json in the data directory records how the key
+    is derived (never the passphrase); keep it with the data.
+
+    Encrypted data is unlocked when today starts. The passphrase is taken
+    from TODAY_PASSPHRASE, then from the output of
+    encryption.passphrase_command in the config file, and is asked for
+    otherwise. There is no way to recover the data without it.
+
+    If encrypting stops partway, run it again with the same passphrase to
+    finish. Git history made before encrypting still holds the plaintext
+    files.
+
+EXAMPLES:
+    # Encrypt, typing the new passphrase twice
+    today encrypt
+
+    # Keep the passphrase in a password manager
+    echo 'encryption: {passphrase_command: "pass show today"}' >> ~/.config/today/config.yaml
+`
+
+// decryptHelpText is the help message for the decrypt subcommand.
+const decryptHelpText = `today decrypt - Turn encrypted data back into plain JSON
+
+USAGE:
+    today decrypt
+
+OPTIONS:
+    -h, --help   Show this help message
+
+DESCRIPTION:
+    Decrypts every file encrypted by "today encrypt" and removes
+    encryption.json, so the data is stored as plain JSON again.
+`
+
+// runEncrypt handles the "today encrypt" subcommand.
+func runEncrypt(args []string) {
+	cfg := parseCryptArgs("encrypt", encryptHelpText, args)
+	count, err := encryptData(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Encrypted %d files in %s\n", count, cfg.GetDataDir())
+	fmt.Println("  Keep your passphrase safe: the data cannot be recovered without it.")
+}
+
+func encryptData(cfg *config.Config) (int, error) {
+	dir := cfg.GetDataDir()
+	// A key file left by an earlier run that stopped partway is unlocked
+	// and the run finished; files are read whether or not they were
+	// converted yet.
+	var key *crypt.Key
+	resuming := crypt.Enabled(dir)
+	if resuming {
+		var err error
+		if key, err = unlockKey(cfg, dir); err != nil {
+			return 0, err
+		}
+	}
+	store, err := storage.NewWithKey(dir, key)
+	if err != nil {
+		return 0, fmt.Errorf("initializing storage: %w", err)
+	}
+	defer store.UnregisterInstance()
+	if err := requireSoleInstance(store); err != nil {
+		return 0, err
+	}
+
+	if !resuming {
+		pass, err := newPassphrase(cfg)
+		if err != nil {
+			return 0, err
+		}
+		if key, err = crypt.Create(dir, pass); err != nil {
+			return 0, err
+		}
+	}
+	count, err := store.Rekey(key)
+	if err != nil {
+		return count, fmt.Errorf("encrypting data (run today encrypt again to finish): %w", err)
+	}
+	if resuming && count == 0 {
+		return 0, fmt.Errorf("data in %s is already encrypted", dir)
+	}
+	return count, nil
+}
+
+// runDecrypt handles the "today decrypt" subcommand.
+func runDecrypt(args []string) {
+	cfg := parseCryptArgs("decrypt", decryptHelpText, args)
+	count, err := decryptData(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Decrypted %d files in %s\n", count, cfg.GetDataDir())
+}
+
+func decryptData(cfg *config.Config) (int, error) {
+	dir := cfg.GetDataDir()
+	if !crypt.Enabled(dir) {
+		return 0, fmt.Errorf("data in %s is not encrypted", dir)
+	}
+	store, err := openStorage(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("initializing storage: %w", err)
+	}
+	defer store.UnregisterInstance()
+	if err := requireSoleInstance(store); err != nil {
+		return 0, err
+	}
+
+	count, err := store.Rekey(nil)
+	if err == nil {
+		err = crypt.Remove(dir)
+	}
+	if err != nil {
+		return count, fmt.Errorf("decrypting data (run today decrypt again to finish): %w", err)
+	}
+	return count, nil
+}
+
+// parseCryptArgs parses the flags shared by encrypt and decrypt and loads
+// the config.
+func parseCryptArgs(name, helpText string, args []string) *config.Config {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+
+	helpFlag := fs.Bool("help", false, "show help message")
+	fs.BoolVar(helpFlag, "h", false, "show help message (shorthand)")
+
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, helpText)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *helpFlag {
+		fmt.Print(helpText)
+		os.Exit(0)
+	}
+
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unknown arguments: %v\n\n", fs.Args())
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// requireSoleInstance fails if another today has the data open, since it
+// would keep writing files in the old form.
+func requireSoleInstance(store *storage.Storage) error {
+	others, err := store.RegisterInstance()
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		return fmt.Errorf("another today (pid %d) has the data open; close it first", others[0].PID)
+	}
+	return nil
+}
+
+// openStorage opens the configured data directory, unlocking it first if it
+// is encrypted.
+func openStorage(cfg *config.Config) (*storage.Storage, error) {
+	dir := cfg.GetDataDir()
+	if !crypt.Enabled(dir) {
+		return storage.New(dir)
+	}
+	key, err := unlockKey(cfg, dir)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewWithKey(dir, key)
+}
+
+// unlockKey unlocks the key file in dir, letting a typed passphrase be
+// retyped when it is wrong.
+func unlockKey(cfg *config.Config, dir string) (*crypt.Key, error) {
+	prompt := fmt.Sprintf("Passphrase for %s: ", dir)
+	pass, prompted, err := passphrase(cfg, prompt)
+	for attempt := 1; err == nil; attempt++ {
+		var key *crypt.Key
+		key, err = crypt.Unlock(dir, pass)
+		if err == nil {
+			return key, nil
+		}
+		if !errors.Is(err, crypt.ErrWrongPassphrase) || !prompted || attempt == maxPassphraseAttempts {
+			break
+		}
+		fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+		pass, err = promptPassphrase(prompt)
+	}
+	return nil, err
+}
+
+// newPassphrase asks for the passphrase to encrypt with. Typed passphrases
+// are asked for twice.
+func newPassphrase(cfg *config.Config) (string, error) {
+	pass, prompted, err := passphrase(cfg, "New passphrase: ")
+	if err != nil || !prompted {
+		return pass, err
+	}
+	again, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != pass {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return pass, nil
+}
+
+// passphrase returns the passphrase from TODAY_PASSPHRASE, the configured
+// passphrase command or the terminal, in that order. prompted reports
+// whether it was typed.
+func passphrase(cfg *config.Config, prompt string) (pass string, prompted bool, err error) {
+	if pass := os.Getenv(passphraseEnv); pass != "" {
+		return pass, false, nil
+	}
+	if command := strings.TrimSpace(cfg.Encryption.PassphraseCommand); command != "" {
+		pass, err := runPassphraseCommand(command)
+		return pass, false, err
+	}
+	pass, err = promptPassphrase(prompt)
+	return pass, true, err
+}
+
+func runPassphraseCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("passphrase command failed: %v: %s", err, msg)
+		}
+		return "", fmt.Errorf("passphrase command failed: %w", err)
+	}
+	pass := strings.TrimRight(string(out), "\r\n")
+	if pass == "" {
+		return "", fmt.Errorf("passphrase command printed nothing")
+	}
+	return pass, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	fd := os.Stdin.Fd()
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("data is encrypted: set %s or encryption.passphrase_command, or run today in a terminal", passphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	if len(pass) == 0 {
+		return "", fmt.Errorf("passphrase must not be empty")
+	}
+	return string(pass), nil
+}
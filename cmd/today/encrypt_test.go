@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"today/internal/config"
+	"today/internal/crypt"
+	"today/internal/storage"
+)
+
+func TestEncryptDataResumes(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{DataDir: dir}
+	t.Setenv(passphraseEnv, "passphrase")
+
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddTask("Call Acme Corp", "clients", storage.PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	// A file that cannot be read without a key stops the first run after
+	// the key file was written but before tasks.json was converted.
+	blocker := filepath.Join(dir, "b.json")
+	if err := os.WriteFile(blocker, []byte("today-encrypted:v1:AAAA\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := encryptData(cfg); err == nil || !strings.Contains(err.Error(), "run today encrypt again") {
+		t.Fatalf("encryptData with an unreadable file = %v, want an error", err)
+	}
+	if !crypt.Enabled(dir) {
+		t.Fatal("key file not written before the failure")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "tasks.json")); !strings.Contains(string(data), "Acme") {
+		t.Fatal("tasks.json converted before the failure; the test no longer stops partway")
+	}
+
+	os.Remove(blocker)
+	count, err := encryptData(cfg)
+	if err != nil {
+		t.Fatalf("encryptData again: %v", err)
+	}
+	if count == 0 {
+		t.Error("encryptData again converted no files")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "tasks.json")); strings.Contains(string(data), "Acme") {
+		t.Error("tasks.json still in plaintext")
+	}
+
+	reopened, err := openStorage(cfg)
+	if err != nil {
+		t.Fatalf("openStorage: %v", err)
+	}
+	tasks, err := reopened.LoadTasks()
+	if err != nil || len(tasks.Tasks) != 1 {
+		t.Fatalf("LoadTasks = %v, %v; want the task", tasks, err)
+	}
+
+	if _, err := encryptData(cfg); err == nil || !strings.Contains(err.Error(), "already encrypted") {
+		t.Errorf("encryptData on encrypted data = %v, want already encrypted", err)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"today/internal/config"
 	"today/internal/fsutil"
 	"today/internal/reports"
-	"today/internal/storage"
 )
 
 // exportHelpText is the help message for the export subcommand.
@@ -123,7 +122,7 @@ func runExport(args []string) {
 		os.Exit(1)
 	}
 
-	store, err := storage.New(cfg.GetDataDir())
+	store, err := openStorage(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
 		os.Exit(1)
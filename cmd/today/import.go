@@ -10,7 +10,6 @@ import (
 
 	"today/internal/config"
 	"today/internal/importer"
-	"today/internal/storage"
 )
 
 // importHelpText is the help message for the import subcommand.
@@ -43,14 +42,14 @@ FIELD MAPPING:
     Todoist:
       - CONTENT → task text
       - PRIORITY: 1,2 → high, 3 → medium, 4 → low
-      - DATE → due date
+      - DATE → due date (a time of day is kept, read in the TIMEZONE column)
       - Notes are skipped
 
     Taskwarrior:
       - description → task text
       - project → project
       - priority: H → high, M → medium, L → low
-      - due → due date
+      - due → due date (times other than local midnight are kept)
       - status: completed → marks task as done
       - Deleted tasks are skipped
 
@@ -158,9 +157,14 @@ func runImportDryRun(imp importer.Importer, file *os.File) {
 		if task.Priority != "" {
 			details = append(details, string(task.Priority))
 		}
-		if task.DueDate != nil {
+		if task.DueDate != nil && task.DueTimed {
+			details = append(details, task.DueDate.Format("2006-01-02 15:04 MST"))
+		} else if task.DueDate != nil {
 			details = append(details, task.DueDate.Format("2006-01-02"))
 		}
+		for _, tag := range task.Tags {
+			details = append(details, "#"+tag)
+		}
 		if task.Done {
 			details = append(details, "done")
 		}
@@ -188,7 +192,7 @@ func runImportActual(imp importer.Importer, file *os.File) {
 		os.Exit(1)
 	}
 
-	store, err := storage.New(cfg.GetDataDir())
+	store, err := openStorage(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
 		os.Exit(1)
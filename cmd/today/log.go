@@ -0,0 +1,123 @@
+// Package main is the entry point for the today application.
+// This file contains the log subcommand handler.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"today/internal/config"
+)
+
+// logHelpText is the help message for the log subcommand.
+const logHelpText = `today log - Show the activity journal
+
+USAGE:
+    today log [OPTIONS]
+
+OPTIONS:
+    -d, --days N     Show changes from the last N days (default: 7)
+    -t, --type TYPE  Only show changes to task, habit, timer, ...
+    --json           Print the raw journal entries, one JSON object per line
+    -h, --help       Show this help message
+
+DESCRIPTION:
+    Every change made in the app or by a command is appended to
+    ~/.today/activity.jsonl with the time, the item, and the item as it was
+    before and after the change. This command prints that history, oldest
+    first. The same list is shown in the app with L.
+
+EXAMPLES:
+    # What changed this week
+    today log
+
+    # Tasks touched today
+    today log --days 1 --type task
+
+    # Full entries for scripting
+    today log --days 30 --json
+`
+
+// runLog handles the "today log" subcommand.
+func runLog(args []string) {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+
+	daysFlag := fs.Int("days", 7, "show changes from the last N days")
+	fs.IntVar(daysFlag, "d", 7, "days to show (shorthand)")
+
+	typeFlag := fs.String("type", "", "only show changes to this item type")
+	fs.StringVar(typeFlag, "t", "", "item type (shorthand)")
+
+	jsonFlag := fs.Bool("json", false, "print raw journal entries")
+
+	helpFlag := fs.Bool("help", false, "show help message")
+	fs.BoolVar(helpFlag, "h", false, "show help message (shorthand)")
+
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, logHelpText)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *helpFlag {
+		fmt.Print(logHelpText)
+		os.Exit(0)
+	}
+
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unknown arguments: %v\n\n", fs.Args())
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *daysFlag < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --days must be at least 1")
+		os.Exit(1)
+	}
+
+	// Load config and storage
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+		os.Exit(1)
+	}
+
+	since := time.Now().AddDate(0, 0, -*daysFlag)
+	entries, err := store.LoadActivity(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading activity: %v\n", err)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	shown := 0
+	for _, entry := range entries {
+		if *typeFlag != "" && entry.ItemType != *typeFlag {
+			continue
+		}
+		shown++
+		if *jsonFlag {
+			if err := enc.Encode(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(1)
+			}
+			continue
+		}
+		fmt.Printf("%s  %-18s %s\n", entry.Time.Local().Format("2006-01-02 15:04"), entry.Summary(), entry.ItemName)
+	}
+
+	if shown == 0 && !*jsonFlag {
+		fmt.Printf("No changes in the last %d days.\n", *daysFlag)
+	}
+}
@@ -6,9 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"today/internal/config"
-	"today/internal/storage"
 	"today/internal/sync"
 	"today/internal/ui"
 )
@@ -40,8 +40,16 @@ COMMANDS:
     import           Import tasks from other apps
     import todoist   Import from Todoist CSV backup
     import taskwarrior  Import from Taskwarrior JSON
+    archive          Move old completed tasks to monthly archive files
+    log              Show recent changes from the activity journal
+    encrypt          Encrypt the data with a passphrase
+    decrypt          Store encrypted data as plain JSON again
+    vacation START [END]  Pause habit streaks from START to END
+    vacation --off   End the vacation
 
 OPTIONS:
+    --profile NAME   Use a profile from the config file (also TODAY_PROFILE);
+                     works with every command
     -h, --help       Show this help message
     -v, --version    Show version information
 
@@ -60,6 +68,9 @@ KEYBINDINGS:
         Tab          Switch between panes
         1, 2, 3      Jump to specific pane
         ?            Show help overlay
+        T            Open trash (r restore, x purge)
+        L            Show recent activity
+        P            Switch profile
         Ctrl+Z       Undo last action
         Ctrl+Y       Redo
         q            Quit
@@ -67,8 +78,18 @@ KEYBINDINGS:
     Tasks Pane:
         j/k, ↓/↑     Navigate
         a            Add task
+        e            Edit task (text, project, tags, priority, due, start, repeat)
         d/Space      Toggle done
-        x            Delete task
+        x            Delete task (moves it to the trash)
+        o            Show/hide checklist
+        A            Add checklist items
+        #            Filter by tag (#words in a new task become tags)
+        n            Edit notes in $VISUAL / $EDITOR
+        b            Mark as blocked by another task
+        K/J          Move task up/down (ux.task_sort: manual)
+        t            Start/stop the timer on the task (tracked time shows as ◷)
+        z            Snooze: hide the task until tomorrow (again to push further)
+        H            Show/hide tasks whose start date is still ahead
         g/G          Go to top/bottom
 
     Timer Pane:
@@ -79,7 +100,13 @@ KEYBINDINGS:
     Habits Pane:
         j/k, ↓/↑     Navigate
         a            Add habit
+        e            Edit habit (name, icon, frequency, target)
         d/Space      Toggle today's completion
+        +/-          Log one more/less towards today's target
+        s            Skip today (keeps the streak, shown as ⊘)
+        p            Pause habit (hidden, streak frozen)
+        A            Archive habit (hidden, history kept)
+        I            List paused and archived habits to restore them
         x            Delete habit
 
 DATA STORAGE:
@@ -87,10 +114,16 @@ DATA STORAGE:
         tasks.json   - Your tasks
         habits.json  - Habits and completion logs
         timer.json   - Time tracking entries
+        trash.json   - Deleted tasks and habits (purged after 30 days)
+        archive/     - Completed tasks archived by month
+        activity.jsonl - Journal of every change (today log)
+    After "today encrypt" the files are encrypted and the passphrase is asked
+    for at startup (or read from TODAY_PASSPHRASE).
 
 CONFIGURATION:
     Optional config file: ~/.config/today/config.yaml
-    See documentation for configuration options.
+    See documentation for configuration options. Named profiles under
+    "profiles:" keep separate data; switch between them in the app with P.
 
 EXAMPLES:
     # Start the app
@@ -108,6 +141,18 @@ EXAMPLES:
     # Generate weekly report as JSON
     today export --weekly --format json
 
+    # Archive tasks completed more than 30 days ago
+    today archive
+
+    # Show what changed this week
+    today log
+
+    # Keep habit streaks over the holidays
+    today vacation 2025-12-24 2026-01-02
+
+    # Use the work profile
+    today --profile work
+
     # Show version
     today --version
 
@@ -118,6 +163,14 @@ For more information, visit: https://github.com/yourusername/today
 `
 
 func main() {
+	// --profile applies to every command, so take it out before dispatching
+	args, err := extractProfile(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+
 	// Check for subcommands first (before flag parsing)
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
@@ -136,6 +189,21 @@ func main() {
 		case "import":
 			runImport(os.Args[2:])
 			return
+		case "archive":
+			runArchive(os.Args[2:])
+			return
+		case "log":
+			runLog(os.Args[2:])
+			return
+		case "encrypt":
+			runEncrypt(os.Args[2:])
+			return
+		case "decrypt":
+			runDecrypt(os.Args[2:])
+			return
+		case "vacation":
+			runVacation(os.Args[2:])
+			return
 		}
 	}
 
@@ -181,8 +249,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Run the TUI until it quits without switching to another profile
+	for {
+		next := runApp(cfg)
+		if next == "" {
+			return
+		}
+		if cfg, err = config.LoadProfile(next); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// runApp runs the TUI on the data of cfg's profile. It returns the profile
+// picked in the app to switch to, or "" when the app was quit.
+func runApp(cfg *config.Config) string {
 	// Initialize storage with configured data directory
-	store, err := storage.New(cfg.GetDataDir())
+	store, err := openStorage(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
 		os.Exit(1)
@@ -214,6 +298,26 @@ func main() {
 		}
 	}
 
+	// Archive old completed tasks if the policy is enabled
+	if cfg.Archive.Auto {
+		if _, err := store.ArchiveTasks(cfg.Archive.AfterDays); err != nil {
+			// Log warning but continue - archiving can be retried next start
+			fmt.Fprintf(os.Stderr, "Warning: archiving failed: %v\n", err)
+		}
+	}
+
+	// Purge deleted items past their retention period
+	if _, err := store.PurgeTrash(cfg.Trash.RetentionDays); err != nil {
+		// Log warning but continue - purging can be retried next start
+		fmt.Fprintf(os.Stderr, "Warning: purging trash failed: %v\n", err)
+	}
+
+	// Drop activity journal entries past their retention period
+	if _, err := store.PruneActivity(cfg.Activity.RetentionDays); err != nil {
+		// Log warning but continue - pruning can be retried next start
+		fmt.Fprintf(os.Stderr, "Warning: pruning activity journal failed: %v\n", err)
+	}
+
 	// Create styles from theme config
 	styles := ui.NewStylesFromTheme(&cfg.Theme)
 
@@ -223,16 +327,59 @@ func main() {
 		ConfirmDeletions:      cfg.UX.ConfirmDeletions,
 		ShowOnboarding:        cfg.UX.ShowOnboarding,
 		NarrowLayoutThreshold: cfg.UX.NarrowLayoutThreshold,
+		ManualSort:            cfg.ManualTaskSort(),
+		TrashRetentionDays:    cfg.Trash.RetentionDays,
+		Profile:               cfg.Profile,
+		Profiles:              cfg.ProfileNames(),
 	}
 
 	// Run the TUI with optional GitSync for status display
-	if err := ui.RunWithSync(store, styles, appCfg, gitSync); err != nil {
+	next, err := ui.RunWithSync(store, styles, appCfg, gitSync)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running app: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Flush any pending git commits before exit
+	// Flush any pending git commits before exit or switching profiles
 	if gitSync != nil {
 		gitSync.Flush()
 	}
+	return next
+}
+
+// extractProfile removes a --profile NAME (or --profile=NAME) option from
+// args and selects that profile for the rest of the run through
+// TODAY_PROFILE, so every subcommand loads it with config.Load.
+func extractProfile(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		var name string
+		switch {
+		case arg == "--profile" || arg == "-profile":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s needs a profile name", arg)
+			}
+			i++
+			name = args[i]
+		case strings.HasPrefix(arg, "--profile="):
+			name = strings.TrimPrefix(arg, "--profile=")
+		case strings.HasPrefix(arg, "-profile="):
+			name = strings.TrimPrefix(arg, "-profile=")
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+		if name == "" {
+			return nil, fmt.Errorf("--profile needs a profile name")
+		}
+		if err := os.Setenv(config.ProfileEnv, name); err != nil {
+			return nil, err
+		}
+	}
+	return rest, nil
 }
@@ -0,0 +1,113 @@
+// Package main is the entry point for the today application.
+// This file contains the vacation subcommand handler.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"today/internal/config"
+	"today/internal/storage"
+)
+
+// vacationHelpText is the help message for the vacation subcommand.
+const vacationHelpText = `today vacation - Pause habit streaks while you are away
+
+USAGE:
+    today vacation [OPTIONS]
+    today vacation START [END]
+
+OPTIONS:
+    --off          End the vacation
+    -h, --help     Show this help message
+
+DESCRIPTION:
+    Sets the vacation to the days from START to END (YYYY-MM-DD, both
+    included; END defaults to START). On those days every habit is excused:
+    missing it does not break its streak and reports do not expect it.
+    Without arguments, shows the current vacation.
+
+    Single habits can be skipped for today with s in the habits pane.
+
+EXAMPLES:
+    # Away over the holidays
+    today vacation 2025-12-24 2026-01-02
+
+    # Back early
+    today vacation --off
+`
+
+// runVacation handles the "today vacation" subcommand.
+func runVacation(args []string) {
+	fs := flag.NewFlagSet("vacation", flag.ExitOnError)
+
+	offFlag := fs.Bool("off", false, "end the vacation")
+
+	helpFlag := fs.Bool("help", false, "show help message")
+	fs.BoolVar(helpFlag, "h", false, "show help message (shorthand)")
+
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, vacationHelpText)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *helpFlag {
+		fmt.Print(vacationHelpText)
+		os.Exit(0)
+	}
+
+	if fs.NArg() > 2 || (*offFlag && fs.NArg() > 0) {
+		fmt.Fprintf(os.Stderr, "Error: unexpected arguments: %v\n\n", fs.Args())
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Load config and storage
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := openStorage(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case *offFlag:
+		if err := store.SetVacation(nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error ending vacation: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Vacation ended")
+
+	case fs.NArg() > 0:
+		vacation := &storage.DateRange{Start: fs.Arg(0), End: fs.Arg(0)}
+		if fs.NArg() == 2 {
+			vacation.End = fs.Arg(1)
+		}
+		if err := store.SetVacation(vacation); err != nil {
+			fmt.Fprintf(os.Stderr, "Error setting vacation: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ On vacation from %s to %s; habit streaks are paused\n", vacation.Start, vacation.End)
+
+	default:
+		habits, err := store.LoadHabits()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading habits: %v\n", err)
+			os.Exit(1)
+		}
+		if habits.Vacation == nil {
+			fmt.Println("No vacation set.")
+			return
+		}
+		fmt.Printf("Vacation from %s to %s\n", habits.Vacation.Start, habits.Vacation.End)
+	}
+}
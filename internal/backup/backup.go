@@ -3,6 +3,7 @@
 package backup
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"today/internal/crypt"
 	"today/internal/fsutil"
 )
 
@@ -23,7 +25,11 @@ const (
 )
 
 // Data files that are backed up.
-var dataFiles = []string{"tasks.json", "habits.json", "timer.json"}
+var dataFiles = []string{"tasks.json", "habits.json", "timer.json", "trash.json"}
+
+// archiveDir holds archived tasks, one file per month (archive/tasks-2025-01.json).
+// Every archive file is backed up along with dataFiles.
+const archiveDir = "archive"
 
 // Manager handles backup and restore operations.
 type Manager struct {
@@ -80,7 +86,12 @@ func (m *Manager) Create() (string, error) {
 	var copiedFiles []string
 	stats := make(map[string]int)
 
-	for _, filename := range dataFiles {
+	files, err := m.backupFiles()
+	if err != nil {
+		_ = os.RemoveAll(backupPath)
+		return "", err
+	}
+	for _, filename := range files {
 		srcPath := filepath.Join(m.dataDir, filename)
 		dstPath := filepath.Join(backupPath, filename)
 
@@ -96,12 +107,12 @@ func (m *Manager) Create() (string, error) {
 			return "", fmt.Errorf("failed to copy %s: %w", filename, err)
 		}
 
-		copiedFiles = append(copiedFiles, filename)
+		copiedFiles = append(copiedFiles, filepath.ToSlash(filename))
 
 		// Gather stats
 		count, err := countItems(srcPath, filename)
 		if err == nil {
-			stats[statsKeyForFile(filename)] = count
+			stats[statsKeyForFile(filename)] += count
 		}
 	}
 
@@ -202,6 +213,10 @@ func (m *Manager) Restore(name string) error {
 
 	// Restore files
 	for _, filename := range manifest.Files {
+		filename = filepath.FromSlash(filename)
+		if !filepath.IsLocal(filename) {
+			return fmt.Errorf("backup %s lists a file outside the data directory: %q", name, filename)
+		}
 		srcPath := filepath.Join(backupPath, filename)
 		dstPath := filepath.Join(m.dataDir, filename)
 
@@ -218,7 +233,7 @@ func (m *Manager) Restore(name string) error {
 
 	// Validate restored files
 	for _, filename := range manifest.Files {
-		dstPath := filepath.Join(m.dataDir, filename)
+		dstPath := filepath.Join(m.dataDir, filepath.FromSlash(filename))
 		if err := validateJSON(dstPath); err != nil {
 			return fmt.Errorf("restored file %s is invalid (safety backup: %s): %w", filename, safetyName, err)
 		}
@@ -332,11 +347,36 @@ func validateBackupName(name string) error {
 	return nil
 }
 
+// backupFiles returns the data-relative files to back up: dataFiles and the
+// task archives.
+func (m *Manager) backupFiles() ([]string, error) {
+	files := append([]string{}, dataFiles...)
+	entries, err := os.ReadDir(filepath.Join(m.dataDir, archiveDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read archive directory: %w", err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && isArchiveFile(name) {
+			files = append(files, filepath.Join(archiveDir, name))
+		}
+	}
+	return files, nil
+}
+
+// isArchiveFile reports whether name is a monthly archive rather than one of
+// its .bak copies or temp files.
+func isArchiveFile(name string) bool {
+	return strings.HasPrefix(name, "tasks-") && strings.HasSuffix(name, ".json")
+}
+
 func copyFileAtomic(src, dst string) error {
 	data, err := os.ReadFile(src)
 	if err != nil {
 		return err
 	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
 	return fsutil.WriteFileAtomic(dst, data, 0600)
 }
 
@@ -367,6 +407,10 @@ func validateJSON(path string) error {
 		}
 		return err
 	}
+	// Encrypted files are checked when they are opened with the passphrase.
+	if crypt.IsSealed(bytes.TrimSpace(data)) {
+		return nil
+	}
 
 	var v interface{}
 	return json.Unmarshal(data, &v)
@@ -384,6 +428,9 @@ func countItems(path, filename string) (int, error) {
 		return 0, err
 	}
 
+	if filepath.Dir(filename) == archiveDir {
+		filename = "tasks.json"
+	}
 	switch filename {
 	case "tasks.json":
 		if tasks, ok := result["tasks"].([]interface{}); ok {
@@ -397,6 +444,10 @@ func countItems(path, filename string) (int, error) {
 		if entries, ok := result["entries"].([]interface{}); ok {
 			return len(entries), nil
 		}
+	case "trash.json":
+		if items, ok := result["items"].([]interface{}); ok {
+			return len(items), nil
+		}
 	}
 
 	return 0, nil
@@ -404,6 +455,9 @@ func countItems(path, filename string) (int, error) {
 
 // statsKeyForFile returns the stats key for a given filename.
 func statsKeyForFile(filename string) string {
+	if filepath.Dir(filename) == archiveDir {
+		return "archived_tasks"
+	}
 	switch filename {
 	case "tasks.json":
 		return "tasks"
@@ -411,6 +465,8 @@ func statsKeyForFile(filename string) string {
 		return "habits"
 	case "timer.json":
 		return "timer_entries"
+	case "trash.json":
+		return "trash_items"
 	default:
 		return filename
 	}
@@ -46,6 +46,18 @@ func createTestData(t *testing.T, dataDir string) {
 		},
 	}
 	writeTestJSON(t, filepath.Join(dataDir, "timer.json"), timer)
+
+	// Create trash.json
+	trash := map[string]interface{}{
+		"items": []map[string]interface{}{
+			{
+				"kind":       "task",
+				"deleted_at": "2025-12-15T12:00:00Z",
+				"task":       map[string]interface{}{"id": "t_3", "text": "Task 3"},
+			},
+		},
+	}
+	writeTestJSON(t, filepath.Join(dataDir, "trash.json"), trash)
 }
 
 // writeTestJSON writes JSON to a file for testing.
@@ -225,6 +237,47 @@ func TestManager_Restore(t *testing.T) {
 	}
 }
 
+// TestManager_RestoreArchive tests that archived tasks survive a backup and
+// restore round trip.
+func TestManager_RestoreArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestData(t, tmpDir)
+	archivePath := filepath.Join(tmpDir, "archive", "tasks-2025-01.json")
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeTestJSON(t, archivePath, map[string]interface{}{
+		"tasks": []map[string]interface{}{
+			{"id": "t_old", "text": "January report", "done": true},
+		},
+	})
+
+	manager := NewManager(tmpDir, "1.0.0")
+	name, err := manager.Create()
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	info, err := manager.GetBackup(name)
+	if err != nil {
+		t.Fatalf("GetBackup() error: %v", err)
+	}
+	if info.Stats["archived_tasks"] != 1 {
+		t.Errorf("Expected 1 archived task in stats, got %d", info.Stats["archived_tasks"])
+	}
+
+	if err := os.RemoveAll(filepath.Join(tmpDir, "archive")); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Restore(name); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+
+	restored := readTestJSON(t, archivePath)
+	if tasks, _ := restored["tasks"].([]interface{}); len(tasks) != 1 {
+		t.Errorf("Expected the archived task after restore, got %v", restored)
+	}
+}
+
 // TestManager_RestoreLatest tests restoring the most recent backup.
 func TestManager_RestoreLatest(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -3,8 +3,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"today/internal/fsutil"
@@ -31,6 +34,78 @@ type Config struct {
 
 	// Notifications configures desktop notifications
 	Notifications NotificationConfig `yaml:"notifications,omitempty"`
+
+	// Archive configures moving old completed tasks out of tasks.json
+	Archive ArchiveConfig `yaml:"archive,omitempty"`
+
+	// Trash configures how long deleted tasks and habits are kept
+	Trash TrashConfig `yaml:"trash,omitempty"`
+
+	// Activity configures how long the activity journal keeps entries
+	Activity ActivityConfig `yaml:"activity,omitempty"`
+
+	// Encryption configures how the passphrase for encrypted data is found
+	Encryption EncryptionConfig `yaml:"encryption,omitempty"`
+
+	// Profiles are named sets of data with their own data directory and,
+	// optionally, their own theme and sync settings
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty"`
+
+	// Profile is the name of the profile applied by LoadProfile, or "" for
+	// the default settings
+	Profile string `yaml:"-"`
+}
+
+// ProfileEnv selects the profile used by Load, like the --profile flag.
+const ProfileEnv = "TODAY_PROFILE"
+
+// DefaultProfile names the settings outside profiles when switching.
+const DefaultProfile = "default"
+
+// ProfileConfig overrides settings for one named profile. Unset theme and
+// sync fields keep the values from the rest of the file.
+type ProfileConfig struct {
+	// DataDir holds the profile's data
+	DataDir string `yaml:"data_dir,omitempty"` // default: data_dir followed by "-<name>"
+
+	// Theme overrides colors for this profile
+	Theme ThemeConfig `yaml:"theme,omitempty"`
+
+	// Sync overrides git sync settings for this profile
+	Sync SyncConfig `yaml:"sync,omitempty"`
+}
+
+// profileNamePattern keeps profile names usable in directory names.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// EncryptionConfig defines where the passphrase for encrypted data comes
+// from when TODAY_PASSPHRASE is not set.
+type EncryptionConfig struct {
+	// PassphraseCommand is run with the shell and its output used as the
+	// passphrase (e.g. "pass show today"); without it the passphrase is
+	// asked for at startup
+	PassphraseCommand string `yaml:"passphrase_command,omitempty"`
+}
+
+// TrashConfig defines the retention policy for deleted items.
+type TrashConfig struct {
+	// RetentionDays is how many days deleted items stay restorable
+	RetentionDays int `yaml:"retention_days,omitempty"` // default: 30
+}
+
+// ActivityConfig defines the retention policy for the activity journal.
+type ActivityConfig struct {
+	// RetentionDays is how many days journal entries are kept; 0 keeps all
+	RetentionDays int `yaml:"retention_days,omitempty"` // default: 365
+}
+
+// ArchiveConfig defines the archiving policy for completed tasks.
+type ArchiveConfig struct {
+	// Auto archives old completed tasks each time the app starts
+	Auto bool `yaml:"auto,omitempty"`
+
+	// AfterDays is how many days completed tasks stay in tasks.json
+	AfterDays int `yaml:"after_days,omitempty"` // default: 30
 }
 
 // NotificationConfig defines desktop notification settings.
@@ -95,6 +170,9 @@ type KeysConfig struct {
 	Pane1    string `yaml:"pane_1,omitempty"`    // default: "1"
 	Pane2    string `yaml:"pane_2,omitempty"`    // default: "2"
 	Pane3    string `yaml:"pane_3,omitempty"`    // default: "3"
+	Trash    string `yaml:"trash,omitempty"`     // default: "T"
+	Activity string `yaml:"activity,omitempty"`  // default: "L"
+	Profiles string `yaml:"profiles,omitempty"`  // default: "P"
 
 	// Navigation keys
 	Up     string `yaml:"up,omitempty"`     // default: "k,up"
@@ -103,14 +181,32 @@ type KeysConfig struct {
 	Bottom string `yaml:"bottom,omitempty"` // default: "G"
 
 	// Task keys
-	AddTask    string `yaml:"add_task,omitempty"`    // default: "a"
-	ToggleTask string `yaml:"toggle_task,omitempty"` // default: "d,enter,space"
-	DeleteTask string `yaml:"delete_task,omitempty"` // default: "x"
+	AddTask          string `yaml:"add_task,omitempty"`           // default: "a"
+	ToggleTask       string `yaml:"toggle_task,omitempty"`        // default: "d,enter,space"
+	DeleteTask       string `yaml:"delete_task,omitempty"`        // default: "x"
+	EditTask         string `yaml:"edit_task,omitempty"`          // default: "e"
+	ExpandChecklist  string `yaml:"expand_checklist,omitempty"`   // default: "o"
+	AddChecklistItem string `yaml:"add_checklist_item,omitempty"` // default: "A"
+	FilterTag        string `yaml:"filter_tag,omitempty"`         // default: "#"
+	EditNotes        string `yaml:"edit_notes,omitempty"`         // default: "n"
+	BlockTask        string `yaml:"block_task,omitempty"`         // default: "b"
+	MoveTaskUp       string `yaml:"move_task_up,omitempty"`       // default: "K,shift+up"
+	MoveTaskDown     string `yaml:"move_task_down,omitempty"`     // default: "J,shift+down"
+	TrackTask        string `yaml:"track_task,omitempty"`         // default: "t"
+	SnoozeTask       string `yaml:"snooze_task,omitempty"`        // default: "z"
+	ToggleDeferred   string `yaml:"toggle_deferred,omitempty"`    // default: "H"
 
 	// Habit keys
-	AddHabit    string `yaml:"add_habit,omitempty"`    // default: "a"
-	ToggleHabit string `yaml:"toggle_habit,omitempty"` // default: "d,enter,space"
-	DeleteHabit string `yaml:"delete_habit,omitempty"` // default: "x"
+	AddHabit       string `yaml:"add_habit,omitempty"`       // default: "a"
+	EditHabit      string `yaml:"edit_habit,omitempty"`      // default: "e"
+	ToggleHabit    string `yaml:"toggle_habit,omitempty"`    // default: "d,enter,space"
+	DeleteHabit    string `yaml:"delete_habit,omitempty"`    // default: "x"
+	IncrementHabit string `yaml:"increment_habit,omitempty"` // default: "+,="
+	DecrementHabit string `yaml:"decrement_habit,omitempty"` // default: "-"
+	SkipHabit      string `yaml:"skip_habit,omitempty"`      // default: "s"
+	PauseHabit     string `yaml:"pause_habit,omitempty"`     // default: "p"
+	ArchiveHabit   string `yaml:"archive_habit,omitempty"`   // default: "A"
+	InactiveHabits string `yaml:"inactive_habits,omitempty"` // default: "I"
 
 	// Timer keys
 	ToggleTimer string `yaml:"toggle_timer,omitempty"` // default: "space,enter"
@@ -136,6 +232,10 @@ type UXConfig struct {
 
 	// NarrowLayoutThreshold is the terminal width below which to use stacked layout
 	NarrowLayoutThreshold int `yaml:"narrow_layout_threshold,omitempty"` // default: 80
+
+	// TaskSort orders the task list: "auto" (priority, due date) or "manual"
+	// (user-arranged with the move keys or by dragging)
+	TaskSort string `yaml:"task_sort,omitempty"` // default: "auto"
 }
 
 // Default returns the default configuration.
@@ -156,6 +256,7 @@ func Default() *Config {
 			ConfirmDeletions:      true,
 			ShowOnboarding:        true,
 			NarrowLayoutThreshold: 80,
+			TaskSort:              "auto",
 		},
 		Sync: SyncConfig{
 			Enabled:       false, // Disabled by default
@@ -170,6 +271,16 @@ func Default() *Config {
 			TimerMilestones: nil,   // No milestones by default
 			Sound:           false, // No sound by default
 		},
+		Archive: ArchiveConfig{
+			Auto:      false, // Archive only on request by default
+			AfterDays: 30,
+		},
+		Trash: TrashConfig{
+			RetentionDays: 30,
+		},
+		Activity: ActivityConfig{
+			RetentionDays: 365,
+		},
 	}
 }
 
@@ -206,21 +317,28 @@ func configPath() string {
 	return filepath.Join(dir, "config.yaml")
 }
 
-// Load reads configuration from disk, merging with defaults.
+// Load reads configuration from disk, merging with defaults, and applies
+// the profile named by TODAY_PROFILE if it is set.
 // If no config file exists, returns default configuration.
 func Load() (*Config, error) {
+	return LoadProfile(os.Getenv(ProfileEnv))
+}
+
+// LoadProfile reads configuration from disk like Load and applies the named
+// profile. An empty name or DefaultProfile applies none.
+func LoadProfile(name string) (*Config, error) {
 	cfg := Default()
 
 	path := configPath()
 	if path == "" {
-		return cfg, nil
+		return cfg, cfg.applyProfile(name, nil)
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// No config file, use defaults
-			return cfg, nil
+			return cfg, cfg.applyProfile(name, nil)
 		}
 		return nil, err
 	}
@@ -237,9 +355,72 @@ func Load() (*Config, error) {
 	// Merge user config with defaults (presence-aware for booleans/slices)
 	cfg.mergeFromYAML(&userCfg, &doc)
 
+	if err := cfg.applyProfile(name, &doc); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// ProfileNames returns DefaultProfile followed by the configured profiles in
+// alphabetical order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles)+1)
+	for name := range c.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
+// applyProfile overlays the named profile onto c. Sync booleans are only
+// taken from the profile when doc shows they are set there.
+func (c *Config) applyProfile(name string, doc *yaml.Node) error {
+	if name == "" || name == DefaultProfile {
+		return nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q: no profiles are configured", name)
+		}
+		return fmt.Errorf("unknown profile %q (configured: %s)", name, strings.Join(c.ProfileNames()[1:], ", "))
+	}
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, - and _", name)
+	}
+	c.Profile = name
+
+	if profile.DataDir != "" {
+		c.DataDir = profile.DataDir
+	} else {
+		c.DataDir = strings.TrimRight(c.DataDir, `/\`) + "-" + name
+	}
+
+	var overlay Config
+	overlay.Theme = profile.Theme
+	overlay.Sync.CommitMessage = profile.Sync.CommitMessage
+	c.mergeNonEmpty(&overlay)
+
+	has := func(key string) bool {
+		return yamlHasPath(doc, "profiles", name, "sync", key)
+	}
+	if has("enabled") {
+		c.Sync.Enabled = profile.Sync.Enabled
+	}
+	if has("auto_commit") {
+		c.Sync.AutoCommit = profile.Sync.AutoCommit
+	}
+	if has("auto_push") {
+		c.Sync.AutoPush = profile.Sync.AutoPush
+	}
+	if has("pull_on_startup") {
+		c.Sync.PullOnStartup = profile.Sync.PullOnStartup
+	}
+	return nil
+}
+
 // mergeNonEmpty applies non-empty values from other to c.
 // It intentionally does not touch booleans or slices (those require presence-aware merging).
 func (c *Config) mergeNonEmpty(other *Config) {
@@ -271,6 +452,15 @@ func (c *Config) mergeNonEmpty(other *Config) {
 	if other.Keys.Help != "" {
 		c.Keys.Help = other.Keys.Help
 	}
+	if other.Keys.Trash != "" {
+		c.Keys.Trash = other.Keys.Trash
+	}
+	if other.Keys.Activity != "" {
+		c.Keys.Activity = other.Keys.Activity
+	}
+	if other.Keys.Profiles != "" {
+		c.Keys.Profiles = other.Keys.Profiles
+	}
 	if other.Keys.NextPane != "" {
 		c.Keys.NextPane = other.Keys.NextPane
 	}
@@ -304,12 +494,66 @@ func (c *Config) mergeNonEmpty(other *Config) {
 	if other.Keys.DeleteTask != "" {
 		c.Keys.DeleteTask = other.Keys.DeleteTask
 	}
+	if other.Keys.EditTask != "" {
+		c.Keys.EditTask = other.Keys.EditTask
+	}
+	if other.Keys.ExpandChecklist != "" {
+		c.Keys.ExpandChecklist = other.Keys.ExpandChecklist
+	}
+	if other.Keys.AddChecklistItem != "" {
+		c.Keys.AddChecklistItem = other.Keys.AddChecklistItem
+	}
+	if other.Keys.FilterTag != "" {
+		c.Keys.FilterTag = other.Keys.FilterTag
+	}
+	if other.Keys.EditNotes != "" {
+		c.Keys.EditNotes = other.Keys.EditNotes
+	}
+	if other.Keys.BlockTask != "" {
+		c.Keys.BlockTask = other.Keys.BlockTask
+	}
+	if other.Keys.MoveTaskUp != "" {
+		c.Keys.MoveTaskUp = other.Keys.MoveTaskUp
+	}
+	if other.Keys.MoveTaskDown != "" {
+		c.Keys.MoveTaskDown = other.Keys.MoveTaskDown
+	}
+	if other.Keys.TrackTask != "" {
+		c.Keys.TrackTask = other.Keys.TrackTask
+	}
+	if other.Keys.SnoozeTask != "" {
+		c.Keys.SnoozeTask = other.Keys.SnoozeTask
+	}
+	if other.Keys.ToggleDeferred != "" {
+		c.Keys.ToggleDeferred = other.Keys.ToggleDeferred
+	}
 	if other.Keys.AddHabit != "" {
 		c.Keys.AddHabit = other.Keys.AddHabit
 	}
+	if other.Keys.EditHabit != "" {
+		c.Keys.EditHabit = other.Keys.EditHabit
+	}
 	if other.Keys.ToggleHabit != "" {
 		c.Keys.ToggleHabit = other.Keys.ToggleHabit
 	}
+	if other.Keys.IncrementHabit != "" {
+		c.Keys.IncrementHabit = other.Keys.IncrementHabit
+	}
+	if other.Keys.DecrementHabit != "" {
+		c.Keys.DecrementHabit = other.Keys.DecrementHabit
+	}
+	if other.Keys.SkipHabit != "" {
+		c.Keys.SkipHabit = other.Keys.SkipHabit
+	}
+	if other.Keys.PauseHabit != "" {
+		c.Keys.PauseHabit = other.Keys.PauseHabit
+	}
+	if other.Keys.ArchiveHabit != "" {
+		c.Keys.ArchiveHabit = other.Keys.ArchiveHabit
+	}
+	if other.Keys.InactiveHabits != "" {
+		c.Keys.InactiveHabits = other.Keys.InactiveHabits
+	}
 	if other.Keys.DeleteHabit != "" {
 		c.Keys.DeleteHabit = other.Keys.DeleteHabit
 	}
@@ -339,6 +583,9 @@ func (c *Config) mergeNonEmpty(other *Config) {
 	if other.UX.NarrowLayoutThreshold > 0 {
 		c.UX.NarrowLayoutThreshold = other.UX.NarrowLayoutThreshold
 	}
+	if other.UX.TaskSort != "" {
+		c.UX.TaskSort = other.UX.TaskSort
+	}
 
 	// Sync strings (presence-aware in mergeFromYAML)
 	if other.Sync.CommitMessage != "" {
@@ -349,6 +596,31 @@ func (c *Config) mergeNonEmpty(other *Config) {
 	if other.Notifications.HabitReminder != "" {
 		c.Notifications.HabitReminder = other.Notifications.HabitReminder
 	}
+
+	// Archive ints (presence-aware in mergeFromYAML)
+	if other.Archive.AfterDays > 0 {
+		c.Archive.AfterDays = other.Archive.AfterDays
+	}
+
+	// Trash ints
+	if other.Trash.RetentionDays > 0 {
+		c.Trash.RetentionDays = other.Trash.RetentionDays
+	}
+
+	// Activity ints (presence-aware in mergeFromYAML)
+	if other.Activity.RetentionDays > 0 {
+		c.Activity.RetentionDays = other.Activity.RetentionDays
+	}
+
+	// Encryption strings
+	if other.Encryption.PassphraseCommand != "" {
+		c.Encryption.PassphraseCommand = other.Encryption.PassphraseCommand
+	}
+
+	// Profiles are applied by name in LoadProfile
+	if len(other.Profiles) > 0 {
+		c.Profiles = other.Profiles
+	}
 }
 
 func (c *Config) mergeFromYAML(other *Config, doc *yaml.Node) {
@@ -404,6 +676,15 @@ func (c *Config) mergeFromYAML(other *Config, doc *yaml.Node) {
 	if yamlHasPath(doc, "notifications", "timer_milestones") {
 		c.Notifications.TimerMilestones = other.Notifications.TimerMilestones
 	}
+
+	if yamlHasPath(doc, "archive", "auto") {
+		c.Archive.Auto = other.Archive.Auto
+	}
+
+	// Zero is meaningful here: it keeps the whole journal.
+	if yamlHasPath(doc, "activity", "retention_days") && other.Activity.RetentionDays >= 0 {
+		c.Activity.RetentionDays = other.Activity.RetentionDays
+	}
 }
 
 func yamlHasPath(doc *yaml.Node, path ...string) bool {
@@ -483,3 +764,9 @@ func (c *Config) GetDataDir() string {
 	}
 	return defaultDataDir()
 }
+
+// ManualTaskSort reports whether the task list uses the user's manual order.
+// Any value other than "manual" means automatic sorting.
+func (c *Config) ManualTaskSort() bool {
+	return strings.EqualFold(strings.TrimSpace(c.UX.TaskSort), "manual")
+}
@@ -86,6 +86,53 @@ theme:
 	}
 }
 
+func TestLoad_ActivityRetention(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+	configDir := filepath.Join(tempDir, "today")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	if cfg := Default(); cfg.Activity.RetentionDays != 365 {
+		t.Errorf("default Activity.RetentionDays = %d, want 365", cfg.Activity.RetentionDays)
+	}
+
+	for _, tt := range []struct {
+		content string
+		want    int
+	}{
+		{"activity:\n  retention_days: 90\n", 90},
+		{"activity:\n  retention_days: 0\n", 0}, // keeps the whole journal
+		{"trash:\n  retention_days: 7\n", 365},
+	} {
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.Activity.RetentionDays != tt.want {
+			t.Errorf("%q: Activity.RetentionDays = %d, want %d", tt.content, cfg.Activity.RetentionDays, tt.want)
+		}
+	}
+}
+
+func TestManualTaskSort(t *testing.T) {
+	cfg := Default()
+	if cfg.ManualTaskSort() {
+		t.Error("default task sort should be automatic")
+	}
+
+	other := &Config{UX: UXConfig{TaskSort: "Manual"}}
+	cfg.mergeNonEmpty(other)
+	if !cfg.ManualTaskSort() {
+		t.Errorf("TaskSort %q should select manual sorting", cfg.UX.TaskSort)
+	}
+}
+
 func TestMerge(t *testing.T) {
 	base := Default()
 	override := &Config{
@@ -289,3 +336,70 @@ func TestSave(t *testing.T) {
 		t.Errorf("loaded Theme.Primary = %q, want #SAVED", loaded.Theme.Primary)
 	}
 }
+
+func TestLoadProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+	t.Setenv(ProfileEnv, "")
+
+	configDir := filepath.Join(tempDir, "today")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	configContent := `
+data_dir: /data/today
+theme:
+  primary: "#FF0000"
+sync:
+  enabled: true
+  auto_push: true
+profiles:
+  work:
+    data_dir: /data/work
+    theme:
+      accent: "#0000FF"
+    sync:
+      auto_push: false
+  personal: {}
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := LoadProfile("work")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.Profile != "work" || cfg.DataDir != "/data/work" {
+		t.Errorf("Profile = %q, DataDir = %q; want work, /data/work", cfg.Profile, cfg.DataDir)
+	}
+	if cfg.Theme.Primary != "#FF0000" || cfg.Theme.Accent != "#0000FF" {
+		t.Errorf("Theme = %+v, want primary kept and accent overridden", cfg.Theme)
+	}
+	if !cfg.Sync.Enabled || cfg.Sync.AutoPush {
+		t.Errorf("Sync = %+v, want enabled kept and auto_push overridden", cfg.Sync)
+	}
+
+	// Without a data_dir the profile gets a sibling of the main one.
+	t.Setenv(ProfileEnv, "personal")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DataDir != "/data/today-personal" || !cfg.Sync.AutoPush {
+		t.Errorf("DataDir = %q, AutoPush = %v; want /data/today-personal, true", cfg.DataDir, cfg.Sync.AutoPush)
+	}
+
+	if got := cfg.ProfileNames(); len(got) != 3 || got[0] != DefaultProfile || got[1] != "personal" || got[2] != "work" {
+		t.Errorf("ProfileNames() = %v", got)
+	}
+
+	cfg, err = LoadProfile(DefaultProfile)
+	if err != nil || cfg.DataDir != "/data/today" || cfg.Profile != "" {
+		t.Errorf("default profile: DataDir = %q, Profile = %q, err = %v", cfg.DataDir, cfg.Profile, err)
+	}
+
+	if _, err := LoadProfile("missing"); err == nil {
+		t.Error("LoadProfile() should reject an unknown profile")
+	}
+}
@@ -0,0 +1,219 @@
+// Package crypt provides passphrase-based encryption of the data files.
+// A data directory is encrypted when it holds a key file recording the salt
+// and key derivation parameters; the passphrase itself is never stored.
+// Files are sealed with AES-256-GCM under a key derived with PBKDF2-SHA256.
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"today/internal/fsutil"
+)
+
+// KeyFile is the name of the key file in the data directory. It holds no
+// secrets and is synced with the data, so other machines can unlock it.
+const KeyFile = "encryption.json"
+
+const (
+	keyFileVersion = 1
+	keyLen         = 32 // AES-256
+	saltLen        = 16
+	checkText      = "today"
+)
+
+// sealedPrefix starts every sealed file or line; the rest is base64 of the
+// nonce followed by the ciphertext.
+var sealedPrefix = []byte("today-encrypted:v1:")
+
+// iterations is the PBKDF2 work factor for new key files. Tests lower it.
+var iterations = 600_000
+
+var (
+	// ErrLocked is returned when encrypted data is read without a key.
+	ErrLocked = errors.New("data is encrypted; a passphrase is needed to open it")
+
+	// ErrWrongPassphrase is returned by Unlock for a passphrase that does
+	// not match the key file.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+// Key seals and opens data files. A nil *Key leaves data in plaintext.
+type Key struct {
+	aead cipher.AEAD
+}
+
+// keyFile is the on-disk form of KeyFile.
+type keyFile struct {
+	Version    int    `json:"version"`
+	Cipher     string `json:"cipher"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Check      []byte `json:"check"` // checkText sealed with the key
+}
+
+// Enabled reports whether the data in dataDir is encrypted.
+func Enabled(dataDir string) bool {
+	_, err := os.Stat(filepath.Join(dataDir, KeyFile))
+	return err == nil
+}
+
+// Create sets up encryption for dataDir with a new salt and returns the key
+// for passphrase. It fails if the directory already has a key file.
+func Create(dataDir, passphrase string) (*Key, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+	if Enabled(dataDir) {
+		return nil, fmt.Errorf("data in %s is already encrypted", dataDir)
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	kf := keyFile{
+		Version:    keyFileVersion,
+		Cipher:     "aes-256-gcm",
+		KDF:        "pbkdf2-sha256",
+		Iterations: iterations,
+		Salt:       salt,
+	}
+	key, err := deriveKey(passphrase, kf)
+	if err != nil {
+		return nil, err
+	}
+	if kf.Check, err = key.Seal([]byte(checkText)); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("serialize %s: %w", KeyFile, err)
+	}
+	if err := fsutil.WriteFileAtomic(filepath.Join(dataDir, KeyFile), data, 0600); err != nil {
+		return nil, fmt.Errorf("write %s: %w", KeyFile, err)
+	}
+	return key, nil
+}
+
+// Unlock derives the key for dataDir from passphrase, checking it against the
+// key file.
+func Unlock(dataDir, passphrase string) (*Key, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", KeyFile, err)
+	}
+	var kf keyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", KeyFile, err)
+	}
+	if kf.Version > keyFileVersion || kf.Cipher != "aes-256-gcm" || kf.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("%s uses an unsupported format; upgrade today to open it", KeyFile)
+	}
+	key, err := deriveKey(passphrase, kf)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := key.Open(kf.Check); err != nil || string(check) != checkText {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// Remove deletes the key file once every file has been decrypted.
+func Remove(dataDir string) error {
+	err := os.Remove(filepath.Join(dataDir, KeyFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func deriveKey(passphrase string, kf keyFile) (*Key, error) {
+	if kf.Iterations < 1 || len(kf.Salt) == 0 {
+		return nil, fmt.Errorf("%s is missing key parameters", KeyFile)
+	}
+	raw, err := pbkdf2.Key(sha256.New, passphrase, kf.Salt, kf.Iterations, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{aead: aead}, nil
+}
+
+// IsSealed reports whether data was produced by Seal.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, sealedPrefix)
+}
+
+// Seal encrypts plaintext into a single line of text.
+func (k *Key) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(plaintext)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := k.aead.Seal(nonce, nonce, plaintext, nil)
+	out := make([]byte, len(sealedPrefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(out, sealedPrefix)
+	base64.StdEncoding.Encode(out[len(sealedPrefix):], sealed)
+	return out, nil
+}
+
+// Open decrypts data produced by Seal. Surrounding whitespace is ignored.
+func (k *Key) Open(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if !IsSealed(data) {
+		return nil, fmt.Errorf("data is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(data[len(sealedPrefix):]))
+	if err != nil || len(sealed) < k.aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted data is damaged")
+	}
+	nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	plaintext, err := k.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: data is damaged or was encrypted with another key")
+	}
+	return plaintext, nil
+}
+
+// Encode seals data with k, or returns it unchanged if k is nil.
+func (k *Key) Encode(data []byte) ([]byte, error) {
+	if k == nil {
+		return data, nil
+	}
+	sealed, err := k.Seal(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(sealed, '\n'), nil
+}
+
+// Decode opens sealed data with k. Plaintext is returned unchanged, so files
+// not yet converted stay readable; sealed data without a key is ErrLocked.
+func (k *Key) Decode(data []byte) ([]byte, error) {
+	if !IsSealed(bytes.TrimSpace(data)) {
+		return data, nil
+	}
+	if k == nil {
+		return nil, ErrLocked
+	}
+	return k.Open(data)
+}
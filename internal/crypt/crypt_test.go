@@ -0,0 +1,87 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func init() {
+	// Key derivation is deliberately slow; tests do not need the strength.
+	iterations = 1000
+}
+
+func TestCreateAndUnlock(t *testing.T) {
+	dir := t.TempDir()
+	if Enabled(dir) {
+		t.Fatal("fresh directory reported as encrypted")
+	}
+	key, err := Create(dir, "correct horse")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !Enabled(dir) {
+		t.Fatal("Create did not write the key file")
+	}
+	if _, err := Create(dir, "other"); err == nil {
+		t.Error("Create should refuse an already encrypted directory")
+	}
+
+	sealed, err := key.Encode([]byte(`{"tasks":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("tasks")) {
+		t.Fatalf("Encode did not seal the data: %q", sealed)
+	}
+
+	unlocked, err := Unlock(dir, "correct horse")
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	plain, err := unlocked.Decode(sealed)
+	if err != nil || string(plain) != `{"tasks":[]}` {
+		t.Errorf("Decode = %q, %v", plain, err)
+	}
+
+	if _, err := Unlock(dir, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Unlock with wrong passphrase = %v, want ErrWrongPassphrase", err)
+	}
+
+	if err := Remove(dir); err != nil || Enabled(dir) {
+		t.Errorf("Remove = %v, enabled %v", err, Enabled(dir))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	key, err := Create(t.TempDir(), "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := Create(t.TempDir(), "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, _ := key.Encode([]byte("secret"))
+
+	// Plaintext passes through, with or without a key.
+	var none *Key
+	if out, err := none.Decode([]byte("{}")); err != nil || string(out) != "{}" {
+		t.Errorf("nil key Decode(plaintext) = %q, %v", out, err)
+	}
+	if out, err := key.Decode([]byte("{}")); err != nil || string(out) != "{}" {
+		t.Errorf("Decode(plaintext) = %q, %v", out, err)
+	}
+	if _, err := none.Decode(sealed); !errors.Is(err, ErrLocked) {
+		t.Errorf("nil key Decode(sealed) = %v, want ErrLocked", err)
+	}
+	// The same passphrase with another salt is another key.
+	if _, err := other.Decode(sealed); err == nil {
+		t.Error("data opened with a different key")
+	}
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-4] ^= 1
+	if _, err := key.Decode(tampered); err == nil {
+		t.Error("tampered data was accepted")
+	}
+}
@@ -4,6 +4,7 @@ package importer
 
 import (
 	"io"
+	"strings"
 	"time"
 
 	"today/internal/storage"
@@ -22,7 +23,10 @@ type PreviewTask struct {
 	Project  string
 	Priority storage.Priority
 	DueDate  *time.Time
+	DueTimed bool   // DueDate carries a time of day (an exact deadline)
+	DueZone  string // IANA zone of a timed deadline, if known
 	Done     bool
+	Tags     []string // Taskwarrior tags / Todoist labels
 }
 
 // Importer defines the interface for import implementations.
@@ -53,3 +57,32 @@ func GetImporter(format string) Importer {
 func SupportedFormats() []string {
 	return []string{"todoist", "taskwarrior"}
 }
+
+// addTask adds an imported task to storage, keeping the time and zone of a
+// timed deadline.
+func addTask(store *storage.Storage, task PreviewTask) (*storage.Task, error) {
+	return store.CreateTask(storage.Task{
+		Text:     task.Text,
+		Project:  task.Project,
+		Priority: task.Priority,
+		DueDate:  task.DueDate,
+		DueTimed: task.DueTimed,
+		DueZone:  task.DueZone,
+		Tags:     task.Tags,
+	})
+}
+
+// sanitizeTag turns a tag or label from another app into a valid today tag:
+// lowercase, without a leading '#' or '@', with spaces and commas as dashes.
+func sanitizeTag(tag string) string {
+	tag = strings.TrimSpace(tag)
+	tag = strings.TrimLeft(tag, "#@")
+	tag = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', ',', '#':
+			return '-'
+		}
+		return r
+	}, tag)
+	return strings.ToLower(strings.Trim(tag, "-"))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"today/internal/storage"
 )
@@ -62,24 +63,33 @@ func TestTodoist_PriorityMapping(t *testing.T) {
 
 // TestTodoist_DateParsing tests various date formats.
 func TestTodoist_DateParsing(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
 	tests := []struct {
 		input    string
 		hasDate  bool
-		expected string // YYYY-MM-DD format
+		expected string // YYYY-MM-DD format, with HH:MM (UTC) for timed dates
 	}{
 		{"2025-12-20", true, "2025-12-20"},
 		{"Jan 2 2025", true, "2025-01-02"},
+		{"2025-12-20 14:00", true, "2025-12-20 05:00"},
+		{"Dec 20 2025 2:30 PM", true, "2025-12-20 05:30"},
+		{"2025-12-20T14:00:00Z", true, "2025-12-20 14:00"},
 		{"", false, ""},
 		{"invalid", false, ""},
 	}
 
 	for _, tc := range tests {
-		result := parseTodoistDate(tc.input)
+		result, timed := parseTodoistDate(tc.input, tokyo)
 		if tc.hasDate {
-			if result == nil {
-				t.Errorf("parseTodoistDate(%q) = nil, want date", tc.input)
-			} else if result.Format("2006-01-02") != tc.expected {
-				t.Errorf("parseTodoistDate(%q) = %s, want %s", tc.input, result.Format("2006-01-02"), tc.expected)
+			got := ""
+			if result != nil {
+				got = result.Format("2006-01-02")
+				if timed {
+					got = result.UTC().Format("2006-01-02 15:04")
+				}
+			}
+			if got != tc.expected {
+				t.Errorf("parseTodoistDate(%q) = %q, want %q", tc.input, got, tc.expected)
 			}
 		} else {
 			if result != nil {
@@ -89,6 +99,69 @@ func TestTodoist_DateParsing(t *testing.T) {
 	}
 }
 
+// TestTodoist_DueTimes tests that timed due dates keep their zone on import.
+func TestTodoist_DueTimes(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skip("zoneinfo not available")
+	}
+	csv := `TYPE,CONTENT,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE
+task,Board meeting,4,1,,,2025-12-20 14:00,en,Asia/Tokyo
+task,Pay invoice,4,1,,,2025-12-21,en,Asia/Tokyo`
+
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("storage.New() error: %v", err)
+	}
+	result, err := (&TodoistImporter{}).Import(strings.NewReader(csv), store)
+	if err != nil || result.Imported != 2 {
+		t.Fatalf("Import() = %+v, %v; want 2 imported", result, err)
+	}
+
+	tasks, _ := store.LoadTasks()
+	meeting, invoice := tasks.Tasks[0], tasks.Tasks[1]
+	want := time.Date(2025, 12, 20, 5, 0, 0, 0, time.UTC)
+	if !meeting.DueTimed || meeting.DueZone != "Asia/Tokyo" || !meeting.DueDate.Equal(want) {
+		t.Errorf("meeting due = %v (timed %v, zone %q), want %v in Asia/Tokyo",
+			meeting.DueDate, meeting.DueTimed, meeting.DueZone, want)
+	}
+	if invoice.DueTimed || invoice.DueDate.Format("2006-01-02") != "2025-12-21" {
+		t.Errorf("invoice due = %v (timed %v), want the all-day date 2025-12-21", invoice.DueDate, invoice.DueTimed)
+	}
+
+	// Each task is saved once, with its time, rather than added then edited
+	entries, _ := store.LoadActivity(time.Time{})
+	for _, e := range entries {
+		if e.Operation != "add" {
+			t.Errorf("activity has %q %s, want only adds", e.Operation, e.ItemName)
+		}
+	}
+	if len(entries) != 2 {
+		t.Errorf("activity has %d entries, want 2", len(entries))
+	}
+}
+
+// TestTodoist_UTCTimezone tests that an explicit UTC zone is kept rather
+// than read as a missing one.
+func TestTodoist_UTCTimezone(t *testing.T) {
+	csv := `TYPE,CONTENT,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE
+task,Standup,4,1,,,2025-12-22 09:00,en,UTC
+task,Lunch,4,1,,,2025-12-22 12:00,en, `
+
+	tasks, err := (&TodoistImporter{}).Preview(strings.NewReader(csv))
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("Preview() = %d tasks, %v; want 2", len(tasks), err)
+	}
+	standup, lunch := tasks[0], tasks[1]
+	want := time.Date(2025, 12, 22, 9, 0, 0, 0, time.UTC)
+	if !standup.DueTimed || standup.DueZone != "UTC" || !standup.DueDate.Equal(want) {
+		t.Errorf("standup due = %v (zone %q), want %v in UTC", standup.DueDate, standup.DueZone, want)
+	}
+	want = time.Date(2025, 12, 22, 12, 0, 0, 0, time.Local)
+	if lunch.DueZone != storage.LocalZoneName() || !lunch.DueDate.Equal(want) {
+		t.Errorf("lunch due = %v (zone %q), want %v in the local zone", lunch.DueDate, lunch.DueZone, want)
+	}
+}
+
 // TestTodoist_EmptyFile tests handling of empty CSV.
 func TestTodoist_EmptyFile(t *testing.T) {
 	importer := &TodoistImporter{}
@@ -280,6 +353,28 @@ func TestTaskwarrior_DateParsing(t *testing.T) {
 	}
 }
 
+// TestTaskwarrior_DueTimes tests that exact due times survive the import
+// while Taskwarrior's local-midnight due dates stay all-day.
+func TestTaskwarrior_DueTimes(t *testing.T) {
+	midnight := time.Date(2025, 12, 20, 0, 0, 0, 0, time.Local).UTC().Format("20060102T150405Z")
+	input := fmt.Sprintf(`[
+{"description":"Dentist","status":"pending","due":"20251220T133000Z"},
+{"description":"Renew domain","status":"pending","due":%q}
+]`, midnight)
+
+	tasks, err := (&TaskwarriorImporter{}).Preview(strings.NewReader(input))
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("Preview() = %d tasks, %v; want 2", len(tasks), err)
+	}
+	want := time.Date(2025, 12, 20, 13, 30, 0, 0, time.UTC)
+	if !tasks[0].DueTimed || !tasks[0].DueDate.Equal(want) {
+		t.Errorf("Dentist due = %v (timed %v), want %v", tasks[0].DueDate, tasks[0].DueTimed, want)
+	}
+	if tasks[1].DueTimed || tasks[1].DueDate.Format("2006-01-02") != "2025-12-20" {
+		t.Errorf("Renew domain due = %v (timed %v), want the all-day date 2025-12-20", tasks[1].DueDate, tasks[1].DueTimed)
+	}
+}
+
 // TestTaskwarrior_EmptyInput tests handling of empty input.
 func TestTaskwarrior_EmptyInput(t *testing.T) {
 	importer := &TaskwarriorImporter{}
@@ -397,3 +492,50 @@ task,Test task 2,4,1,,,,,`
 		t.Errorf("Expected 2 tasks in storage, got %d", len(tasks.Tasks))
 	}
 }
+
+// TestTodoist_Labels tests that Todoist labels become tags.
+func TestTodoist_Labels(t *testing.T) {
+	csv := `TYPE,CONTENT,PRIORITY,LABELS
+task,Email invoice @Client_A @admin,4,
+task,Plan sprint,4,"work, Deep Focus"
+task,@someday,4,`
+
+	importer := &TodoistImporter{}
+	tasks, err := importer.Preview(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Preview() error: %v", err)
+	}
+
+	if tasks[0].Text != "Email invoice" {
+		t.Errorf("Expected labels stripped from content, got %q", tasks[0].Text)
+	}
+	if got := strings.Join(tasks[0].Tags, ","); got != "client_a,admin" {
+		t.Errorf("Expected tags from @labels, got %q", got)
+	}
+	if got := strings.Join(tasks[1].Tags, ","); got != "work,deep-focus" {
+		t.Errorf("Expected tags from LABELS column, got %q", got)
+	}
+	if tasks[2].Text != "@someday" || len(tasks[2].Tags) != 0 {
+		t.Errorf("Label-only content should be kept as text, got %q %v", tasks[2].Text, tasks[2].Tags)
+	}
+}
+
+// TestTaskwarrior_Tags tests that Taskwarrior tags are imported.
+func TestTaskwarrior_Tags(t *testing.T) {
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	json := `[{"description":"Call dentist","status":"pending","tags":["phone","Health"]}]`
+
+	importer := &TaskwarriorImporter{}
+	if _, err := importer.Import(strings.NewReader(json), store); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+
+	tasks, _ := store.LoadTasks()
+	if got := strings.Join(tasks.Tasks[0].Tags, ","); got != "phone,health" {
+		t.Errorf("Expected tags phone,health, got %q", got)
+	}
+}
@@ -19,14 +19,15 @@ type TaskwarriorImporter struct{}
 
 // taskwarriorTask represents a task in Taskwarrior's JSON format.
 type taskwarriorTask struct {
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	Project     string `json:"project"`
-	Priority    string `json:"priority"`
-	Due         string `json:"due"`
-	Entry       string `json:"entry"`
-	End         string `json:"end"`
-	UUID        string `json:"uuid"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Project     string   `json:"project"`
+	Priority    string   `json:"priority"`
+	Due         string   `json:"due"`
+	Entry       string   `json:"entry"`
+	End         string   `json:"end"`
+	UUID        string   `json:"uuid"`
+	Tags        []string `json:"tags"`
 }
 
 // Name returns the importer name.
@@ -44,7 +45,7 @@ func (t *TaskwarriorImporter) Import(reader io.Reader, store *storage.Storage) (
 	result := &ImportResult{}
 
 	for _, task := range tasks {
-		addedTask, err := store.AddTask(task.Text, task.Project, task.Priority, task.DueDate)
+		addedTask, err := addTask(store, task)
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", task.Text, err))
 			continue
@@ -210,10 +211,21 @@ func previewFromTaskwarrior(tw taskwarriorTask) (PreviewTask, bool) {
 	}
 	task.Text = strings.TrimSpace(task.Text)
 
-	// Parse due date
+	for _, tag := range tw.Tags {
+		if tag = sanitizeTag(tag); tag != "" {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+
+	// Parse due date. Taskwarrior stores a plain due date as local midnight,
+	// so any other time of day is an exact deadline.
 	if tw.Due != "" {
 		if dueDate := parseTaskwarriorDate(tw.Due); dueDate != nil {
 			task.DueDate = dueDate
+			if h, m, s := dueDate.Clock(); h != 0 || m != 0 || s != 0 {
+				task.DueTimed = true
+				task.DueZone = storage.LocalZoneName()
+			}
 		}
 	}
 
@@ -236,7 +248,8 @@ func mapTaskwarriorPriority(priority string) storage.Priority {
 }
 
 // parseTaskwarriorDate parses Taskwarrior's date format.
-// Format: 20140928T211124Z (ISO 8601 basic format)
+// Format: 20140928T211124Z (ISO 8601 basic format, UTC). Timestamps without
+// the trailing Z are read as local wall-clock time.
 func parseTaskwarriorDate(dateStr string) *time.Time {
 	dateStr = strings.TrimSpace(dateStr)
 	if dateStr == "" {
@@ -253,7 +266,11 @@ func parseTaskwarriorDate(dateStr string) *time.Time {
 	}
 
 	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
+		loc := time.Local
+		if strings.HasSuffix(format, "Z") {
+			loc = time.UTC
+		}
+		if t, err := time.ParseInLocation(format, dateStr, loc); err == nil {
 			// Convert to local time
 			localTime := t.Local()
 			return &localTime
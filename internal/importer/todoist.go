@@ -30,7 +30,7 @@ func (t *TodoistImporter) Import(reader io.Reader, store *storage.Storage) (*Imp
 	result := &ImportResult{}
 
 	for _, task := range tasks {
-		_, err := store.AddTask(task.Text, task.Project, task.Priority, task.DueDate)
+		_, err := addTask(store, task)
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", task.Text, err))
 			continue
@@ -104,6 +104,17 @@ func (t *TodoistImporter) parseTasks(reader io.Reader) ([]PreviewTask, error) {
 			task.Text = strings.TrimSpace(record[idx])
 		}
 
+		// Labels: "@label" tokens inside CONTENT (classic export) and/or a
+		// LABELS column (comma separated)
+		task.Text, task.Tags = extractTodoistLabels(task.Text)
+		if idx, ok := colIndex["LABELS"]; ok && idx < len(record) {
+			for _, label := range strings.Split(record[idx], ",") {
+				if tag := sanitizeTag(label); tag != "" {
+					task.Tags = append(task.Tags, tag)
+				}
+			}
+		}
+
 		// Skip empty tasks
 		if task.Text == "" {
 			continue
@@ -119,10 +130,23 @@ func (t *TodoistImporter) parseTasks(reader io.Reader) ([]PreviewTask, error) {
 			task.Project = strings.TrimSpace(record[idx])
 		}
 
-		// Due date
+		// Due date, with its time read in the task's TIMEZONE when given
 		if idx, ok := colIndex["DATE"]; ok && idx < len(record) {
-			if dueDate := parseTodoistDate(record[idx]); dueDate != nil {
+			loc, zone := time.Local, storage.LocalZoneName()
+			if zi, ok := colIndex["TIMEZONE"]; ok && zi < len(record) {
+				// LoadLocation reads a blank name as UTC; blank means unset here
+				if name := strings.TrimSpace(record[zi]); name != "" {
+					if l, err := time.LoadLocation(name); err == nil {
+						loc, zone = l, l.String()
+					}
+				}
+			}
+			if dueDate, timed := parseTodoistDate(record[idx], loc); dueDate != nil {
 				task.DueDate = dueDate
+				if timed {
+					task.DueTimed = true
+					task.DueZone = zone
+				}
 			}
 		}
 
@@ -132,6 +156,25 @@ func (t *TodoistImporter) parseTasks(reader io.Reader) ([]PreviewTask, error) {
 	return tasks, nil
 }
 
+// extractTodoistLabels removes "@label" tokens from task content and returns
+// them as tags. If the content is nothing but labels it is kept as is.
+func extractTodoistLabels(content string) (string, []string) {
+	var words, tags []string
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && word[0] == '@' {
+			if tag := sanitizeTag(word); tag != "" {
+				tags = append(tags, tag)
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		return content, nil
+	}
+	return strings.Join(words, " "), tags
+}
+
 // mapTodoistPriority converts Todoist priority to our priority system.
 // Todoist: 1 = urgent (highest), 2 = high, 3 = medium, 4 = normal (lowest)
 // Our system: high, medium, low, none
@@ -150,11 +193,30 @@ func mapTodoistPriority(priority string) storage.Priority {
 	}
 }
 
-// parseTodoistDate parses various Todoist date formats.
-func parseTodoistDate(dateStr string) *time.Time {
+// parseTodoistDate parses various Todoist date formats. Dates with a time of
+// day are read in loc (unless they carry their own offset) and reported as
+// timed; plain dates are local calendar days.
+func parseTodoistDate(dateStr string, loc *time.Location) (*time.Time, bool) {
 	dateStr = strings.TrimSpace(dateStr)
 	if dateStr == "" {
-		return nil
+		return nil, false
+	}
+
+	if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
+		return &t, true
+	}
+	timedFormats := []string{
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04",
+		"Jan 2 2006 15:04",
+		"Jan 2 2006 3:04 PM",
+		"Jan 2, 2006 15:04",
+		"2 Jan 2006 15:04",
+	}
+	for _, format := range timedFormats {
+		if t, err := time.ParseInLocation(format, dateStr, loc); err == nil {
+			return &t, true
+		}
 	}
 
 	// Try various formats
@@ -170,9 +232,9 @@ func parseTodoistDate(dateStr string) *time.Time {
 
 	for _, format := range formats {
 		if t, err := time.ParseInLocation(format, dateStr, time.Local); err == nil {
-			return &t
+			return &t, false
 		}
 	}
 
-	return nil
+	return nil, false
 }
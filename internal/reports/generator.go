@@ -84,20 +84,73 @@ func (g *Generator) GenerateWeekly(startDate time.Time) (*WeeklyReport, error) {
 	}, nil
 }
 
+// loadTasks returns the live tasks plus the archived tasks that may have been
+// added or completed on or after start. Tasks deleted since start are taken
+// from the activity journal so the work they represent still counts; deleted
+// marks them.
+func (g *Generator) loadTasks(start time.Time) (tasks []storage.Task, deleted map[string]bool, err error) {
+	taskStore, err := g.store.LoadTasks()
+	if err != nil {
+		return nil, nil, err
+	}
+	archived, err := g.store.LoadArchivedTasks(start)
+	if err != nil {
+		return nil, nil, err
+	}
+	activity, err := g.store.LoadActivity(start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tasks = taskStore.Tasks
+	known := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		known[task.ID] = true
+	}
+	for _, task := range archived {
+		// An interrupted archive run can leave a task in both places.
+		if !known[task.ID] {
+			tasks = append(tasks, task)
+			known[task.ID] = true
+		}
+	}
+
+	// Walk the journal newest first so a task deleted twice (deleted,
+	// restored, deleted again) is counted as it was last seen.
+	deleted = make(map[string]bool)
+	for i := len(activity) - 1; i >= 0; i-- {
+		entry := activity[i]
+		if entry.Operation != "delete" {
+			continue
+		}
+		if task := entry.Task(); task != nil && !known[task.ID] {
+			tasks = append(tasks, *task)
+			known[task.ID] = true
+			deleted[task.ID] = true
+		}
+	}
+	return tasks, deleted, nil
+}
+
 // getTaskSummary returns task statistics for a date range.
 func (g *Generator) getTaskSummary(start, end time.Time) (TaskSummary, error) {
-	taskStore, err := g.store.LoadTasks()
+	tasks, deleted, err := g.loadTasks(start)
 	if err != nil {
 		return TaskSummary{}, err
 	}
 
 	var completed, pending []storage.Task
 	projectCounts := make(map[string]int)
-	addedCount := 0
-
-	for _, task := range taskStore.Tasks {
-		// Check if task was added in this period
-		if !task.CreatedAt.Before(start) && task.CreatedAt.Before(end) {
+	tagCounts := make(map[string]int)
+	addedCount, deferredCount := 0, 0
+	checklistDone, checklistTotal := 0, 0
+	// Tasks that only start after the period were not actionable in it.
+	lastMoment := end.Add(-time.Nanosecond)
+
+	for _, task := range tasks {
+		// Check if task was added in this period. Occurrences spawned by
+		// completing a recurring task are not new work, so they don't count.
+		if task.SpawnedFrom == "" && !task.CreatedAt.Before(start) && task.CreatedAt.Before(end) {
 			addedCount++
 		}
 
@@ -110,9 +163,20 @@ func (g *Generator) getTaskSummary(start, end time.Time) (TaskSummary, error) {
 					project = "General"
 				}
 				projectCounts[project]++
+				for _, tag := range task.Tags {
+					tagCounts[tag]++
+				}
 			}
+		} else if deleted[task.ID] {
+			// Deleted tasks are no longer pending work.
+			continue
+		} else if task.IsDeferred(lastMoment) {
+			deferredCount++
 		} else if !task.Done {
 			pending = append(pending, task)
+			done, total := task.ChecklistProgress()
+			checklistDone += done
+			checklistTotal += total
 		}
 	}
 
@@ -133,11 +197,30 @@ func (g *Generator) getTaskSummary(start, end time.Time) (TaskSummary, error) {
 		Pending:        pending,
 		CompletedCount: len(completed),
 		PendingCount:   len(pending),
+		DeferredCount:  deferredCount,
 		AddedCount:     addedCount,
 		ByProject:      byProject,
+		ByTag:          sortedTagCounts(tagCounts),
+		ChecklistDone:  checklistDone,
+		ChecklistTotal: checklistTotal,
 	}, nil
 }
 
+// sortedTagCounts converts tag counts to a slice, most used first (ties by name).
+func sortedTagCounts(counts map[string]int) []TagCount {
+	byTag := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		byTag = append(byTag, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(byTag, func(i, j int) bool {
+		if byTag[i].Count != byTag[j].Count {
+			return byTag[i].Count > byTag[j].Count
+		}
+		return byTag[i].Tag < byTag[j].Tag
+	})
+	return byTag
+}
+
 // getTimeSummary returns time tracking statistics for a date range.
 func (g *Generator) getTimeSummary(start, end time.Time) (TimeSummary, error) {
 	timerStore, err := g.store.LoadTimer()
@@ -146,6 +229,7 @@ func (g *Generator) getTimeSummary(start, end time.Time) (TimeSummary, error) {
 	}
 
 	projectDurations := make(map[string]time.Duration)
+	taskDurations := make(map[string]time.Duration)
 	var total time.Duration
 
 	for _, entry := range timerStore.Entries {
@@ -154,6 +238,9 @@ func (g *Generator) getTimeSummary(start, end time.Time) (TimeSummary, error) {
 		if overlap > 0 {
 			total += overlap
 			projectDurations[entry.Project] += overlap
+			if entry.TaskID != "" {
+				taskDurations[entry.TaskID] += overlap
+			}
 		}
 	}
 
@@ -163,9 +250,17 @@ func (g *Generator) getTimeSummary(start, end time.Time) (TimeSummary, error) {
 		if overlap > 0 {
 			total += overlap
 			projectDurations[timerStore.Current.Project] += overlap
+			if timerStore.Current.TaskID != "" {
+				taskDurations[timerStore.Current.TaskID] += overlap
+			}
 		}
 	}
 
+	byTask, err := g.taskTimes(taskDurations, start)
+	if err != nil {
+		return TimeSummary{}, err
+	}
+
 	// Convert to sorted slice with percentages
 	byProject := make([]ProjectTime, 0, len(projectDurations))
 	for project, duration := range projectDurations {
@@ -186,9 +281,48 @@ func (g *Generator) getTimeSummary(start, end time.Time) (TimeSummary, error) {
 	return TimeSummary{
 		Total:     total,
 		ByProject: byProject,
+		ByTask:    byTask,
 	}, nil
 }
 
+// taskTimes turns per-task durations into a list sorted by time (descending),
+// labelled with the task text. Tasks that can no longer be found are
+// labelled as deleted.
+func (g *Generator) taskTimes(durations map[string]time.Duration, start time.Time) ([]TaskTime, error) {
+	if len(durations) == 0 {
+		return []TaskTime{}, nil
+	}
+	tasks, _, err := g.loadTasks(start)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]storage.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	byTask := make([]TaskTime, 0, len(durations))
+	for id, duration := range durations {
+		task, ok := byID[id]
+		if !ok {
+			task = storage.Task{ID: id, Text: "(deleted task)"}
+		}
+		byTask = append(byTask, TaskTime{
+			TaskID:   id,
+			Text:     task.Text,
+			Project:  task.Project,
+			Duration: duration,
+		})
+	}
+	sort.Slice(byTask, func(i, j int) bool {
+		if byTask[i].Duration != byTask[j].Duration {
+			return byTask[i].Duration > byTask[j].Duration
+		}
+		return byTask[i].Text < byTask[j].Text
+	})
+	return byTask, nil
+}
+
 // getHabitSummary returns habit statistics for a specific date.
 func (g *Generator) getHabitSummary(date time.Time) (HabitSummary, error) {
 	habitStore, err := g.store.LoadHabits()
@@ -200,44 +334,64 @@ func (g *Generator) getHabitSummary(date time.Time) (HabitSummary, error) {
 	var statuses []HabitStatus
 	completedCount := 0
 
+	totalCount := 0
+
 	for _, habit := range habitStore.Habits {
+		// Paused and archived habits are not on that day's checklist
+		if !habit.ActiveOn(dateStr) {
+			continue
+		}
 		done := g.store.IsHabitDoneOnDate(habitStore, habit.ID, dateStr)
+		skipped := !done && g.store.IsHabitSkippedOnDate(habitStore, habit.ID, dateStr)
 		streak := g.store.GetHabitStreakAt(habitStore, habit.ID, date)
 
 		if done {
 			completedCount++
 		}
+		// Skipped habits are not expected that day
+		if !skipped {
+			totalCount++
+		}
 
-		statuses = append(statuses, HabitStatus{
-			ID:     habit.ID,
-			Name:   habit.Name,
-			Icon:   habit.Icon,
-			Done:   done,
-			Streak: streak,
-		})
+		status := HabitStatus{
+			ID:         habit.ID,
+			Name:       habit.Name,
+			Icon:       habit.Icon,
+			Done:       done,
+			Skipped:    skipped,
+			Streak:     streak,
+			StreakUnit: habit.StreakUnit(),
+		}
+		if habit.HasTarget() {
+			status.Value = g.store.HabitValueOnDate(habitStore, habit.ID, dateStr)
+			status.Target = habit.Target
+			status.Unit = habit.Unit
+		}
+		statuses = append(statuses, status)
 	}
 
 	rate := 0.0
-	if len(statuses) > 0 {
-		rate = float64(completedCount) / float64(len(statuses)) * 100
+	if totalCount > 0 {
+		rate = float64(completedCount) / float64(totalCount) * 100
 	}
 
 	return HabitSummary{
 		Habits:         statuses,
 		CompletedCount: completedCount,
-		TotalCount:     len(statuses),
+		TotalCount:     totalCount,
 		CompletionRate: rate,
 	}, nil
 }
 
 // getWeeklyTasks returns task statistics for a week.
 func (g *Generator) getWeeklyTasks(start, end time.Time) (WeeklyTasks, error) {
-	taskStore, err := g.store.LoadTasks()
+	tasks, _, err := g.loadTasks(start)
 	if err != nil {
 		return WeeklyTasks{}, err
 	}
 
 	projectCounts := make(map[string]int)
+	tagCounts := make(map[string]int)
 	totalCompleted := 0
 	totalAdded := 0
 	byDay := make([]DayTaskCount, 7)
@@ -251,9 +405,9 @@ func (g *Generator) getWeeklyTasks(start, end time.Time) (WeeklyTasks, error) {
 		}
 	}
 
-	for _, task := range taskStore.Tasks {
-		// Count added tasks
-		if !task.CreatedAt.Before(start) && task.CreatedAt.Before(end) {
+	for _, task := range tasks {
+		// Count added tasks (excluding spawned recurring occurrences)
+		if task.SpawnedFrom == "" && !task.CreatedAt.Before(start) && task.CreatedAt.Before(end) {
 			totalAdded++
 			dayIdx := dayIndexInRange(task.CreatedAt, start, 7)
 			if dayIdx >= 0 && dayIdx < 7 {
@@ -271,6 +425,9 @@ func (g *Generator) getWeeklyTasks(start, end time.Time) (WeeklyTasks, error) {
 					project = "General"
 				}
 				projectCounts[project]++
+				for _, tag := range task.Tags {
+					tagCounts[tag]++
+				}
 
 				dayIdx := dayIndexInRange(*task.CompletedAt, start, 7)
 				if dayIdx >= 0 && dayIdx < 7 {
@@ -296,6 +453,7 @@ func (g *Generator) getWeeklyTasks(start, end time.Time) (WeeklyTasks, error) {
 		TotalCompleted: totalCompleted,
 		TotalAdded:     totalAdded,
 		ByProject:      byProject,
+		ByTag:          sortedTagCounts(tagCounts),
 		ByDay:          byDay,
 	}, nil
 }
@@ -308,6 +466,7 @@ func (g *Generator) getWeeklyTime(start, end time.Time) (WeeklyTime, error) {
 	}
 
 	projectDurations := make(map[string]time.Duration)
+	taskDurations := make(map[string]time.Duration)
 	var total time.Duration
 	byDay := make([]DayTime, 7)
 
@@ -326,6 +485,9 @@ func (g *Generator) getWeeklyTime(start, end time.Time) (WeeklyTime, error) {
 		if overlap > 0 {
 			total += overlap
 			projectDurations[entry.Project] += overlap
+			if entry.TaskID != "" {
+				taskDurations[entry.TaskID] += overlap
+			}
 
 			// Split across days.
 			for i := 0; i < 7; i++ {
@@ -345,6 +507,9 @@ func (g *Generator) getWeeklyTime(start, end time.Time) (WeeklyTime, error) {
 		if overlap > 0 {
 			total += overlap
 			projectDurations[timerStore.Current.Project] += overlap
+			if timerStore.Current.TaskID != "" {
+				taskDurations[timerStore.Current.TaskID] += overlap
+			}
 
 			for i := 0; i < 7; i++ {
 				dayStart := start.AddDate(0, 0, i)
@@ -374,6 +539,11 @@ func (g *Generator) getWeeklyTime(start, end time.Time) (WeeklyTime, error) {
 		return byProject[i].Duration > byProject[j].Duration
 	})
 
+	byTask, err := g.taskTimes(taskDurations, start)
+	if err != nil {
+		return WeeklyTime{}, err
+	}
+
 	// Calculate daily average
 	dailyAvg := time.Duration(0)
 	if total > 0 {
@@ -384,6 +554,7 @@ func (g *Generator) getWeeklyTime(start, end time.Time) (WeeklyTime, error) {
 		Total:        total,
 		DailyAverage: dailyAvg,
 		ByProject:    byProject,
+		ByTask:       byTask,
 		ByDay:        byDay,
 	}, nil
 }
@@ -401,19 +572,33 @@ func (g *Generator) getWeeklyHabits(start, end time.Time) (WeeklyHabits, error)
 	weekEnd := end.Add(-time.Nanosecond)
 
 	for _, habit := range habitStore.Habits {
+		if !activeInWeek(habit, start) {
+			continue
+		}
 		daysCompleted := make([]bool, 7)
+		daysScheduled := make([]bool, 7)
+		daysSkipped := make([]bool, 7)
+		var daysValue []int
+		if habit.HasTarget() {
+			daysValue = make([]int, 7)
+		}
 
 		for i := 0; i < 7; i++ {
 			day := start.AddDate(0, 0, i)
 			dateStr := day.Format("2006-01-02")
 			done := g.store.IsHabitDoneOnDate(habitStore, habit.ID, dateStr)
 			daysCompleted[i] = done
+			daysScheduled[i] = habit.IsScheduledOn(day.Weekday())
+			daysSkipped[i] = g.store.IsHabitSkippedOnDate(habitStore, habit.ID, dateStr)
+			if daysValue != nil {
+				daysValue[i] = g.store.HabitValueOnDate(habitStore, habit.ID, dateStr)
+			}
 		}
 
-		expectedCount := expectedCountForWeek(habit, start)
+		expectedCount := expectedCountForWeek(habit, start, daysCompleted, daysSkipped)
 		totalExpected += expectedCount
 
-		completedCount := completedCountForWeek(habit, daysCompleted)
+		completedCount := completedCountForWeek(habit, start, daysCompleted)
 		totalCompleted += completedCount
 
 		rate := 0.0
@@ -426,10 +611,18 @@ func (g *Generator) getWeeklyHabits(start, end time.Time) (WeeklyHabits, error)
 			ID:             habit.ID,
 			Name:           habit.Name,
 			Icon:           habit.Icon,
+			Frequency:      habit.EffectiveFrequency(),
 			DaysCompleted:  daysCompleted,
+			DaysScheduled:  daysScheduled,
+			DaysSkipped:    daysSkipped,
 			CompletedCount: completedCount,
+			ExpectedCount:  expectedCount,
 			CompletionRate: rate,
 			Streak:         streak,
+			StreakUnit:     habit.StreakUnit(),
+			DaysValue:      daysValue,
+			Target:         habit.Target,
+			Unit:           habit.Unit,
 		})
 	}
 
@@ -522,93 +715,63 @@ func dayIndexInRange(t time.Time, start time.Time, days int) int {
 	return -1
 }
 
-func expectedCountForWeek(h storage.Habit, weekStart time.Time) int {
-	freq := h.Frequency
-	if freq == "" {
-		freq = storage.FrequencyDaily
+// expectedCountForWeek returns how often h is due in the week starting at
+// weekStart: once for weekly habits, otherwise once per scheduled day.
+// daysCompleted and daysSkipped hold one entry per day from weekStart; days
+// skipped without being done are not expected, and neither is a weekly habit
+// skipped in a week it was not done in.
+func expectedCountForWeek(h storage.Habit, weekStart time.Time, daysCompleted, daysSkipped []bool) int {
+	excused := func(i int) bool {
+		return i < len(daysSkipped) && daysSkipped[i] && (i >= len(daysCompleted) || !daysCompleted[i])
 	}
-
-	switch freq {
-	case storage.FrequencyWeekly:
-		return 1
-	case storage.FrequencyWeekdays:
-		count := 0
-		for i := 0; i < 7; i++ {
-			day := weekStart.AddDate(0, 0, i)
-			if day.Weekday() >= time.Monday && day.Weekday() <= time.Friday {
-				count++
+	if h.EffectiveFrequency() == storage.FrequencyWeekly {
+		for _, done := range daysCompleted {
+			if done {
+				return 1
 			}
 		}
-		return count
-	case storage.FrequencyCustom:
-		allowed := make(map[int]struct{}, len(h.CustomDays))
-		for _, d := range h.CustomDays {
-			if d >= 0 && d <= 6 {
-				allowed[d] = struct{}{}
+		for i := range daysSkipped {
+			if excused(i) {
+				return 0
 			}
 		}
-		count := 0
-		for i := 0; i < 7; i++ {
-			day := weekStart.AddDate(0, 0, i)
-			if _, ok := allowed[int(day.Weekday())]; ok {
-				count++
-			}
+		return 1
+	}
+	count := 0
+	for i := 0; i < 7; i++ {
+		if h.IsScheduledOn(weekStart.AddDate(0, 0, i).Weekday()) && !excused(i) {
+			count++
 		}
-		return count
-	default:
-		return 7
 	}
+	return count
 }
 
-func completedCountForWeek(h storage.Habit, daysCompleted []bool) int {
-	freq := h.Frequency
-	if freq == "" {
-		freq = storage.FrequencyDaily
+// activeInWeek reports whether the habit was tracked on any day of the week
+// from weekStart. Days it was paused or archived on count as skipped.
+func activeInWeek(h storage.Habit, weekStart time.Time) bool {
+	for i := 0; i < 7; i++ {
+		if h.ActiveOn(weekStart.AddDate(0, 0, i).Format("2006-01-02")) {
+			return true
+		}
 	}
+	return false
+}
 
-	switch freq {
-	case storage.FrequencyWeekly:
-		for _, done := range daysCompleted {
-			if done {
-				return 1
-			}
-		}
-		return 0
-	case storage.FrequencyWeekdays:
-		count := 0
-		for i, done := range daysCompleted {
-			day := time.Weekday(i) // i is offset from Sunday
-			if day >= time.Monday && day <= time.Friday && done {
-				count++
-			}
-		}
-		return count
-	case storage.FrequencyCustom:
-		allowed := make(map[int]struct{}, len(h.CustomDays))
-		for _, d := range h.CustomDays {
-			if d >= 0 && d <= 6 {
-				allowed[d] = struct{}{}
-			}
+// completedCountForWeek counts the completions in daysCompleted (one per day
+// from weekStart) that count towards expectedCountForWeek. Completions on
+// days the habit is not due on are left out.
+func completedCountForWeek(h storage.Habit, weekStart time.Time, daysCompleted []bool) int {
+	count := 0
+	for i, done := range daysCompleted {
+		if !done || !h.IsScheduledOn(weekStart.AddDate(0, 0, i).Weekday()) {
+			continue
 		}
-		count := 0
-		for i, done := range daysCompleted {
-			if !done {
-				continue
-			}
-			if _, ok := allowed[i]; ok {
-				count++
-			}
+		if h.EffectiveFrequency() == storage.FrequencyWeekly {
+			return 1
 		}
-		return count
-	default:
-		count := 0
-		for _, done := range daysCompleted {
-			if done {
-				count++
-			}
-		}
-		return count
+		count++
 	}
+	return count
 }
 
 // overlapDuration calculates how much of [entryStart, entryEnd] overlaps with [rangeStart, rangeEnd].
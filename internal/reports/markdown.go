@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"today/internal/storage"
 )
 
 // FormatDailyMarkdown formats a daily report as Markdown.
@@ -19,6 +21,9 @@ func FormatDailyMarkdown(report *DailyReport) string {
 	b.WriteString("## Tasks\n\n")
 	b.WriteString(fmt.Sprintf("- **Completed:** %d tasks\n", report.Tasks.CompletedCount))
 	b.WriteString(fmt.Sprintf("- **Pending:** %d tasks\n", report.Tasks.PendingCount))
+	if report.Tasks.DeferredCount > 0 {
+		b.WriteString(fmt.Sprintf("- **Scheduled for later:** %d tasks\n", report.Tasks.DeferredCount))
+	}
 	if report.Tasks.AddedCount > 0 {
 		b.WriteString(fmt.Sprintf("- **Added today:** %d tasks\n", report.Tasks.AddedCount))
 	}
@@ -26,6 +31,17 @@ func FormatDailyMarkdown(report *DailyReport) string {
 		b.WriteString(fmt.Sprintf("- **Top project:** %s (%d tasks)\n",
 			report.Tasks.ByProject[0].Project, report.Tasks.ByProject[0].Count))
 	}
+	if len(report.Tasks.ByTag) > 0 {
+		tags := make([]string, 0, len(report.Tasks.ByTag))
+		for _, tc := range report.Tasks.ByTag {
+			tags = append(tags, fmt.Sprintf("#%s (%d)", tc.Tag, tc.Count))
+		}
+		b.WriteString(fmt.Sprintf("- **By tag:** %s\n", strings.Join(tags, ", ")))
+	}
+	if report.Tasks.ChecklistTotal > 0 {
+		b.WriteString(fmt.Sprintf("- **Checklist progress:** %d/%d items\n",
+			report.Tasks.ChecklistDone, report.Tasks.ChecklistTotal))
+	}
 	b.WriteString("\n")
 
 	// Completed tasks list
@@ -36,7 +52,9 @@ func FormatDailyMarkdown(report *DailyReport) string {
 			if task.Project != "" {
 				projectTag = fmt.Sprintf(" `%s`", task.Project)
 			}
-			b.WriteString(fmt.Sprintf("- [x] %s%s\n", task.Text, projectTag))
+			b.WriteString(fmt.Sprintf("- [x] %s%s%s%s\n", task.Text, tagList(task), projectTag, checklistTag(task)))
+			writeChecklist(&b, task)
+			writeNotes(&b, task)
 		}
 		b.WriteString("\n")
 	}
@@ -54,7 +72,9 @@ func FormatDailyMarkdown(report *DailyReport) string {
 			if task.Project != "" {
 				projectTag = fmt.Sprintf(" `%s`", task.Project)
 			}
-			b.WriteString(fmt.Sprintf("- [ ] %s%s\n", task.Text, projectTag))
+			b.WriteString(fmt.Sprintf("- [ ] %s%s%s%s\n", task.Text, tagList(task), projectTag, checklistTag(task)))
+			writeChecklist(&b, task)
+			writeNotes(&b, task)
 		}
 		if len(report.Tasks.Pending) > limit {
 			b.WriteString(fmt.Sprintf("- ... and %d more\n", len(report.Tasks.Pending)-limit))
@@ -73,6 +93,12 @@ func FormatDailyMarkdown(report *DailyReport) string {
 					p.Project, formatDurationHuman(p.Duration), p.Percentage))
 			}
 		}
+		if len(report.Time.ByTask) > 0 {
+			b.WriteString("- **Tasks:**\n")
+			for _, t := range report.Time.ByTask {
+				b.WriteString(fmt.Sprintf("  - %s: %s\n", t.Text, formatDurationHuman(t.Duration)))
+			}
+		}
 	} else {
 		b.WriteString("_No time tracked today._\n")
 	}
@@ -85,14 +111,21 @@ func FormatDailyMarkdown(report *DailyReport) string {
 			report.Habits.CompletedCount, report.Habits.TotalCount, report.Habits.CompletionRate))
 		for _, h := range report.Habits.Habits {
 			checkmark := "✗"
-			if h.Done {
+			switch {
+			case h.Done:
 				checkmark = "✓"
+			case h.Skipped:
+				checkmark = "⊘"
+			}
+			progress := ""
+			if h.Target > 0 {
+				progress = " " + strings.TrimSpace(fmt.Sprintf("%d/%d %s", h.Value, h.Target, h.Unit))
 			}
 			streakInfo := ""
 			if h.Streak > 1 {
 				streakInfo = fmt.Sprintf(" (🔥%d)", h.Streak)
 			}
-			b.WriteString(fmt.Sprintf("- %s %s %s%s\n", h.Icon, h.Name, checkmark, streakInfo))
+			b.WriteString(fmt.Sprintf("- %s %s %s%s%s\n", h.Icon, h.Name, checkmark, progress, streakInfo))
 		}
 	} else {
 		b.WriteString("_No habits tracked._\n")
@@ -143,6 +176,18 @@ func FormatWeeklyMarkdown(report *WeeklyReport) string {
 		b.WriteString("\n")
 	}
 
+	// Time by task table
+	if len(report.Time.ByTask) > 0 {
+		b.WriteString("## Time by Task\n\n")
+		b.WriteString("| Task | Project | Time |\n")
+		b.WriteString("|------|---------|------|\n")
+		for _, t := range report.Time.ByTask {
+			b.WriteString(fmt.Sprintf("| %s | %s | %s |\n",
+				strings.ReplaceAll(t.Text, "|", "\\|"), t.Project, formatDurationHuman(t.Duration)))
+		}
+		b.WriteString("\n")
+	}
+
 	// Time by day
 	b.WriteString("## Time by Day\n\n")
 	b.WriteString("| Day | Time |\n")
@@ -175,10 +220,17 @@ func FormatWeeklyMarkdown(report *WeeklyReport) string {
 		// Build rows
 		for _, h := range report.Habits.Habits {
 			row := fmt.Sprintf("| %s %s |", h.Icon, h.Name)
-			for _, done := range h.DaysCompleted {
-				if done {
+			for i, done := range h.DaysCompleted {
+				switch {
+				case done:
 					row += " ✓ |"
-				} else {
+				case i < len(h.DaysSkipped) && h.DaysSkipped[i]:
+					row += " ⊘ |" // skipped or on vacation
+				case i < len(h.DaysValue) && h.DaysValue[i] > 0:
+					row += fmt.Sprintf(" %d/%d |", h.DaysValue[i], h.Target) // partial progress
+				case i < len(h.DaysScheduled) && !h.DaysScheduled[i]:
+					row += " · |" // not due that day
+				default:
 					row += " ✗ |"
 				}
 			}
@@ -200,7 +252,7 @@ func FormatWeeklyMarkdown(report *WeeklyReport) string {
 		b.WriteString("## Streaks\n\n")
 		for _, h := range report.Habits.Habits {
 			if h.Streak > 1 {
-				b.WriteString(fmt.Sprintf("- 🔥 %s: %d days\n", h.Name, h.Streak))
+				b.WriteString(fmt.Sprintf("- 🔥 %s: %d %s\n", h.Name, h.Streak, h.StreakUnit))
 			}
 		}
 		b.WriteString("\n")
@@ -213,6 +265,51 @@ func FormatWeeklyMarkdown(report *WeeklyReport) string {
 	return b.String()
 }
 
+// tagList returns a " #a #b" suffix for tagged tasks.
+func tagList(task storage.Task) string {
+	var b strings.Builder
+	for _, tag := range task.Tags {
+		b.WriteString(" #" + tag)
+	}
+	return b.String()
+}
+
+// checklistTag returns a " (done/total)" progress suffix for tasks with a checklist.
+func checklistTag(task storage.Task) string {
+	done, total := task.ChecklistProgress()
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d/%d)", done, total)
+}
+
+// writeChecklist writes a task's checklist items as a nested task list.
+func writeChecklist(b *strings.Builder, task storage.Task) {
+	for _, item := range task.Checklist {
+		mark := " "
+		if item.Done {
+			mark = "x"
+		}
+		b.WriteString(fmt.Sprintf("  - [%s] %s\n", mark, item.Text))
+	}
+}
+
+// writeNotes writes a task's notes as a blockquote nested under the task.
+func writeNotes(b *strings.Builder, task storage.Task) {
+	notes := strings.TrimSpace(task.Notes)
+	if notes == "" {
+		return
+	}
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			b.WriteString("  >\n")
+			continue
+		}
+		b.WriteString("  > " + line + "\n")
+	}
+}
+
 // formatDurationHuman formats a duration in a human-readable way.
 func formatDurationHuman(d time.Duration) string {
 	d = d.Round(time.Minute)
@@ -39,8 +39,8 @@ func setupTestData(t *testing.T, store *storage.Storage) {
 	}
 
 	// Add habits
-	store.AddHabit("Exercise", "🏃")
-	store.AddHabit("Reading", "📚")
+	store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.AddHabit("Reading", "📚", storage.FrequencyDaily)
 
 	// Toggle one habit for today
 	habits, _ := store.LoadHabits()
@@ -129,6 +129,167 @@ func TestGenerator_WeeklyReport(t *testing.T) {
 	}
 }
 
+// TestGenerator_WeeklyHabitsFollowFrequency tests that weekly habit stats
+// only count the days a habit is due.
+func TestGenerator_WeeklyHabitsFollowFrequency(t *testing.T) {
+	store := createTestStorage(t)
+	workout, _ := store.AddHabit("Workout", "🏋", storage.FrequencyDaily)
+	review, _ := store.AddHabit("Review", "📝", storage.FrequencyDaily)
+
+	// Week of Sunday 2025-03-02: workout on weekdays except Friday, plus
+	// Saturday; the weekly review on Wednesday.
+	sunday := time.Date(2025, 3, 2, 0, 0, 0, 0, time.Local)
+	habits, _ := store.LoadHabits()
+	habits.Habits[0].Frequency = storage.FrequencyWeekdays
+	habits.Habits[1].Frequency = storage.FrequencyWeekly
+	for _, offset := range []int{1, 2, 3, 4, 6} {
+		habits.Logs = append(habits.Logs, storage.HabitLog{HabitID: workout.ID, Date: sunday.AddDate(0, 0, offset).Format("2006-01-02")})
+	}
+	habits.Logs = append(habits.Logs, storage.HabitLog{HabitID: review.ID, Date: sunday.AddDate(0, 0, 3).Format("2006-01-02")})
+	if err := store.SaveHabits(habits); err != nil {
+		t.Fatalf("SaveHabits() error: %v", err)
+	}
+
+	report, err := NewGenerator(store).GenerateWeekly(sunday.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error: %v", err)
+	}
+
+	// Saturday does not make up for the missed Friday
+	w := report.Habits.Habits[0]
+	if w.CompletedCount != 4 || w.ExpectedCount != 5 || w.Streak != 0 || w.StreakUnit != "days" {
+		t.Errorf("weekdays habit = %d/%d streak %d %s, want 4/5 streak 0 days",
+			w.CompletedCount, w.ExpectedCount, w.Streak, w.StreakUnit)
+	}
+	if w.DaysScheduled[0] || !w.DaysScheduled[1] || w.DaysScheduled[6] {
+		t.Errorf("DaysScheduled = %v, want weekdays only", w.DaysScheduled)
+	}
+
+	r := report.Habits.Habits[1]
+	if r.CompletedCount != 1 || r.ExpectedCount != 1 || r.Streak != 1 || r.StreakUnit != "weeks" {
+		t.Errorf("weekly habit = %d/%d streak %d %s, want 1/1 streak 1 weeks",
+			r.CompletedCount, r.ExpectedCount, r.Streak, r.StreakUnit)
+	}
+
+	if report.Habits.TotalCompleted != 5 || report.Habits.TotalExpected != 6 {
+		t.Errorf("totals = %d/%d, want 5/6", report.Habits.TotalCompleted, report.Habits.TotalExpected)
+	}
+
+	md := FormatWeeklyMarkdown(report)
+	if !strings.Contains(md, "| 🏋 Workout | · | ✓ | ✓ | ✓ | ✓ | ✗ | ✓ |") {
+		t.Errorf("Expected days the habit is not due marked with ·, got:\n%s", md)
+	}
+}
+
+func TestGenerator_HabitTargets(t *testing.T) {
+	store := createTestStorage(t)
+	water, _ := store.CreateHabit(storage.Habit{Name: "Water", Icon: "🥤", Target: 8, Unit: "glasses"})
+
+	// Week of Sunday 2025-03-02: the target reached on Monday, partial
+	// progress on Tuesday
+	sunday := time.Date(2025, 3, 2, 0, 0, 0, 0, time.Local)
+	store.SetHabitValueOnDate(water.ID, "2025-03-03", 8)
+	store.SetHabitValueOnDate(water.ID, "2025-03-04", 5)
+
+	report, err := NewGenerator(store).GenerateWeekly(sunday)
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error: %v", err)
+	}
+	h := report.Habits.Habits[0]
+	if h.CompletedCount != 1 || h.Target != 8 || h.Unit != "glasses" {
+		t.Errorf("habit = %d done, target %d %s; want 1 done, target 8 glasses", h.CompletedCount, h.Target, h.Unit)
+	}
+	if len(h.DaysValue) != 7 || h.DaysValue[1] != 8 || h.DaysValue[2] != 5 {
+		t.Errorf("DaysValue = %v, want 8 on Monday and 5 on Tuesday", h.DaysValue)
+	}
+	if md := FormatWeeklyMarkdown(report); !strings.Contains(md, "| 🥤 Water | ✗ | ✓ | 5/8 | ✗ |") {
+		t.Errorf("Expected partial progress in the habits table, got:\n%s", md)
+	}
+
+	daily, err := NewGenerator(store).GenerateDaily(sunday.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("GenerateDaily() error: %v", err)
+	}
+	if d := daily.Habits.Habits[0]; d.Done || d.Value != 5 {
+		t.Errorf("daily habit = done %v value %d, want not done with 5", d.Done, d.Value)
+	}
+	if md := FormatDailyMarkdown(daily); !strings.Contains(md, "- 🥤 Water ✗ 5/8 glasses") {
+		t.Errorf("Expected today's amount in the daily report, got:\n%s", md)
+	}
+}
+
+func TestGenerator_SkippedDaysNotExpected(t *testing.T) {
+	store := createTestStorage(t)
+	run, _ := store.AddHabit("Run", "🏃", storage.FrequencyDaily)
+
+	// Week of Sunday 2025-03-02: done Sunday to Tuesday, skipped Wednesday,
+	// on vacation from Thursday
+	sunday := time.Date(2025, 3, 2, 0, 0, 0, 0, time.Local)
+	for _, date := range []string{"2025-03-02", "2025-03-03", "2025-03-04"} {
+		store.SetHabitDoneOnDate(run.ID, date, true)
+	}
+	store.SetHabitSkippedOnDate(run.ID, "2025-03-05", true)
+	store.SetVacation(&storage.DateRange{Start: "2025-03-06", End: "2025-03-10"})
+
+	report, err := NewGenerator(store).GenerateWeekly(sunday)
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error: %v", err)
+	}
+	h := report.Habits.Habits[0]
+	if h.CompletedCount != 3 || h.ExpectedCount != 3 || h.CompletionRate != 100 || h.Streak != 3 {
+		t.Errorf("habit = %d/%d (%.0f%%) streak %d, want 3/3 (100%%) streak 3",
+			h.CompletedCount, h.ExpectedCount, h.CompletionRate, h.Streak)
+	}
+	if md := FormatWeeklyMarkdown(report); !strings.Contains(md, "| 🏃 Run | ✓ | ✓ | ✓ | ⊘ | ⊘ | ⊘ | ⊘ |") {
+		t.Errorf("Expected skipped days marked with ⊘, got:\n%s", md)
+	}
+
+	daily, _ := NewGenerator(store).GenerateDaily(sunday.AddDate(0, 0, 4))
+	if daily.Habits.TotalCount != 0 || !daily.Habits.Habits[0].Skipped {
+		t.Errorf("daily habits = %+v, want the habit skipped and not expected", daily.Habits)
+	}
+}
+
+func TestGenerator_PausedHabitsNotExpected(t *testing.T) {
+	store := createTestStorage(t)
+	run, _ := store.AddHabit("Run", "🏃", storage.FrequencyDaily)
+	read, _ := store.AddHabit("Read", "📚", storage.FrequencyDaily)
+
+	// Week of Sunday 2025-03-02: the run done Sunday to Tuesday and paused
+	// from Wednesday; the reading archived the week before
+	sunday := time.Date(2025, 3, 2, 0, 0, 0, 0, time.Local)
+	for _, date := range []string{"2025-03-02", "2025-03-03", "2025-03-04"} {
+		store.SetHabitDoneOnDate(run.ID, date, true)
+	}
+	store.SetHabitDoneOnDate(read.ID, "2025-02-25", true)
+	store.SetNowFunc(func() time.Time { return sunday.AddDate(0, 0, -3) })
+	store.SetHabitState(read.ID, storage.HabitArchived)
+	store.SetNowFunc(func() time.Time { return sunday.AddDate(0, 0, 3) })
+	store.SetHabitState(run.ID, storage.HabitPaused)
+
+	report, err := NewGenerator(store).GenerateWeekly(sunday)
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error: %v", err)
+	}
+	if len(report.Habits.Habits) != 1 {
+		t.Fatalf("weekly habits = %d, want only the paused one (archived before the week)", len(report.Habits.Habits))
+	}
+	h := report.Habits.Habits[0]
+	if h.CompletedCount != 3 || h.ExpectedCount != 3 || h.CompletionRate != 100 {
+		t.Errorf("habit = %d/%d (%.0f%%), want 3/3 (100%%)", h.CompletedCount, h.ExpectedCount, h.CompletionRate)
+	}
+
+	// Past reports keep the archived habit's history
+	daily, _ := NewGenerator(store).GenerateDaily(sunday.AddDate(0, 0, -5))
+	if daily.Habits.TotalCount != 2 || daily.Habits.CompletedCount != 1 {
+		t.Errorf("daily habits before archiving = %d/%d, want 1/2", daily.Habits.CompletedCount, daily.Habits.TotalCount)
+	}
+	daily, _ = NewGenerator(store).GenerateDaily(sunday.AddDate(0, 0, 4))
+	if len(daily.Habits.Habits) != 0 || daily.Habits.TotalCount != 0 {
+		t.Errorf("daily habits = %+v, want none while paused and archived", daily.Habits)
+	}
+}
+
 // TestFormatDailyMarkdown tests Markdown formatting.
 func TestFormatDailyMarkdown(t *testing.T) {
 	store := createTestStorage(t)
@@ -394,6 +555,262 @@ func TestProjectGrouping(t *testing.T) {
 	}
 }
 
+// TestRecurringTaskCounts verifies spawned occurrences aren't counted as added.
+func TestRecurringTaskCounts(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Daily standup", "Work", storage.PriorityNone, nil)
+	task.Recurrence = &storage.Recurrence{Kind: storage.RecurDaily}
+	store.UpdateTask(*task)
+	store.CompleteTask(task.ID)
+
+	gen := NewGenerator(store)
+	report, _ := gen.GenerateDaily(time.Now())
+
+	if report.Tasks.AddedCount != 1 {
+		t.Errorf("Expected 1 added task, got %d", report.Tasks.AddedCount)
+	}
+	if report.Tasks.CompletedCount != 1 {
+		t.Errorf("Expected 1 completed task, got %d", report.Tasks.CompletedCount)
+	}
+	if report.Tasks.PendingCount != 1 {
+		t.Errorf("Expected next occurrence to be pending, got %d", report.Tasks.PendingCount)
+	}
+
+	weekly, _ := gen.GenerateWeekly(time.Now())
+	if weekly.Tasks.TotalAdded != 1 {
+		t.Errorf("Expected 1 added task this week, got %d", weekly.Tasks.TotalAdded)
+	}
+}
+
+// TestChecklistInReports verifies checklist items appear in summaries and Markdown.
+func TestChecklistInReports(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Ship release 1.4", "", storage.PriorityNone, nil)
+	item, _ := store.AddChecklistItem(task.ID, "Tag release")
+	store.AddChecklistItem(task.ID, "Publish notes")
+	store.SetChecklistItemDone(task.ID, item.ID, true)
+
+	gen := NewGenerator(store)
+	report, _ := gen.GenerateDaily(time.Now())
+
+	if report.Tasks.ChecklistDone != 1 || report.Tasks.ChecklistTotal != 2 {
+		t.Errorf("Expected checklist progress 1/2, got %d/%d",
+			report.Tasks.ChecklistDone, report.Tasks.ChecklistTotal)
+	}
+	if len(report.Tasks.Pending) != 1 || len(report.Tasks.Pending[0].Checklist) != 2 {
+		t.Error("Pending task should carry its checklist items")
+	}
+
+	md := FormatDailyMarkdown(report)
+	for _, want := range []string{"Ship release 1.4 (1/2)", "  - [x] Tag release", "  - [ ] Publish notes"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q", want)
+		}
+	}
+}
+
+// TestDeferredTasksInReports verifies tasks that start later are not pending.
+func TestDeferredTasksInReports(t *testing.T) {
+	store := createTestStorage(t)
+
+	store.AddTask("Call the bank", "", storage.PriorityNone, nil)
+	task, _ := store.AddTask("Book flights", "", storage.PriorityNone, nil)
+	start := time.Now().AddDate(0, 0, 3)
+	task.StartDate = &start
+	store.UpdateTask(*task)
+
+	gen := NewGenerator(store)
+	report, _ := gen.GenerateDaily(time.Now())
+
+	if report.Tasks.PendingCount != 1 || report.Tasks.DeferredCount != 1 {
+		t.Errorf("Expected 1 pending and 1 deferred task, got %d and %d",
+			report.Tasks.PendingCount, report.Tasks.DeferredCount)
+	}
+	if md := FormatDailyMarkdown(report); !strings.Contains(md, "- **Scheduled for later:** 1 tasks") {
+		t.Error("Markdown should list the deferred count")
+	}
+
+	// On its start date the task counts as pending again
+	later, _ := gen.GenerateDaily(start)
+	if later.Tasks.PendingCount != 2 || later.Tasks.DeferredCount != 0 {
+		t.Errorf("Expected 2 pending tasks on the start date, got %d (%d deferred)",
+			later.Tasks.PendingCount, later.Tasks.DeferredCount)
+	}
+}
+
+// TestNotesInReports tests that task notes reach Markdown and JSON output.
+func TestNotesInReports(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Draft proposal", "", storage.PriorityNone, nil)
+	task.Notes = "Budget: see sheet\n\nhttps://example.com/brief"
+	store.UpdateTask(*task)
+
+	gen := NewGenerator(store)
+	report, _ := gen.GenerateDaily(time.Now())
+
+	md := FormatDailyMarkdown(report)
+	want := "- [ ] Draft proposal\n  > Budget: see sheet\n  >\n  > https://example.com/brief\n"
+	if !strings.Contains(md, want) {
+		t.Errorf("Markdown should quote notes under the task, got:\n%s", md)
+	}
+
+	data, err := FormatDailyJSON(report)
+	if err != nil {
+		t.Fatalf("FormatDailyJSON() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"notes": "Budget: see sheet\n\nhttps://example.com/brief"`) {
+		t.Error("JSON export should include task notes")
+	}
+}
+
+// TestTaskTimeInReports tests per-task time totals in daily and weekly reports.
+func TestTaskTimeInReports(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Write | report", "work", storage.PriorityNone, nil)
+	day := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	store.SaveTimer(&storage.TimerStore{Entries: []storage.TimerEntry{
+		{Project: "work", TaskID: task.ID, StartedAt: day, EndedAt: day.Add(90 * time.Minute)},
+		{Project: "work", TaskID: "t_gone", StartedAt: day.Add(2 * time.Hour), EndedAt: day.Add(150 * time.Minute)},
+		{Project: "admin", StartedAt: day.Add(3 * time.Hour), EndedAt: day.Add(4 * time.Hour)},
+	}})
+
+	gen := NewGenerator(store)
+	daily, err := gen.GenerateDaily(day)
+	if err != nil {
+		t.Fatalf("GenerateDaily() error = %v", err)
+	}
+	byTask := daily.Time.ByTask
+	if len(byTask) != 2 {
+		t.Fatalf("len(ByTask) = %d, want 2 (untracked time is not per task)", len(byTask))
+	}
+	if byTask[0].TaskID != task.ID || byTask[0].Duration != 90*time.Minute || byTask[0].Project != "work" {
+		t.Errorf("ByTask[0] = %+v, want the report task with 1h30m", byTask[0])
+	}
+	if byTask[1].Text != "(deleted task)" {
+		t.Errorf("ByTask[1].Text = %q, want a deleted-task label", byTask[1].Text)
+	}
+	if md := FormatDailyMarkdown(daily); !strings.Contains(md, "  - Write | report: 1h 30m\n") {
+		t.Errorf("daily markdown should list time per task, got:\n%s", md)
+	}
+
+	weekly, err := gen.GenerateWeekly(day)
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error = %v", err)
+	}
+	if len(weekly.Time.ByTask) != 2 {
+		t.Errorf("len(weekly ByTask) = %d, want 2", len(weekly.Time.ByTask))
+	}
+	if md := FormatWeeklyMarkdown(weekly); !strings.Contains(md, "| Write \\| report | work | 1h 30m |") {
+		t.Errorf("weekly markdown should have a time-by-task table, got:\n%s", md)
+	}
+}
+
+// TestArchivedTasksInReports tests that archived tasks still count in
+// historical reports.
+func TestArchivedTasksInReports(t *testing.T) {
+	store := createTestStorage(t)
+
+	day := time.Date(2025, 1, 15, 10, 0, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return day })
+	task, _ := store.AddTask("Quarterly taxes", "finance", storage.PriorityNone, nil)
+	store.CompleteTask(task.ID)
+
+	store.SetNowFunc(func() time.Time { return day.AddDate(0, 3, 0) })
+	if n, err := store.ArchiveTasks(30); err != nil || n != 1 {
+		t.Fatalf("ArchiveTasks() = %d, %v; want 1 archived", n, err)
+	}
+
+	gen := NewGenerator(store)
+	report, err := gen.GenerateDaily(day)
+	if err != nil {
+		t.Fatalf("GenerateDaily() error = %v", err)
+	}
+	if report.Tasks.CompletedCount != 1 || report.Tasks.Completed[0].Text != "Quarterly taxes" {
+		t.Errorf("daily report should include the archived task, got %+v", report.Tasks.Completed)
+	}
+
+	weekly, err := gen.GenerateWeekly(day)
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error = %v", err)
+	}
+	if weekly.Tasks.TotalCompleted != 1 {
+		t.Errorf("weekly TotalCompleted = %d, want 1", weekly.Tasks.TotalCompleted)
+	}
+}
+
+func TestDeletedTasksInReports(t *testing.T) {
+	store := createTestStorage(t)
+
+	done, _ := store.AddTask("Call plumber", "home", storage.PriorityNone, nil)
+	store.CompleteTask(done.ID)
+	dropped, _ := store.AddTask("Maybe later", "", storage.PriorityNone, nil)
+	store.AddTask("Still open", "", storage.PriorityNone, nil)
+	store.DeleteTask(done.ID)
+	store.DeleteTask(dropped.ID)
+	store.PurgeTrash(0)
+
+	gen := NewGenerator(store)
+	report, err := gen.GenerateDaily(time.Now())
+	if err != nil {
+		t.Fatalf("GenerateDaily() error = %v", err)
+	}
+	if report.Tasks.AddedCount != 3 {
+		t.Errorf("AddedCount = %d, want 3 (deleted tasks were still added today)", report.Tasks.AddedCount)
+	}
+	if report.Tasks.CompletedCount != 1 || report.Tasks.Completed[0].Text != "Call plumber" {
+		t.Errorf("Completed = %+v, want the deleted completed task", report.Tasks.Completed)
+	}
+	if report.Tasks.PendingCount != 1 {
+		t.Errorf("PendingCount = %d, want 1 (deleted tasks are not pending)", report.Tasks.PendingCount)
+	}
+
+	weekly, err := gen.GenerateWeekly(time.Now())
+	if err != nil {
+		t.Fatalf("GenerateWeekly() error = %v", err)
+	}
+	if weekly.Tasks.TotalAdded != 3 || weekly.Tasks.TotalCompleted != 1 {
+		t.Errorf("weekly added/completed = %d/%d, want 3/1", weekly.Tasks.TotalAdded, weekly.Tasks.TotalCompleted)
+	}
+}
+
+// TestTagGrouping tests grouping completed tasks by tag.
+func TestTagGrouping(t *testing.T) {
+	store := createTestStorage(t)
+
+	task1, _ := store.AddTask("Invoice", "", storage.PriorityNone, nil, "client-a", "admin")
+	task2, _ := store.AddTask("Kickoff", "", storage.PriorityNone, nil, "client-a")
+	store.AddTask("Pending", "", storage.PriorityNone, nil, "client-b")
+	store.CompleteTask(task1.ID)
+	store.CompleteTask(task2.ID)
+
+	gen := NewGenerator(store)
+	report, _ := gen.GenerateDaily(time.Now())
+
+	want := []TagCount{{Tag: "client-a", Count: 2}, {Tag: "admin", Count: 1}}
+	if len(report.Tasks.ByTag) != len(want) {
+		t.Fatalf("Expected %d tags, got %+v", len(want), report.Tasks.ByTag)
+	}
+	for i := range want {
+		if report.Tasks.ByTag[i] != want[i] {
+			t.Errorf("ByTag[%d] = %+v, want %+v", i, report.Tasks.ByTag[i], want[i])
+		}
+	}
+
+	md := FormatDailyMarkdown(report)
+	if !strings.Contains(md, "#client-a (2), #admin (1)") {
+		t.Error("Markdown should list completed tasks by tag")
+	}
+
+	weekly, _ := gen.GenerateWeekly(time.Now())
+	if len(weekly.Tasks.ByTag) != 2 || weekly.Tasks.ByTag[0].Tag != "client-a" {
+		t.Errorf("Weekly ByTag = %+v, want client-a first", weekly.Tasks.ByTag)
+	}
+}
+
 // Ensure test directory is cleaned up
 func TestMain(m *testing.M) {
 	os.Exit(m.Run())
@@ -34,8 +34,12 @@ type TaskSummary struct {
 	Pending        []storage.Task `json:"pending"`
 	CompletedCount int            `json:"completed_count"`
 	PendingCount   int            `json:"pending_count"`
+	DeferredCount  int            `json:"deferred_count"` // Pending tasks that start after the period
 	AddedCount     int            `json:"added_count"`
 	ByProject      []ProjectCount `json:"by_project"`
+	ByTag          []TagCount     `json:"by_tag"`          // Completed tasks per tag; a task counts once per tag
+	ChecklistDone  int            `json:"checklist_done"`  // Checklist items done across pending tasks
+	ChecklistTotal int            `json:"checklist_total"` // Checklist items across pending tasks
 }
 
 // ProjectCount represents a count grouped by project.
@@ -44,10 +48,17 @@ type ProjectCount struct {
 	Count   int    `json:"count"`
 }
 
+// TagCount represents a count grouped by tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
 // TimeSummary contains time tracking statistics for a period.
 type TimeSummary struct {
 	Total     time.Duration `json:"total"`
 	ByProject []ProjectTime `json:"by_project"`
+	ByTask    []TaskTime    `json:"by_task"` // Only time tracked on a task
 }
 
 // ProjectTime represents time tracked for a specific project.
@@ -57,6 +68,14 @@ type ProjectTime struct {
 	Percentage float64       `json:"percentage"`
 }
 
+// TaskTime represents time tracked on a specific task.
+type TaskTime struct {
+	TaskID   string        `json:"task_id"`
+	Text     string        `json:"text"`
+	Project  string        `json:"project,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
 // HabitSummary contains habit statistics for a period.
 type HabitSummary struct {
 	Habits         []HabitStatus `json:"habits"`
@@ -67,11 +86,19 @@ type HabitSummary struct {
 
 // HabitStatus represents a habit and its completion status.
 type HabitStatus struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Icon   string `json:"icon"`
-	Done   bool   `json:"done"`
-	Streak int    `json:"streak"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Icon    string `json:"icon"`
+	Done    bool   `json:"done"`
+	Skipped bool   `json:"skipped,omitempty"` // Excused that day (skipped or on vacation) and not done
+	Streak  int    `json:"streak"`
+	// StreakUnit is "weeks" for weekly habits and "days" otherwise.
+	StreakUnit string `json:"streak_unit"`
+	// Value is the amount logged for habits with a target, which are done
+	// once it reaches Target.
+	Value  int    `json:"value,omitempty"`
+	Target int    `json:"target,omitempty"`
+	Unit   string `json:"unit,omitempty"`
 }
 
 // WeeklyTasks contains task statistics for a week.
@@ -79,6 +106,7 @@ type WeeklyTasks struct {
 	TotalCompleted int            `json:"total_completed"`
 	TotalAdded     int            `json:"total_added"`
 	ByProject      []ProjectCount `json:"by_project"`
+	ByTag          []TagCount     `json:"by_tag"`
 	ByDay          []DayTaskCount `json:"by_day"`
 }
 
@@ -95,6 +123,7 @@ type WeeklyTime struct {
 	Total         time.Duration `json:"total"`
 	DailyAverage  time.Duration `json:"daily_average"`
 	ByProject     []ProjectTime `json:"by_project"`
+	ByTask        []TaskTime    `json:"by_task"`
 	ByDay         []DayTime     `json:"by_day"`
 }
 
@@ -115,13 +144,21 @@ type WeeklyHabits struct {
 
 // WeeklyHabitStatus represents a habit's completion over a week.
 type WeeklyHabitStatus struct {
-	ID             string   `json:"id"`
-	Name           string   `json:"name"`
-	Icon           string   `json:"icon"`
-	DaysCompleted  []bool   `json:"days_completed"` // 7 bools for each day
-	CompletedCount int      `json:"completed_count"`
-	CompletionRate float64  `json:"completion_rate"`
-	Streak         int      `json:"streak"`
+	ID             string                 `json:"id"`
+	Name           string                 `json:"name"`
+	Icon           string                 `json:"icon"`
+	Frequency      storage.HabitFrequency `json:"frequency"`
+	DaysCompleted  []bool                 `json:"days_completed"` // 7 bools for each day
+	DaysScheduled  []bool                 `json:"days_scheduled"` // Days the habit is due on
+	DaysSkipped    []bool                 `json:"days_skipped"`   // Days the habit was skipped, on vacation or paused
+	CompletedCount int                    `json:"completed_count"`
+	ExpectedCount  int                    `json:"expected_count"`
+	CompletionRate float64                `json:"completion_rate"`
+	Streak         int                    `json:"streak"`
+	StreakUnit     string                 `json:"streak_unit"`          // "weeks" for weekly habits, else "days"
+	DaysValue      []int                  `json:"days_value,omitempty"` // Amount logged each day, for habits with a target
+	Target         int                    `json:"target,omitempty"`
+	Unit           string                 `json:"unit,omitempty"`
 }
 
 // DailySummary provides a quick overview of a single day within a week.
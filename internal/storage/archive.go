@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveDir is the data subdirectory holding archived tasks, one file per
+// month of completion (archive/tasks-2025-01.json).
+const archiveDir = "archive"
+
+// ArchiveTasks moves tasks completed more than olderThanDays days ago out of
+// tasks.json into per-month archive files, keyed by completion month. It
+// returns how many tasks were archived.
+func (s *Storage) ArchiveTasks(olderThanDays int) (int, error) {
+	var count int
+	err := s.update(func() (err error) {
+		count, err = s.archiveTasks(olderThanDays)
+		return err
+	})
+	return count, err
+}
+
+func (s *Storage) archiveTasks(olderThanDays int) (int, error) {
+	if olderThanDays < 0 {
+		return 0, fmt.Errorf("archive age must be zero or more days")
+	}
+	cutoff := startOfDay(s.Now()).AddDate(0, 0, -olderThanDays)
+
+	store, err := s.loadTasks()
+	if err != nil {
+		return 0, err
+	}
+
+	byMonth := make(map[string][]Task)
+	keep := make([]Task, 0, len(store.Tasks))
+	archived := 0
+	for _, task := range store.Tasks {
+		if task.Done && task.CompletedAt != nil && task.CompletedAt.Before(cutoff) {
+			month := archiveMonth(*task.CompletedAt)
+			byMonth[month] = append(byMonth[month], task)
+			archived++
+			continue
+		}
+		keep = append(keep, task)
+	}
+	if archived == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(s.path(archiveDir), dataDirPerm); err != nil {
+		return 0, fmt.Errorf("create archive directory: %w", err)
+	}
+
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	// Archives are written before tasks.json, so an interrupted run leaves
+	// duplicates (skipped on the next run) rather than losing tasks.
+	written := make([]string, 0, len(months))
+	for _, month := range months {
+		existing, err := s.readArchive(archiveFile(month))
+		if err != nil {
+			return 0, err
+		}
+		seen := make(map[string]bool, len(existing))
+		for _, task := range existing {
+			seen[task.ID] = true
+		}
+		for _, task := range byMonth[month] {
+			if !seen[task.ID] {
+				existing = append(existing, task)
+			}
+		}
+		if err := s.writeJSONAtomic(archiveFile(month), &TaskStore{Version: SchemaVersion, Tasks: existing}); err != nil {
+			return 0, err
+		}
+		written = append(written, archiveFile(month))
+	}
+
+	store.Tasks = keep
+	if err := s.SaveTasks(store); err != nil {
+		return 0, err
+	}
+	// Notify with semantic context for git commit
+	s.notifySaveWithContext(SaveContext{
+		Filename:  "tasks.json",
+		Files:     written,
+		Operation: "archive",
+		ItemType:  "tasks",
+		ItemName:  fmt.Sprintf("%d completed", archived),
+	})
+	return archived, nil
+}
+
+// LoadArchivedTasks returns the archived tasks completed in the month of since
+// or later. Since a task is completed after it is created, this includes every
+// archived task created or completed on or after since.
+func (s *Storage) LoadArchivedTasks(since time.Time) ([]Task, error) {
+	entries, err := os.ReadDir(s.path(archiveDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read archive directory: %w", err)
+	}
+
+	first := archiveMonth(since)
+	var tasks []Task
+	for _, entry := range entries {
+		month, ok := parseArchiveName(entry.Name())
+		if !ok || entry.IsDir() || month < first {
+			continue
+		}
+		archived, err := s.readArchive(filepath.Join(archiveDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, archived...)
+	}
+	return tasks, nil
+}
+
+// readArchive reads one archive file. A missing file is an empty archive;
+// unlike the live data files, a damaged archive is reported, never reset.
+func (s *Storage) readArchive(filename string) ([]Task, error) {
+	data, err := os.ReadFile(s.path(filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	if data, err = s.key.Decode(data); err != nil {
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	// Archives are migrated in memory only; they are rewritten at the
+	// current version the next time tasks are archived into them.
+	data, _, err = migrateFile(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	var store TaskStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filename, err)
+	}
+	return store.Tasks, nil
+}
+
+// archiveMonth returns the "2006-01" month key used to name archive files.
+func archiveMonth(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// archiveFile returns the data-relative path of a month's archive file.
+func archiveFile(month string) string {
+	return filepath.Join(archiveDir, "tasks-"+month+".json")
+}
+
+// parseArchiveName extracts the month from an archive file name, reporting
+// false for anything else in the archive directory (such as .bak files).
+func parseArchiveName(name string) (string, bool) {
+	if !strings.HasPrefix(name, "tasks-") || !strings.HasSuffix(name, ".json") {
+		return "", false
+	}
+	month := strings.TrimSuffix(strings.TrimPrefix(name, "tasks-"), ".json")
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return "", false
+	}
+	return month, true
+}
@@ -120,7 +120,7 @@ func BenchmarkAddHabit(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := store.AddHabit(fmt.Sprintf("Habit %d", i), "🎯")
+		_, err := store.AddHabit(fmt.Sprintf("Habit %d", i), "🎯", FrequencyDaily)
 		if err != nil {
 			b.Fatalf("AddHabit failed: %v", err)
 		}
@@ -137,7 +137,7 @@ func BenchmarkLoadHabits(b *testing.B) {
 
 			// Populate with habits
 			for i := 0; i < size; i++ {
-				store.AddHabit(fmt.Sprintf("Habit %d", i), "🔥")
+				store.AddHabit(fmt.Sprintf("Habit %d", i), "🔥", FrequencyDaily)
 			}
 
 			b.ResetTimer()
@@ -155,7 +155,7 @@ func BenchmarkLoadHabits(b *testing.B) {
 func BenchmarkToggleHabitToday(b *testing.B) {
 	store := createBenchStorage(b)
 
-	habit, _ := store.AddHabit("Benchmark Habit", "⚡")
+	habit, _ := store.AddHabit("Benchmark Habit", "⚡", FrequencyDaily)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -170,7 +170,7 @@ func BenchmarkToggleHabitToday(b *testing.B) {
 func BenchmarkGetHabitStreak(b *testing.B) {
 	store := createBenchStorage(b)
 
-	habit, _ := store.AddHabit("Streak Habit", "🔥")
+	habit, _ := store.AddHabit("Streak Habit", "🔥", FrequencyDaily)
 
 	// Create a 30-day streak
 	hs, _ := store.LoadHabits()
@@ -198,7 +198,7 @@ func BenchmarkGetHabitStreak(b *testing.B) {
 func BenchmarkGetHabitWeek(b *testing.B) {
 	store := createBenchStorage(b)
 
-	habit, _ := store.AddHabit("Week Habit", "📅")
+	habit, _ := store.AddHabit("Week Habit", "📅", FrequencyDaily)
 
 	// Add logs for the past 7 days
 	hs, _ := store.LoadHabits()
@@ -233,7 +233,7 @@ func BenchmarkLoadHabitsWithManyLogs(b *testing.B) {
 			// Create habits
 			habits := make([]string, 10)
 			for i := 0; i < 10; i++ {
-				h, _ := store.AddHabit(fmt.Sprintf("Habit %d", i), "🎯")
+				h, _ := store.AddHabit(fmt.Sprintf("Habit %d", i), "🎯", FrequencyDaily)
 				habits[i] = h.ID
 			}
 
@@ -437,7 +437,7 @@ func BenchmarkConcurrentReads(b *testing.B) {
 	// Populate with data
 	for i := 0; i < 100; i++ {
 		store.AddTask(fmt.Sprintf("Task %d", i), "project", PriorityNone, nil)
-		store.AddHabit(fmt.Sprintf("Habit %d", i), "🎯")
+		store.AddHabit(fmt.Sprintf("Habit %d", i), "🎯", FrequencyDaily)
 	}
 	store.StartTimer("Active Project")
 
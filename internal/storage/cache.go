@@ -0,0 +1,358 @@
+package storage
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Storage keeps the collection it last read or wrote for each file in
+// memory. A load stats the file and, while the file is unchanged, hands out a
+// copy of the cached collection instead of parsing it again, so a long habit
+// history or task list costs one parse rather than one per action.
+// Anything else that writes the file (another instance, a git pull, an
+// editor) changes its stamp, and the next load reads it from disk.
+//
+// Inside an update cycle saves only replace the cached collection and mark
+// it dirty. Only the cycle itself reads dirty entries; other loads get the
+// collection on disk. When the cycle succeeds each dirty file is written
+// once, in the order it was first saved; when it fails nothing is written.
+// Notifications for the cycle are held back until its writes have landed.
+
+// collection is a store kept in its own JSON file.
+type collection interface {
+	revision() *int64
+}
+
+func (c *TaskStore) revision() *int64  { return &c.Revision }
+func (c *HabitStore) revision() *int64 { return &c.Revision }
+func (c *TimerStore) revision() *int64 { return &c.Revision }
+func (c *TrashStore) revision() *int64 { return &c.Revision }
+
+// cachedFile is a parsed collection. Entries are replaced, never modified,
+// and their values are only handed out as copies.
+type cachedFile struct {
+	stamp fileStamp
+	value collection
+	dirty bool        // saved in the current update cycle but not yet written
+	clean *cachedFile // for dirty entries, what is on disk
+}
+
+// loadCollection fills v with the collection in filename, from the cache if
+// the file has not changed since it was cached. Saves of the current update
+// cycle that are not written yet are only seen with working set, by the
+// cycle itself; everyone else gets what is on disk.
+func (s *Storage) loadCollection(filename string, v collection, working bool) error {
+	stamp, ok := s.statStamp(filename)
+
+	s.fileMu.Lock()
+	cached := s.cache[filename]
+	if cached != nil && cached.dirty && !working {
+		cached = cached.clean
+	}
+	if cached != nil && (cached.dirty || ok && cached.stamp.equal(stamp)) {
+		copyCollection(v, cached.value)
+		s.fileMu.Unlock()
+		return nil
+	}
+	s.fileMu.Unlock()
+
+	if err := s.loadJSONWithRecovery(filename, v); err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	// The stamp was taken before reading, so a write that raced the read
+	// shows up as a changed stamp on the next load.
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if cached := s.cache[filename]; cached == nil || !cached.dirty {
+		s.setCached(filename, &cachedFile{stamp: stamp, value: cloneCollection(v)})
+	}
+	return nil
+}
+
+// saveCollection writes v to filename, or defers the write to the end of the
+// current update cycle.
+func (s *Storage) saveCollection(filename string, v collection) error {
+	s.fileMu.Lock()
+	if s.batching {
+		cached := s.cache[filename]
+		clean := cached
+		if cached != nil && cached.dirty {
+			clean = cached.clean
+		} else {
+			s.dirty = append(s.dirty, filename)
+		}
+		s.setCached(filename, &cachedFile{value: cloneCollection(v), dirty: true, clean: clean})
+		s.fileMu.Unlock()
+		return nil
+	}
+	s.fileMu.Unlock()
+
+	if err := s.writeJSONRevision(filename, v); err != nil {
+		return err
+	}
+	s.cacheWritten(filename, cloneCollection(v))
+	return nil
+}
+
+// cacheWritten caches v, which must not be modified afterwards, as the
+// contents of filename just written.
+func (s *Storage) cacheWritten(filename string, v collection) {
+	stamp, ok := s.statStamp(filename)
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if ok {
+		s.setCached(filename, &cachedFile{stamp: stamp, value: v})
+	} else {
+		delete(s.cache, filename)
+	}
+}
+
+// cachedRevision returns the revision of filename if the cache knows what is
+// on disk. Collections that fail to load are never cached, so a cached file
+// always has the current schema version.
+func (s *Storage) cachedRevision(filename string) (int64, bool) {
+	stamp, ok := s.statStamp(filename)
+	if !ok {
+		return 0, false
+	}
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	cached := s.cache[filename]
+	if cached != nil && cached.dirty {
+		cached = cached.clean
+	}
+	if cached == nil || !cached.stamp.equal(stamp) {
+		return 0, false
+	}
+	return *cached.value.revision(), true
+}
+
+// setCached must be called with fileMu held.
+func (s *Storage) setCached(filename string, entry *cachedFile) {
+	if s.cache == nil {
+		s.cache = make(map[string]*cachedFile)
+	}
+	s.cache[filename] = entry
+}
+
+// batch runs fn with saves deferred, then writes every collection it saved
+// and sends its notifications. If fn or a write fails, the collections not
+// yet written are dropped from the cache and no notifications are sent.
+func (s *Storage) batch(fn func() error) error {
+	s.fileMu.Lock()
+	s.batching = true
+	s.fileMu.Unlock()
+
+	err := fn()
+	if err == nil {
+		err = s.flushDirty()
+	}
+
+	s.fileMu.Lock()
+	for _, filename := range s.dirty {
+		if cached := s.cache[filename]; cached != nil && cached.dirty {
+			if cached.clean != nil {
+				s.cache[filename] = cached.clean
+			} else {
+				delete(s.cache, filename)
+			}
+		}
+	}
+	pending := s.pending
+	s.dirty, s.pending, s.batching = nil, nil, false
+	s.fileMu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	for _, ctx := range pending {
+		s.notifySaveWithContext(ctx)
+	}
+	return nil
+}
+
+// flushDirty writes the collections saved during the current cycle. Every
+// file is checked against what is on disk before the first one is written,
+// so a stale cycle writes nothing and can be run again. A write failing after
+// others landed returns a partialWriteError.
+func (s *Storage) flushDirty() error {
+	s.fileMu.Lock()
+	var check []string
+	var values []collection
+	for _, filename := range s.dirty {
+		if cached := s.cache[filename]; cached != nil && cached.dirty {
+			check = append(check, filename)
+			values = append(values, cached.value)
+		}
+	}
+	s.fileMu.Unlock()
+	for i, filename := range check {
+		if _, err := s.checkRevision(filename, values[i]); err != nil {
+			return err
+		}
+	}
+
+	var written []string
+	for {
+		s.fileMu.Lock()
+		if len(s.dirty) == 0 {
+			s.fileMu.Unlock()
+			return nil
+		}
+		filename := s.dirty[0]
+		cached := s.cache[filename]
+		if cached == nil || !cached.dirty {
+			s.dirty = s.dirty[1:]
+			s.fileMu.Unlock()
+			continue
+		}
+		// The dirty entry stays untouched; only the copy's revision moves.
+		v := shallowCollection(cached.value)
+		s.fileMu.Unlock()
+
+		if err := s.writeJSONRevision(filename, v); err != nil {
+			if len(written) > 0 {
+				return &partialWriteError{written: written, err: err}
+			}
+			return err
+		}
+		s.cacheWritten(filename, v)
+		written = append(written, filename)
+
+		s.fileMu.Lock()
+		s.dirty = s.dirty[1:]
+		s.fileMu.Unlock()
+	}
+}
+
+// deferNotification holds ctx until the current cycle's writes land. It
+// reports false outside an update cycle.
+func (s *Storage) deferNotification(ctx SaveContext) bool {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if !s.batching {
+		return false
+	}
+	s.pending = append(s.pending, ctx)
+	return true
+}
+
+// cloneCollection returns a deep copy of c.
+func cloneCollection(c collection) collection {
+	v := reflect.New(reflect.TypeOf(c).Elem())
+	v.Elem().Set(reflect.ValueOf(c).Elem())
+	deepen(v.Elem())
+	return v.Interface().(collection)
+}
+
+// shallowCollection returns a copy of c that shares its items. Only its own
+// fields, such as the revision, may be changed.
+func shallowCollection(c collection) collection {
+	v := reflect.New(reflect.TypeOf(c).Elem())
+	v.Elem().Set(reflect.ValueOf(c).Elem())
+	return v.Interface().(collection)
+}
+
+// copyCollection overwrites dst with a deep copy of src, which must be the
+// same type.
+func copyCollection(dst, src collection) {
+	v := reflect.ValueOf(dst).Elem()
+	v.Set(reflect.ValueOf(src).Elem())
+	deepen(v)
+}
+
+// deepen gives v, a settable copy of some value, its own copy of everything
+// it still shares with the original through pointers, slices, maps and
+// interfaces. Unexported struct fields, such as a time.Time's location, stay
+// shared.
+func deepen(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(v.Elem())
+		deepen(p.Elem())
+		v.Set(p)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		items := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(items, v)
+		if needsDeepCopy(v.Type().Elem()) {
+			for i := 0; i < items.Len(); i++ {
+				deepen(items.Index(i))
+			}
+		}
+		v.Set(items)
+	case reflect.Array:
+		if needsDeepCopy(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				deepen(v.Index(i))
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			deepen(value)
+			m.SetMapIndex(iter.Key(), value)
+		}
+		v.Set(m)
+	case reflect.Interface:
+		if v.IsNil() || !needsDeepCopy(v.Elem().Type()) {
+			return
+		}
+		value := reflect.New(v.Elem().Type()).Elem()
+		value.Set(v.Elem())
+		deepen(value)
+		v.Set(value)
+	case reflect.Struct:
+		for _, i := range deepFields(v.Type()) {
+			deepen(v.Field(i))
+		}
+	}
+}
+
+// needsDeepCopy reports whether a value of type t can share memory with a
+// copy made by assignment.
+func needsDeepCopy(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	case reflect.Array:
+		return needsDeepCopy(t.Elem())
+	case reflect.Struct:
+		return len(deepFields(t)) > 0
+	}
+	return false
+}
+
+// structFields memoizes deepFields by struct type.
+var structFields sync.Map
+
+// deepFields returns the indexes of the exported fields of struct type t
+// that need a deep copy.
+func deepFields(t reflect.Type) []int {
+	if fields, ok := structFields.Load(t); ok {
+		return fields.([]int)
+	}
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() && needsDeepCopy(f.Type) {
+			fields = append(fields, i)
+		}
+	}
+	structFields.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"today/internal/crypt"
+	"today/internal/fsutil"
+)
+
+// Rekey rewrites every data file in the data directory with key, or in
+// plaintext if key is nil, and uses key for all later writes. Besides the
+// collections this covers their .bak and pre-migration copies, archives,
+// backups and the activity journal. Files already in the wanted form are
+// left alone, so an interrupted run can simply be repeated. It returns how
+// many files were rewritten.
+func (s *Storage) Rekey(key *crypt.Key) (int, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	var files []string
+	err = filepath.WalkDir(s.dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != s.dataDir && (name == ".git" || name == lockDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isRekeyedFile(d.Name()) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list data files: %w", err)
+	}
+
+	count := 0
+	for _, path := range files {
+		changed, err := s.rekeyFile(path, key)
+		if err != nil {
+			rel, _ := filepath.Rel(s.dataDir, path)
+			return count, fmt.Errorf("%s: %w", rel, err)
+		}
+		if changed {
+			count++
+		}
+	}
+
+	s.key = key
+	s.fileMu.Lock()
+	s.cache = nil
+	s.fileMu.Unlock()
+	return count, nil
+}
+
+// scratchDir holds plaintext working copies, such as notes open in an
+// external editor, while the data is encrypted.
+const scratchDir = "scratch"
+
+// ScratchDir returns a directory for plaintext working copies of user data.
+// With encryption on this is a directory inside the data directory that only
+// the owner can read and that git sync ignores; otherwise it returns "" and
+// the system temp directory will do.
+func (s *Storage) ScratchDir() (string, error) {
+	if s.key == nil {
+		return "", nil
+	}
+	dir := s.path(scratchDir)
+	if err := os.MkdirAll(dir, dataDirPerm); err != nil {
+		return "", err
+	}
+	// MkdirAll leaves an existing directory's mode alone.
+	if err := os.Chmod(dir, dataDirPerm); err != nil {
+		return "", err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if !fileExists(ignore) {
+		if err := fsutil.WriteFileAtomic(ignore, []byte("*\n"), dataFilePerm); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// isRekeyedFile reports whether a file holds data that is encrypted along
+// with the collections: anything JSON except the key file and backup
+// manifests, which hold no user data.
+func isRekeyedFile(name string) bool {
+	if name == crypt.KeyFile || name == "manifest.json" || strings.Contains(name, ".tmp-") {
+		return false
+	}
+	return strings.Contains(name, ".json")
+}
+
+func (s *Storage) rekeyFile(path string, key *crypt.Key) (bool, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var out []byte
+	if filepath.Base(path) == activityFile {
+		out, err = s.rekeyLines(raw, key)
+	} else if sealedWith(raw, key) {
+		return false, nil
+	} else {
+		var plain []byte
+		if plain, err = s.key.Decode(raw); err == nil {
+			out, err = key.Encode(plain)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(out, raw) {
+		return false, nil
+	}
+	if err := fsutil.WriteFileAtomic(path, out, dataFilePerm); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rekeyLines converts the journal line by line. Lines that cannot be read
+// are kept as they are; LoadActivity skips them either way.
+func (s *Storage) rekeyLines(raw []byte, key *crypt.Key) ([]byte, error) {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), maxActivityLine)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if plain, err := s.key.Decode(line); err == nil && !sealedWith(line, key) {
+			if key != nil {
+				if line, err = key.Seal(plain); err != nil {
+					return nil, err
+				}
+			} else {
+				line = plain
+			}
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// sealedWith reports whether data is already sealed with key, so a repeated
+// Rekey leaves it alone; sealing again would only change the nonce.
+func sealedWith(data []byte, key *crypt.Key) bool {
+	if key == nil || !crypt.IsSealed(bytes.TrimSpace(data)) {
+		return false
+	}
+	_, err := key.Open(data)
+	return err == nil
+}
@@ -136,7 +136,7 @@ func FuzzAddHabit(f *testing.F) {
 			}
 		}()
 
-		habit, err := store.AddHabit(name, icon)
+		habit, err := store.AddHabit(name, icon, FrequencyDaily)
 
 		// Empty name (after trimming) should error
 		if strings.TrimSpace(name) == "" {
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"today/internal/fsutil"
+)
+
+// activityFile is the append-only journal of every change, one JSON object
+// per line.
+const activityFile = "activity.jsonl"
+
+// maxActivityLine bounds a journal line; a task with long notes appears in
+// both its before and after snapshots.
+const maxActivityLine = 1 << 20
+
+// ActivityEntry is one operation recorded in the activity journal.
+type ActivityEntry struct {
+	Time      time.Time       `json:"time"`
+	Operation string          `json:"op"`
+	ItemType  string          `json:"type"`
+	ItemID    string          `json:"id,omitempty"`
+	ItemName  string          `json:"name,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// habitDay is the journal snapshot of a habit check-in. Value is the amount
+// logged for habits with a target.
+type habitDay struct {
+	Date    string `json:"date"`
+	Done    bool   `json:"done"`
+	Value   int    `json:"value,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// Task returns the task an entry is about, as it was after the operation
+// (before it, for deletes). It returns nil for entries about other items.
+func (e ActivityEntry) Task() *Task {
+	if e.ItemType != "task" {
+		return nil
+	}
+	raw := e.After
+	if len(raw) == 0 {
+		raw = e.Before
+	}
+	var task Task
+	if len(raw) == 0 || json.Unmarshal(raw, &task) != nil || task.ID == "" {
+		return nil
+	}
+	return &task
+}
+
+// snapshot encodes an item for the journal.
+func snapshot(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// appendActivity adds an operation to the journal. The change itself has
+// already been saved, so a journal that cannot be written only loses history
+// and never fails the operation; the error is reported instead (see
+// SetOnActivityError).
+func (s *Storage) appendActivity(ctx SaveContext) error {
+	line, err := json.Marshal(ActivityEntry{
+		Time:      s.Now(),
+		Operation: ctx.Operation,
+		ItemType:  ctx.ItemType,
+		ItemID:    ctx.ItemID,
+		ItemName:  ctx.ItemName,
+		Before:    ctx.Before,
+		After:     ctx.After,
+	})
+	if err != nil {
+		return fmt.Errorf("serialize %s entry: %w", activityFile, err)
+	}
+	if s.key != nil {
+		if line, err = s.key.Seal(line); err != nil {
+			return fmt.Errorf("encrypt %s entry: %w", activityFile, err)
+		}
+	}
+	f, err := os.OpenFile(s.path(activityFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, dataFilePerm)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", activityFile, err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", activityFile, err)
+	}
+	return nil
+}
+
+// SetOnActivityError registers a callback for changes that were saved but
+// could not be recorded in the activity journal. Without one, the error is
+// printed to stderr.
+func (s *Storage) SetOnActivityError(fn func(err error)) {
+	s.onActivityError = fn
+}
+
+// reportActivityError hands a journal write failure to the registered
+// callback.
+func (s *Storage) reportActivityError(err error) {
+	if s.onActivityError != nil {
+		s.onActivityError(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: activity journal: %v\n", err)
+}
+
+// PruneActivity drops journal entries older than retentionDays days, so
+// the journal does not grow forever. Zero keeps every entry. It returns how
+// many entries were dropped; lines that cannot be parsed are dropped too.
+func (s *Storage) PruneActivity(retentionDays int) (int, error) {
+	if retentionDays < 0 {
+		return 0, fmt.Errorf("activity retention must be zero or more days")
+	}
+	if retentionDays == 0 {
+		return 0, nil
+	}
+	// Appends happen under the same lock, so none is lost to the rewrite.
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	cutoff := s.Now().AddDate(0, 0, -retentionDays)
+	path := s.path(activityFile)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read %s: %w", activityFile, err)
+	}
+	defer f.Close()
+
+	var kept bytes.Buffer
+	dropped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxActivityLine)
+	for scanner.Scan() {
+		var entry ActivityEntry
+		line, err := s.key.Decode(scanner.Bytes())
+		if err != nil || json.Unmarshal(line, &entry) != nil || entry.Time.Before(cutoff) {
+			dropped++
+			continue
+		}
+		// Lines are kept as they are on disk, sealed when the data is
+		// encrypted.
+		kept.Write(scanner.Bytes())
+		kept.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read %s: %w", activityFile, err)
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+	if err := fsutil.WriteFileAtomic(path, kept.Bytes(), dataFilePerm); err != nil {
+		return 0, fmt.Errorf("write %s: %w", activityFile, err)
+	}
+	return dropped, nil
+}
+
+// LoadActivity returns the journal entries recorded at or after since,
+// oldest first. Lines that cannot be parsed, such as one cut short by a
+// crash, are skipped. When the data is encrypted each line is sealed on its
+// own, so the journal stays append-only.
+func (s *Storage) LoadActivity(since time.Time) ([]ActivityEntry, error) {
+	f, err := os.Open(s.path(activityFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", activityFile, err)
+	}
+	defer f.Close()
+
+	var entries []ActivityEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxActivityLine)
+	for scanner.Scan() {
+		line, err := s.key.Decode(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		var entry ActivityEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", activityFile, err)
+	}
+	return entries, nil
+}
+
+// Summary describes the entry in a couple of words, such as "completed task".
+func (e ActivityEntry) Summary() string {
+	verb := e.Operation
+	switch e.Operation {
+	case "add", "complete", "delete", "restore", "reopen", "reorder", "archive", "purge", "block", "unblock", "pause", "resume":
+		verb = strings.TrimSuffix(e.Operation, "e") + "ed"
+	case "update":
+		verb = "edited"
+	case "start":
+		verb = "started"
+	case "stop":
+		verb = "stopped"
+	case "log":
+		verb = "logged"
+	case "skip":
+		var day habitDay
+		if json.Unmarshal(e.After, &day) == nil && !day.Skipped {
+			verb = "unskipped"
+		} else {
+			verb = "skipped"
+		}
+	case "toggle":
+		var day habitDay
+		if json.Unmarshal(e.After, &day) == nil && !day.Done {
+			verb = "unchecked"
+		} else {
+			verb = "checked"
+		}
+	}
+	return verb + " " + e.ItemType
+}
@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"today/internal/fsutil"
+)
+
+// ErrStaleWrite is returned when a save is based on data that another
+// process has changed since it was loaded.
+var ErrStaleWrite = errors.New("data was changed by another process")
+
+const (
+	lockDir          = "locks"
+	writeLockFile    = "write.lock"
+	lockTimeout      = 5 * time.Second
+	lockRetryDelay   = 10 * time.Millisecond
+	staleLockAge     = 30 * time.Second // writes take milliseconds; older locks were left by a crash
+	maxWriteAttempts = 3
+
+	// InstanceHeartbeat is how often a running app should call
+	// RegisterInstance to show it is still alive.
+	InstanceHeartbeat = 30 * time.Second
+	staleInstanceAge  = 3 * InstanceHeartbeat
+)
+
+// Instance describes a today process that has the data directory open.
+type Instance struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// update runs one load-modify-save cycle while holding the data lock. If the
+// save finds the data changed underneath it (a writer that does not take the
+// lock, such as an older version or a git pull), the cycle is run again on
+// fresh data. Saves made by fn are written when it returns (see batch). A
+// cycle that already wrote some of its files is never run again, since that
+// would apply its changes twice.
+func (s *Storage) update(fn func() error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for attempt := 1; ; attempt++ {
+		err := s.batch(fn)
+		var partial *partialWriteError
+		if !errors.Is(err, ErrStaleWrite) || errors.As(err, &partial) || attempt == maxWriteAttempts {
+			return err
+		}
+	}
+}
+
+// partialWriteError is returned by an update cycle that failed after some of
+// its files were written.
+type partialWriteError struct {
+	written []string
+	err     error
+}
+
+func (e *partialWriteError) Error() string {
+	return fmt.Sprintf("%v (%s already saved)", e.err, strings.Join(e.written, ", "))
+}
+
+func (e *partialWriteError) Unwrap() error { return e.err }
+
+// lock serializes writers: goroutines of this process through a mutex, and
+// other today processes through an advisory lock file in the data directory.
+// The lock file holds the owner's PID and a token of its own, so neither
+// breaking a stale lock nor unlocking removes a lock another process took.
+func (s *Storage) lock() (unlock func(), err error) {
+	s.mu.Lock()
+	path := filepath.Join(s.dataDir, lockDir, writeLockFile)
+	if err := os.MkdirAll(filepath.Dir(path), dataDirPerm); err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("lock data: %w", err)
+	}
+	content := []byte(fmt.Sprintf("%d %s\n", os.Getpid(), hex.EncodeToString(token)))
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, dataFilePerm)
+		if err == nil {
+			_, err = f.Write(content)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				s.mu.Unlock()
+				return nil, fmt.Errorf("lock data: %w", err)
+			}
+			return func() {
+				// Held past staleLockAge, the lock may have been broken
+				// and taken by another process.
+				if held, err := os.ReadFile(path); err == nil && bytes.Equal(held, content) {
+					os.Remove(path)
+				}
+				s.mu.Unlock()
+			}, nil
+		}
+		if !os.IsExist(err) {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("lock data: %w", err)
+		}
+		if breakStaleLock(path) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("data is locked by another today process (pid %s); remove %s if none is running",
+				lockOwner(path), path)
+		}
+		time.Sleep(lockRetryDelay)
+	}
+}
+
+// breakStaleLock removes the lock file at path if it is older than
+// staleLockAge, reporting whether it did. The file is read again just before
+// removing it, so a lock another process took in the meantime is left alone.
+func breakStaleLock(path string) bool {
+	stale, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) <= staleLockAge {
+		return false
+	}
+	if current, err := os.ReadFile(path); err != nil || !bytes.Equal(current, stale) {
+		return false
+	}
+	return os.Remove(path) == nil
+}
+
+// lockOwner returns the process ID recorded in a lock file, or "unknown".
+func lockOwner(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "unknown"
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	if _, err := strconv.Atoi(fields[0]); err != nil {
+		return "unknown"
+	}
+	return fields[0]
+}
+
+// writeJSONRevision writes a collection if its file is still at the revision
+// it was loaded at, bumping its revision. Otherwise it returns ErrStaleWrite. A
+// revision of 0 means the collection was built rather than loaded (or comes
+// from a file older than revisions); it overwrites whatever is on disk.
+func (s *Storage) writeJSONRevision(filename string, v collection) error {
+	rev := v.revision()
+	base := *rev
+	current, err := s.checkRevision(filename, v)
+	if err != nil {
+		return err
+	}
+	*rev = current + 1
+	if err := s.writeJSONAtomic(filename, v); err != nil {
+		*rev = base
+		return err
+	}
+	return nil
+}
+
+// checkRevision returns the revision of filename on disk if v may replace
+// it, or the error writeJSONRevision would fail with. It changes nothing.
+func (s *Storage) checkRevision(filename string, v collection) (int64, error) {
+	base := *v.revision()
+	current, version, ok := s.readRevision(filename)
+	if !ok {
+		return base, nil
+	}
+	if version > SchemaVersion {
+		return 0, fmt.Errorf("%w: refusing to overwrite %s (schema version %d)", ErrNewerSchema, filename, version)
+	}
+	if base != 0 && current != base {
+		return 0, fmt.Errorf("%w: %s is at revision %d, expected %d", ErrStaleWrite, filename, current, base)
+	}
+	if base != 0 && s.replacedAtRevision(filename, base) {
+		return 0, fmt.Errorf("%w: %s was replaced by another copy at revision %d", ErrStaleWrite, filename, base)
+	}
+	return current, nil
+}
+
+// replacedAtRevision reports whether filename was replaced on disk by a
+// different file that carries the same revision as the one this Storage
+// loaded, as when a git pull brings in a copy edited elsewhere.
+func (s *Storage) replacedAtRevision(filename string, rev int64) bool {
+	stamp, ok := s.statStamp(filename)
+	if !ok {
+		return false
+	}
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	cached := s.cache[filename]
+	if cached != nil && cached.dirty {
+		cached = cached.clean
+	}
+	return cached != nil && *cached.value.revision() == rev && !cached.stamp.equal(stamp)
+}
+
+// readRevision returns the revision and schema version of a collection file
+// on disk. ok is false when the file is missing or unreadable; load-time
+// recovery deals with those, so they never block a save.
+func (s *Storage) readRevision(filename string) (rev int64, version int, ok bool) {
+	if rev, ok := s.cachedRevision(filename); ok {
+		return rev, SchemaVersion, true
+	}
+	data, err := os.ReadFile(s.path(filename))
+	if err != nil {
+		return 0, 0, false
+	}
+	if data, err = s.key.Decode(data); err != nil {
+		return 0, 0, false
+	}
+	var head struct {
+		Version  int   `json:"version"`
+		Revision int64 `json:"revision"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return 0, 0, false
+	}
+	return head.Revision, head.Version, true
+}
+
+// RegisterInstance records this process as having the data open (or refreshes
+// the record) and returns the other instances that still do. Records that
+// have not been refreshed for a while were left by a crash and are removed.
+func (s *Storage) RegisterInstance() ([]Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.dataDir, lockDir)
+	if err := os.MkdirAll(dir, dataDirPerm); err != nil {
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+	if s.instance == nil {
+		host, _ := os.Hostname()
+		s.instance = &Instance{PID: os.Getpid(), Host: host, StartedAt: s.Now()}
+	}
+	data, err := json.Marshal(s.instance)
+	if err != nil {
+		return nil, fmt.Errorf("serialize instance: %w", err)
+	}
+	own := instancePath(dir, *s.instance)
+	if err := fsutil.WriteFileAtomic(own, data, dataFilePerm); err != nil {
+		return nil, fmt.Errorf("register instance: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "instance-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var others []Instance
+	for _, path := range paths {
+		if path == own {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) > staleInstanceAge {
+			os.Remove(path)
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var inst Instance
+		if err := json.Unmarshal(data, &inst); err != nil {
+			continue
+		}
+		others = append(others, inst)
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].StartedAt.Before(others[j].StartedAt)
+	})
+	return others, nil
+}
+
+// UnregisterInstance removes the record written by RegisterInstance.
+func (s *Storage) UnregisterInstance() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.instance == nil {
+		return nil
+	}
+	err := os.Remove(instancePath(filepath.Join(s.dataDir, lockDir), *s.instance))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.instance = nil
+	return nil
+}
+
+// instancePath names the record for a process. The host is included because
+// the data directory may be shared between machines.
+func instancePath(dir string, inst Instance) string {
+	host := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, inst.Host)
+	return filepath.Join(dir, fmt.Sprintf("instance-%s-%d.json", host, inst.PID))
+}
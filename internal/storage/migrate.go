@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"today/internal/fsutil"
+)
+
+// SchemaVersion is the version of the data file format written by this build.
+// Bump it together with a new entry in migrations.
+const SchemaVersion = 4
+
+// ErrNewerSchema is returned for data files written by a newer today, which
+// this build could damage by rewriting them without the fields it lacks.
+var ErrNewerSchema = errors.New("data was written by a newer version of today")
+
+// migration upgrades a decoded data file from version from to from+1. It
+// works on generic JSON so it can reshape fields the current structs no
+// longer have. filename tells it which collection it is looking at.
+type migration struct {
+	from  int
+	apply func(filename string, doc map[string]any) error
+}
+
+// migrations holds every upgrade step, oldest first.
+var migrations = []migration{
+	{from: 0, apply: migrateExplicitHabitFrequency},
+	{from: 1, apply: migrateHabitTargets},
+	{from: 2, apply: migrateHabitSkips},
+	{from: 3, apply: migrateHabitStates},
+}
+
+// migrateFile upgrades the contents of a data file to SchemaVersion. Files
+// that are already current or not valid JSON are returned unchanged; the
+// latter are left to load-time recovery.
+func migrateFile(filename string, data []byte) (out []byte, from int, err error) {
+	var head struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return data, 0, nil
+	}
+	if head.Version > SchemaVersion {
+		return nil, head.Version, fmt.Errorf("%w: %s has schema version %d, this build supports up to %d; upgrade today to open it",
+			ErrNewerSchema, filename, head.Version, SchemaVersion)
+	}
+	if head.Version == SchemaVersion {
+		return data, head.Version, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // keep revisions and other integers exact
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return data, head.Version, nil
+	}
+	for version := head.Version; version < SchemaVersion; version++ {
+		step, ok := findMigration(version)
+		if !ok {
+			return nil, head.Version, fmt.Errorf("no migration for %s from schema version %d", filename, version)
+		}
+		if err := step.apply(filename, doc); err != nil {
+			return nil, head.Version, fmt.Errorf("migrate %s from schema version %d: %w", filename, version, err)
+		}
+	}
+	doc["version"] = SchemaVersion
+
+	out, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, head.Version, fmt.Errorf("serialize migrated %s: %w", filename, err)
+	}
+	return out, head.Version, nil
+}
+
+func findMigration(from int) (migration, bool) {
+	for _, m := range migrations {
+		if m.from == from {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
+
+// migrateFiles upgrades every data file written with an older schema as soon
+// as the data is opened. It fails if any file comes from a newer today.
+func (s *Storage) migrateFiles() error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, filename := range []string{"tasks.json", "habits.json", "timer.json", trashFile} {
+		if _, err := s.loadMigrated(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMigrated reads a data file, upgrading it on disk if it was written with
+// an older schema. The original is kept next to it as <file>.v<N>.bak, where
+// N is the version it had. A missing file is reported as os.ErrNotExist.
+func (s *Storage) loadMigrated(filename string) ([]byte, error) {
+	path := s.path(filename)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	data, err := s.key.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	migrated, from, err := migrateFile(filename, data)
+	if err != nil || bytes.Equal(migrated, data) {
+		return migrated, err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if !fileExists(backup) {
+		if err := fsutil.WriteFileAtomic(backup, raw, dataFilePerm); err != nil {
+			return nil, fmt.Errorf("keep pre-migration copy of %s: %w", filename, err)
+		}
+	}
+	out, err := s.key.Encode(migrated)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt migrated %s: %w", filename, err)
+	}
+	if err := fsutil.WriteFileAtomic(path, out, dataFilePerm); err != nil {
+		return nil, fmt.Errorf("write migrated %s: %w", filename, err)
+	}
+	return migrated, nil
+}
+
+// migrateExplicitHabitFrequency (0 → 1) writes out the daily frequency that
+// habits created before frequencies existed have implicitly, including
+// habits in the trash.
+func migrateExplicitHabitFrequency(filename string, doc map[string]any) error {
+	var habits []any
+	switch filename {
+	case "habits.json":
+		habits, _ = doc["habits"].([]any)
+	case trashFile:
+		items, _ := doc["items"].([]any)
+		for _, item := range items {
+			if item, ok := item.(map[string]any); ok && item["habit"] != nil {
+				habits = append(habits, item["habit"])
+			}
+		}
+	}
+	for _, habit := range habits {
+		habit, ok := habit.(map[string]any)
+		if !ok {
+			return fmt.Errorf("habit is not an object")
+		}
+		if freq, _ := habit["frequency"].(string); freq == "" {
+			habit["frequency"] = string(FrequencyDaily)
+		}
+	}
+	return nil
+}
+
+// migrateHabitTargets (1 → 2) leaves existing data as it is. Habits gained a
+// target and unit and their logs a value; the bump keeps older builds, which
+// would count partial progress as done and drop the amounts when saving,
+// from opening files written since.
+func migrateHabitTargets(filename string, doc map[string]any) error {
+	return nil
+}
+
+// migrateHabitSkips (2 → 3) leaves existing data as it is. Habit logs can now
+// be skip marks and habits.json has a vacation; older builds would count the
+// marks as completions.
+func migrateHabitSkips(filename string, doc map[string]any) error {
+	return nil
+}
+
+// migrateHabitStates (3 → 4) leaves existing data as it is. Habits can now be
+// paused or archived; older builds would show them as active and count them
+// in reports.
+func migrateHabitStates(filename string, doc map[string]any) error {
+	return nil
+}
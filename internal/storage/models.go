@@ -14,19 +14,56 @@ const (
 
 // Task represents a single todo item
 type Task struct {
-	ID          string     `json:"id"`
-	Text        string     `json:"text"`
-	Project     string     `json:"project,omitempty"`
-	Priority    Priority   `json:"priority,omitempty"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	Done        bool       `json:"done"`
-	CreatedAt   time.Time  `json:"created_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ID          string          `json:"id"`
+	Text        string          `json:"text"`
+	Project     string          `json:"project,omitempty"`
+	Priority    Priority        `json:"priority,omitempty"`
+	DueDate     *time.Time      `json:"due_date,omitempty"`
+	DueTimed    bool            `json:"due_timed,omitempty"`  // DueDate is an exact deadline, not just a day
+	DueZone     string          `json:"due_zone,omitempty"`   // IANA zone a timed deadline was set in
+	StartDate   *time.Time      `json:"start_date,omitempty"` // Hidden from the default list before this day
+	Done        bool            `json:"done"`
+	CreatedAt   time.Time       `json:"created_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Recurrence  *Recurrence     `json:"recurrence,omitempty"`
+	SpawnedFrom string          `json:"spawned_from,omitempty"` // ID of the occurrence this one was rolled forward from
+	Checklist   []ChecklistItem `json:"checklist,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`       // Lowercase, without the leading '#'
+	Notes       string          `json:"notes,omitempty"`      // Free-form Markdown, edited in $EDITOR
+	BlockedBy   []string        `json:"blocked_by,omitempty"` // IDs of tasks that must be done first
+	Position    int             `json:"position,omitempty"`   // Manual sort order (1-based); 0 = never reordered
+}
+
+// ChecklistItem is a step inside a larger task
+type ChecklistItem struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// RecurrenceKind identifies how a recurring task repeats
+type RecurrenceKind string
+
+const (
+	RecurDaily           RecurrenceKind = "daily"            // Every N days from the due date
+	RecurWeekly          RecurrenceKind = "weekly"           // On chosen weekdays (or the due date's weekday)
+	RecurMonthly         RecurrenceKind = "monthly"          // On a day of the month
+	RecurAfterCompletion RecurrenceKind = "after_completion" // N days after the task is completed
+)
+
+// Recurrence describes when the next occurrence of a task is due
+type Recurrence struct {
+	Kind       RecurrenceKind `json:"kind"`
+	Interval   int            `json:"interval,omitempty"`     // Days between occurrences (daily, after_completion); default 1
+	Weekdays   []int          `json:"weekdays,omitempty"`     // 0=Sunday, 1=Monday, etc. (weekly)
+	DayOfMonth int            `json:"day_of_month,omitempty"` // 1-31, clamped to short months (monthly); default: due date's day
 }
 
 // TaskStore holds all tasks
 type TaskStore struct {
-	Tasks []Task `json:"tasks"`
+	Version  int    `json:"version"`            // Schema version; files without one predate versioning
+	Revision int64  `json:"revision,omitempty"` // Bumped on every save to detect stale writes
+	Tasks    []Task `json:"tasks"`
 }
 
 // HabitFrequency represents how often a habit should be done
@@ -46,24 +83,58 @@ type Habit struct {
 	Icon        string         `json:"icon"`
 	Frequency   HabitFrequency `json:"frequency,omitempty"` // Default: daily for backward compatibility
 	CustomDays  []int          `json:"custom_days,omitempty"` // 0=Sunday, 1=Monday, etc.
+	Target      int            `json:"target,omitempty"`      // Amount per day that counts as done; 0 for a yes/no habit
+	Unit        string         `json:"unit,omitempty"`        // Unit of Target, such as "glasses"
+	State       HabitState     `json:"state,omitempty"`       // Paused or archived; "" while active
+	StateSince  string         `json:"state_since,omitempty"` // YYYY-MM-DD the habit was paused or archived
+	Breaks      []DateRange    `json:"breaks,omitempty"`      // Earlier periods the habit was paused or archived
 	CreatedAt   time.Time      `json:"created_at"`
 }
 
-// HabitLog represents a single habit completion
+// HabitState is whether a habit is tracked. Paused and archived habits are
+// left off the checklist and out of reports, but keep their logs.
+type HabitState string
+
+const (
+	HabitActive   HabitState = ""
+	HabitPaused   HabitState = "paused"   // Taking a break; streaks are frozen
+	HabitArchived HabitState = "archived" // No longer tracked; kept for its history
+)
+
+// HabitLog represents a single habit completion, or a day the habit was
+// skipped
 type HabitLog struct {
 	HabitID string `json:"habit_id"`
-	Date    string `json:"date"` // YYYY-MM-DD format
+	Date    string `json:"date"`              // YYYY-MM-DD format
+	Value   int    `json:"value,omitempty"`   // Amount logged for habits with a target; 0 is a plain check-in
+	Skipped bool   `json:"skipped,omitempty"` // Excused that day (sick, travelling); not a completion
 }
 
 // HabitStore holds habits and their logs
 type HabitStore struct {
-	Habits []Habit    `json:"habits"`
-	Logs   []HabitLog `json:"logs"`
+	Version  int        `json:"version"`            // Schema version; files without one predate versioning
+	Revision int64      `json:"revision,omitempty"` // Bumped on every save to detect stale writes
+	Habits   []Habit    `json:"habits"`
+	Logs     []HabitLog `json:"logs"`
+	Vacation *DateRange `json:"vacation,omitempty"` // Days every habit is excused on
+}
+
+// DateRange is an inclusive range of YYYY-MM-DD dates.
+type DateRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Contains reports whether the YYYY-MM-DD date lies in the range. A nil
+// range contains no dates.
+func (r *DateRange) Contains(date string) bool {
+	return r != nil && date >= r.Start && date <= r.End
 }
 
 // TimerEntry represents a completed time tracking entry
 type TimerEntry struct {
 	Project   string    `json:"project"`
+	TaskID    string    `json:"task_id,omitempty"` // Task the time was tracked on, if any
 	StartedAt time.Time `json:"started_at"`
 	EndedAt   time.Time `json:"ended_at"`
 }
@@ -71,11 +142,38 @@ type TimerEntry struct {
 // CurrentTimer represents the actively running timer (if any)
 type CurrentTimer struct {
 	Project   string    `json:"project"`
+	TaskID    string    `json:"task_id,omitempty"` // Task the timer was started from, if any
 	StartedAt time.Time `json:"started_at"`
 }
 
 // TimerStore holds timer state and history
 type TimerStore struct {
-	Current *CurrentTimer `json:"current,omitempty"`
-	Entries []TimerEntry  `json:"entries"`
+	Version  int           `json:"version"`            // Schema version; files without one predate versioning
+	Revision int64         `json:"revision,omitempty"` // Bumped on every save to detect stale writes
+	Current  *CurrentTimer `json:"current,omitempty"`
+	Entries  []TimerEntry  `json:"entries"`
+}
+
+// TrashKind identifies what a trash item holds
+type TrashKind string
+
+const (
+	TrashKindTask  TrashKind = "task"
+	TrashKindHabit TrashKind = "habit"
+)
+
+// TrashItem is a deleted task or habit, kept until it is restored or purged
+type TrashItem struct {
+	Kind      TrashKind  `json:"kind"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	Task      *Task      `json:"task,omitempty"`
+	Habit     *Habit     `json:"habit,omitempty"`
+	Logs      []HabitLog `json:"logs,omitempty"` // The deleted habit's completion logs
+}
+
+// TrashStore holds deleted items
+type TrashStore struct {
+	Version  int         `json:"version"`            // Schema version; files without one predate versioning
+	Revision int64       `json:"revision,omitempty"` // Bumped on every save to detect stale writes
+	Items    []TrashItem `json:"items"`
 }
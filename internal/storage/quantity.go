@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A habit with a target is done on a day once the amount logged for it
+// reaches the target, such as 8 glasses of water. Less than that is partial
+// progress and does not count towards streaks. Habits without a target are
+// plain yes/no habits, done as soon as they have a log. A log without a
+// value is a plain check-in and counts as reaching the target, so habits
+// that gain a target keep their history.
+
+const (
+	maxHabitTarget  = 10000
+	maxHabitUnitLen = 20
+)
+
+// ParseHabitTarget parses a habit target as typed by the user, a number
+// optionally followed by a unit:
+//
+//	8 glasses | 30 min | 10000steps
+//
+// A blank string means no target.
+func ParseHabitTarget(s string) (int, string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, "", nil
+	}
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(s)
+	}
+	target, err := strconv.Atoi(s[:end])
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid target: expected a number and an optional unit, like 8 glasses")
+	}
+	return normalizeHabitTarget(target, s[end:])
+}
+
+// normalizeHabitTarget validates a target and its unit. The unit is trimmed
+// and only kept for habits with a target.
+func normalizeHabitTarget(target int, unit string) (int, string, error) {
+	unit = strings.TrimSpace(unit)
+	switch {
+	case target < 0:
+		return 0, "", fmt.Errorf("invalid target: must not be negative")
+	case target == 0:
+		return 0, "", nil
+	case target > maxHabitTarget:
+		return 0, "", fmt.Errorf("invalid target: max %d", maxHabitTarget)
+	case len(unit) > maxHabitUnitLen:
+		return 0, "", fmt.Errorf("habit unit too long (max %d)", maxHabitUnitLen)
+	}
+	return target, unit, nil
+}
+
+// TargetString renders the habit's target in the syntax accepted by
+// ParseHabitTarget, "" for habits without one.
+func (h Habit) TargetString() string {
+	if h.Target <= 0 {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d %s", h.Target, h.Unit))
+}
+
+// HasTarget reports whether the habit tracks an amount rather than yes/no.
+func (h Habit) HasTarget() bool {
+	return h.Target > 0
+}
+
+// Goal returns the amount that marks the habit done for a day: its target,
+// or 1 for habits without one.
+func (h Habit) Goal() int {
+	if h.Target > 0 {
+		return h.Target
+	}
+	return 1
+}
+
+// ProgressString describes an amount logged against the habit's target,
+// such as "3/8 glasses".
+func (h Habit) ProgressString(value int) string {
+	return strings.TrimSpace(fmt.Sprintf("%d/%d %s", value, h.Goal(), h.Unit))
+}
+
+// amount returns how much the log counts for h. Plain check-ins count as
+// reaching the goal and skip marks as nothing.
+func (l HabitLog) amount(h Habit) int {
+	switch {
+	case l.Skipped:
+		return 0
+	case l.Value > 0:
+		return l.Value
+	}
+	return h.Goal()
+}
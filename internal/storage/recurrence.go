@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdayNames maps the short weekday names used in recurrence rules to
+// time.Weekday values (0=Sunday).
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Validate checks that the recurrence rule is well formed.
+func (r Recurrence) Validate() error {
+	switch r.Kind {
+	case RecurDaily, RecurWeekly, RecurMonthly, RecurAfterCompletion:
+	default:
+		return fmt.Errorf("invalid recurrence: unknown kind %q", r.Kind)
+	}
+	// A zero interval is the stored default and means every day.
+	if r.Interval < 0 || r.Interval > 365 {
+		return fmt.Errorf("invalid recurrence: interval must be 1-365 days, or 0 for the default of 1, got %d", r.Interval)
+	}
+	for _, day := range r.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("invalid recurrence: weekday must be 0-6, got %d", day)
+		}
+	}
+	if r.DayOfMonth < 0 || r.DayOfMonth > 31 {
+		return fmt.Errorf("invalid recurrence: day of month must be 1-31, got %d", r.DayOfMonth)
+	}
+	return nil
+}
+
+// String renders the rule in the same syntax accepted by ParseRecurrence.
+func (r Recurrence) String() string {
+	switch r.Kind {
+	case RecurDaily:
+		if r.interval() == 1 {
+			return "daily"
+		}
+		return fmt.Sprintf("every %d days", r.interval())
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return "weekly"
+		}
+		names := make([]string, 0, len(r.Weekdays))
+		for _, day := range r.Weekdays {
+			if day >= 0 && day < len(weekdayNames) {
+				names = append(names, weekdayNames[day])
+			}
+		}
+		return "weekly " + strings.Join(names, ",")
+	case RecurMonthly:
+		if r.DayOfMonth == 0 {
+			return "monthly"
+		}
+		return fmt.Sprintf("monthly %d", r.DayOfMonth)
+	case RecurAfterCompletion:
+		if r.interval() == 1 {
+			return "after 1 day"
+		}
+		return fmt.Sprintf("after %d days", r.interval())
+	default:
+		return string(r.Kind)
+	}
+}
+
+// ParseRecurrence parses a human-friendly recurrence rule:
+//
+//	daily | every N days | weekly [mon,wed,...] | monthly [N] | after N days
+//
+// A blank string or "none" means the task does not repeat (nil, nil).
+func ParseRecurrence(s string) (*Recurrence, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(s)))
+	if len(fields) == 0 || (len(fields) == 1 && (fields[0] == "none" || fields[0] == "-")) {
+		return nil, nil
+	}
+
+	var r Recurrence
+	switch fields[0] {
+	case "daily":
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("invalid recurrence: use \"every N days\" for intervals")
+		}
+		r = Recurrence{Kind: RecurDaily}
+	case "every":
+		n, err := parseDayCount(fields[1:])
+		if err != nil {
+			return nil, err
+		}
+		r = Recurrence{Kind: RecurDaily, Interval: n}
+	case "after":
+		n, err := parseDayCount(fields[1:])
+		if err != nil {
+			return nil, err
+		}
+		r = Recurrence{Kind: RecurAfterCompletion, Interval: n}
+	case "weekly":
+		r = Recurrence{Kind: RecurWeekly}
+		for _, field := range fields[1:] {
+			for _, name := range strings.Split(field, ",") {
+				if name == "" {
+					continue
+				}
+				day := weekdayIndex(name)
+				if day < 0 {
+					return nil, fmt.Errorf("invalid recurrence: unknown weekday %q", name)
+				}
+				r.Weekdays = appendWeekday(r.Weekdays, day)
+			}
+		}
+	case "monthly":
+		r = Recurrence{Kind: RecurMonthly}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid recurrence: use \"monthly N\"")
+		}
+		if len(fields) == 2 {
+			day, err := strconv.Atoi(fields[1])
+			if err != nil || day < 1 || day > 31 {
+				return nil, fmt.Errorf("invalid recurrence: day of month must be 1-31")
+			}
+			r.DayOfMonth = day
+		}
+	default:
+		return nil, fmt.Errorf("invalid recurrence: expected daily, every N days, weekly, monthly or after N days")
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// parseDayCount parses the "N days" tail of "every N days" / "after N days".
+func parseDayCount(fields []string) (int, error) {
+	if len(fields) != 2 || (fields[1] != "day" && fields[1] != "days") {
+		return 0, fmt.Errorf("invalid recurrence: expected \"N days\"")
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n < 1 || n > 365 {
+		return 0, fmt.Errorf("invalid recurrence: interval must be between 1 and 365 days")
+	}
+	return n, nil
+}
+
+// weekdayIndex resolves a weekday name (or any prefix of at least 2 letters
+// of its full name) to 0-6, returning -1 if unknown.
+func weekdayIndex(name string) int {
+	for i := 0; i < 7; i++ {
+		full := strings.ToLower(time.Weekday(i).String())
+		if name == weekdayNames[i] || (len(name) >= 2 && strings.HasPrefix(full, name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// appendWeekday adds day to days keeping the slice sorted and unique.
+func appendWeekday(days []int, day int) []int {
+	for i, d := range days {
+		if d == day {
+			return days
+		}
+		if d > day {
+			days = append(days, 0)
+			copy(days[i+1:], days[i:])
+			days[i] = day
+			return days
+		}
+	}
+	return append(days, day)
+}
+
+func (r Recurrence) interval() int {
+	if r.Interval <= 0 {
+		return 1
+	}
+	return r.Interval
+}
+
+// NextDue computes the due date of the occurrence that follows a task
+// completed at completedAt. Rules anchored to the calendar (daily, weekly,
+// monthly) roll forward from the previous due date, skipping occurrences that
+// are already in the past; after_completion counts from the completion day.
+// Tasks without a due date are anchored to the completion day. The time of
+// day of the previous due date is preserved.
+func (r Recurrence) NextDue(due *time.Time, completedAt time.Time) time.Time {
+	doneDay := startOfDay(completedAt)
+	if r.Kind == RecurAfterCompletion {
+		return doneDay.AddDate(0, 0, r.interval())
+	}
+
+	base := doneDay
+	if due != nil {
+		base = *due
+	}
+
+	dayOfMonth := r.DayOfMonth
+	if dayOfMonth == 0 {
+		dayOfMonth = base.Day()
+	}
+
+	next := r.step(base, dayOfMonth)
+	// Skip missed occurrences so the next one is never already overdue.
+	// Bounded so a malformed rule can never spin forever.
+	for i := 0; i < 1000 && !startOfDay(next).After(doneDay); i++ {
+		next = r.step(next, dayOfMonth)
+	}
+	return next
+}
+
+// step returns the first occurrence strictly after t.
+func (r Recurrence) step(t time.Time, dayOfMonth int) time.Time {
+	switch r.Kind {
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return t.AddDate(0, 0, 7)
+		}
+		for offset := 1; offset <= 7; offset++ {
+			candidate := t.AddDate(0, 0, offset)
+			for _, day := range r.Weekdays {
+				if int(candidate.Weekday()) == day {
+					return candidate
+				}
+			}
+		}
+		return t.AddDate(0, 0, 7)
+	case RecurMonthly:
+		candidate := dateInMonth(t, t.Year(), t.Month(), dayOfMonth)
+		if !candidate.After(t) {
+			candidate = dateInMonth(t, t.Year(), t.Month()+1, dayOfMonth)
+		}
+		return candidate
+	default:
+		return t.AddDate(0, 0, r.interval())
+	}
+}
+
+// dateInMonth returns day of the given month (clamped to the month's length)
+// at t's time of day and location.
+func dateInMonth(t time.Time, year int, month time.Month, day int) time.Time {
+	// Day 0 of the following month is the last day of this one.
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
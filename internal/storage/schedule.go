@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A habit's frequency decides the days it is due. Streaks and progress only
+// look at those days: a missed day the habit is not due on does not break a
+// streak, and doing it anyway does not extend one. Weekly habits are due once
+// per week (Sunday to Saturday) and their streaks count weeks.
+//
+// Days a habit is skipped on, and days of the vacation, freeze its streak:
+// they neither break nor extend it, and are not expected in its progress.
+// A weekly habit skipped on any day of a week it was not done in is excused
+// for that week. Days a habit spends paused or archived are excused the same
+// way, so resuming it picks its streak up where it stopped.
+
+// ParseHabitSchedule parses a habit schedule as typed by the user:
+//
+//	daily | weekdays | weekly | mon,wed,fri
+//
+// A blank string means daily. Days may be separated by commas or spaces and
+// are returned sorted.
+func ParseHabitSchedule(s string) (HabitFrequency, []int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", string(FrequencyDaily):
+		return FrequencyDaily, nil, nil
+	case string(FrequencyWeekdays):
+		return FrequencyWeekdays, nil, nil
+	case string(FrequencyWeekly):
+		return FrequencyWeekly, nil, nil
+	}
+
+	var days []int
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		day := weekdayIndex(name)
+		if day < 0 {
+			return "", nil, fmt.Errorf("invalid frequency: expected daily, weekdays, weekly or days like mon,wed,fri; unknown weekday %q", name)
+		}
+		days = appendWeekday(days, day)
+	}
+	return FrequencyCustom, days, nil
+}
+
+// normalizeHabitSchedule validates a frequency and its custom days. An empty
+// frequency means daily; custom days are sorted and only kept for custom
+// habits.
+func normalizeHabitSchedule(frequency HabitFrequency, customDays []int) (HabitFrequency, []int, error) {
+	switch frequency {
+	case "":
+		return FrequencyDaily, nil, nil
+	case FrequencyDaily, FrequencyWeekdays, FrequencyWeekly:
+		return frequency, nil, nil
+	case FrequencyCustom:
+	default:
+		return "", nil, fmt.Errorf("invalid frequency %q", frequency)
+	}
+
+	var days []int
+	for _, day := range customDays {
+		if day < 0 || day > 6 {
+			return "", nil, fmt.Errorf("invalid frequency: weekday must be 0-6, got %d", day)
+		}
+		days = appendWeekday(days, day)
+	}
+	if len(days) == 0 {
+		return "", nil, fmt.Errorf("invalid frequency: pick at least one day")
+	}
+	return FrequencyCustom, days, nil
+}
+
+// ScheduleString renders the habit's schedule in the syntax accepted by
+// ParseHabitSchedule.
+func (h Habit) ScheduleString() string {
+	if h.EffectiveFrequency() != FrequencyCustom {
+		return string(h.EffectiveFrequency())
+	}
+	names := make([]string, 0, len(h.CustomDays))
+	for _, day := range h.CustomDays {
+		if day >= 0 && day < len(weekdayNames) {
+			names = append(names, weekdayNames[day])
+		}
+	}
+	if len(names) == 0 {
+		return string(FrequencyDaily)
+	}
+	return strings.Join(names, ",")
+}
+
+// EffectiveFrequency returns the habit's frequency, daily if it has none.
+func (h Habit) EffectiveFrequency() HabitFrequency {
+	switch h.Frequency {
+	case FrequencyWeekly, FrequencyWeekdays, FrequencyCustom:
+		return h.Frequency
+	}
+	return FrequencyDaily
+}
+
+// IsScheduledOn reports whether the habit is due on the given weekday.
+// Weekly habits can be done on any day. A custom habit without valid days is
+// treated as daily.
+func (h Habit) IsScheduledOn(day time.Weekday) bool {
+	switch h.EffectiveFrequency() {
+	case FrequencyWeekdays:
+		return day >= time.Monday && day <= time.Friday
+	case FrequencyCustom:
+		valid := false
+		for _, d := range h.CustomDays {
+			if d == int(day) {
+				return true
+			}
+			valid = valid || d >= 0 && d <= 6
+		}
+		return !valid
+	}
+	return true
+}
+
+// StreakUnit names what the habit's streak counts: "weeks" for weekly
+// habits, "days" otherwise.
+func (h Habit) StreakUnit() string {
+	if h.EffectiveFrequency() == FrequencyWeekly {
+		return "weeks"
+	}
+	return "days"
+}
+
+// IsActive reports whether the habit is tracked, neither paused nor
+// archived.
+func (h Habit) IsActive() bool {
+	return h.State == HabitActive
+}
+
+// ActiveOn reports whether the habit was tracked on a YYYY-MM-DD date: not
+// paused or archived since then, nor during an earlier break. Habits loaded
+// through Storage always have a StateSince when they are not active.
+func (h Habit) ActiveOn(date string) bool {
+	if !h.IsActive() && date >= h.StateSince {
+		return false
+	}
+	for _, r := range h.Breaks {
+		if r.Contains(date) {
+			return false
+		}
+	}
+	return true
+}
+
+// findHabit returns the habit with the given ID. Unknown habits are
+// treated as daily.
+func findHabit(store *HabitStore, habitID string) Habit {
+	for _, h := range store.Habits {
+		if h.ID == habitID {
+			return h
+		}
+	}
+	return Habit{ID: habitID}
+}
+
+// habitDates returns the dates (YYYY-MM-DD) the habit was done, reaching
+// its target, and the earliest of them.
+func habitDates(store *HabitStore, h Habit) (map[string]bool, string) {
+	dates := make(map[string]bool)
+	earliest := ""
+	for _, log := range store.Logs {
+		if log.HabitID != h.ID || log.amount(h) < h.Goal() {
+			continue
+		}
+		dates[log.Date] = true
+		if earliest == "" || log.Date < earliest {
+			earliest = log.Date
+		}
+	}
+	return dates, earliest
+}
+
+// habitExcuses returns a function reporting whether the habit is excused on
+// a YYYY-MM-DD date, by a skip mark, the vacation or not being active.
+func habitExcuses(store *HabitStore, h Habit) func(date string) bool {
+	skipped := make(map[string]bool)
+	for _, log := range store.Logs {
+		if log.HabitID == h.ID && log.Skipped {
+			skipped[log.Date] = true
+		}
+	}
+	return func(date string) bool {
+		return skipped[date] || store.Vacation.Contains(date) || !h.ActiveOn(date)
+	}
+}
+
+// dailyStreak counts the due days done in a row up to day, skipping days
+// the habit is not due on or is excused on. If day itself is not done yet
+// it does not break the streak.
+func dailyStreak(h Habit, dates map[string]bool, excused func(string) bool, earliest string, day time.Time) int {
+	streak := 0
+	if !dates[day.Format("2006-01-02")] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for {
+		key := day.Format("2006-01-02")
+		if key < earliest {
+			return streak
+		}
+		if h.IsScheduledOn(day.Weekday()) {
+			switch {
+			case dates[key]:
+				streak++
+			case !excused(key):
+				return streak
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// weeklyStreak counts the weeks in a row up to day's week with at least one
+// completion. Weeks without one that the habit was excused in are passed
+// over. Days after day are ignored, and if day's week has no completion yet
+// it does not break the streak.
+func weeklyStreak(dates map[string]bool, excused func(string) bool, earliest string, day time.Time) int {
+	anyInWeek := func(set func(string) bool, weekStart time.Time, days int) bool {
+		for i := 0; i < days; i++ {
+			if set(weekStart.AddDate(0, 0, i).Format("2006-01-02")) {
+				return true
+			}
+		}
+		return false
+	}
+	done := func(date string) bool { return dates[date] }
+
+	week := startOfWeekSunday(day)
+	streak := 0
+	if anyInWeek(done, week, int(day.Weekday())+1) {
+		streak++
+	}
+	for {
+		week = week.AddDate(0, 0, -7)
+		switch {
+		case week.AddDate(0, 0, 6).Format("2006-01-02") < earliest:
+			return streak
+		case anyInWeek(done, week, 7):
+			streak++
+		case !anyInWeek(excused, week, 7):
+			return streak
+		}
+	}
+}
@@ -5,31 +5,52 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"today/internal/crypt"
 	"today/internal/fsutil"
 )
 
 // SaveContext contains information about a save operation for semantic commit messages.
 // It provides context about what operation was performed, enabling meaningful git commits
-// like "Complete task: Review PR" instead of generic "Update tasks".
+// like "Complete task: Review PR" instead of generic "Update tasks". Every SaveContext is
+// also appended to the activity journal (see journal.go).
 type SaveContext struct {
-	Filename  string // The file being saved (e.g., "tasks.json")
-	Operation string // The operation type: "add", "complete", "delete", "toggle", "start", "stop", "update"
-	ItemType  string // The item type: "task", "habit", "timer"
-	ItemName  string // Human-readable name (truncated task text, habit name, project name)
+	Filename  string          // The file being saved (e.g., "tasks.json")
+	Files     []string        // Other data-relative files the operation wrote (archives, the trash)
+	Operation string          // The operation type: "add", "complete", "delete", "toggle", "log", "skip", "pause", "resume", "start", "stop", "update"
+	ItemType  string          // The item type: "task", "habit", "timer"
+	ItemName  string          // Human-readable name (truncated task text, habit name, project name)
+	ItemID    string          // ID of the task or habit, when there is a single one
+	Before    json.RawMessage // The item before the operation, for the activity journal
+	After     json.RawMessage // The item after the operation, for the activity journal
 }
 
 // Storage handles all file I/O operations
 type Storage struct {
 	dataDir           string
+	key               *crypt.Key             // Encrypts data files at rest; nil keeps them in plaintext
+	mu                sync.Mutex             // Held for each load-modify-save cycle (see update)
+	instance          *Instance              // This process, once registered (see RegisterInstance)
+	fileMu            sync.Mutex             // Guards stamps, cache, dirty, batching and pending
+	stamps            map[string]fileStamp   // Data files as last written or seen (see ExternalChanges)
+	cache             map[string]*cachedFile // Parsed collections by file (see cache.go)
+	dirty             []string               // Files saved in the current update cycle, in order
+	batching          bool                   // Inside an update cycle, so saves are deferred
+	pending           []SaveContext          // Notifications held until the cycle's writes land
 	onSave            func(filename string)  // Legacy callback triggered after file saves
 	onSaveWithContext func(ctx SaveContext)  // Context-aware callback for semantic commits
+	onActivityError   func(err error)        // Reports changes the journal could not record
 	now               func() time.Time       // injectable clock for deterministic tests
 }
 
@@ -42,16 +63,34 @@ const (
 	maxHabitNameLen = 60
 	maxHabitIconLen = 12
 	maxTimerProjLen = 60
+	maxTagLen       = 30
+	maxTagsPerTask  = 20
+	maxNotesLen     = 20000
 )
 
-// New creates a new Storage instance with the given data directory
+// New creates a new Storage instance with the given data directory. Data
+// encrypted with "today encrypt" has to be opened with NewWithKey.
 func New(dataDir string) (*Storage, error) {
+	return NewWithKey(dataDir, nil)
+}
+
+// NewWithKey creates a Storage whose data files are encrypted with key, or
+// kept in plaintext if key is nil.
+func NewWithKey(dataDir string, key *crypt.Key) (*Storage, error) {
 	// Create data directory if it doesn't exist
 	if err := os.MkdirAll(dataDir, dataDirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
+	if key == nil && crypt.Enabled(dataDir) {
+		return nil, fmt.Errorf("%w (%s)", crypt.ErrLocked, dataDir)
+	}
+
+	s := &Storage{dataDir: dataDir, key: key, now: time.Now}
 
-	s := &Storage{dataDir: dataDir, now: time.Now}
+	// Upgrade files from older versions, refusing ones from newer versions
+	if err := s.migrateFiles(); err != nil {
+		return nil, err
+	}
 
 	// Initialize files if they don't exist
 	if err := s.initFiles(); err != nil {
@@ -124,6 +163,13 @@ func (s *Storage) initFiles() error {
 		}
 	}
 
+	// Trash
+	if !fileExists(s.path(trashFile)) {
+		if err := s.SaveTrash(&TrashStore{Items: []TrashItem{}}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -150,13 +196,18 @@ func (s *Storage) writeJSONAtomic(filename string, v any) error {
 	if err != nil {
 		return fmt.Errorf("serialize %s: %w", filename, err)
 	}
+	if data, err = s.key.Encode(data); err != nil {
+		return fmt.Errorf("encrypt %s: %w", filename, err)
+	}
 
-	// Keep a best-effort backup before overwriting.
+	// Keep a best-effort backup before overwriting. It is a copy of the
+	// file as it is on disk, so it is encrypted whenever the data is.
 	fsutil.BestEffortBackup(path, dataFilePerm)
 
 	if err := fsutil.WriteFileAtomic(path, data, dataFilePerm); err != nil {
 		return fmt.Errorf("write %s: %w", filename, err)
 	}
+	s.recordStamp(filename)
 
 	// Trigger legacy callback after successful write (for backward compatibility)
 	if s.onSave != nil {
@@ -166,9 +217,17 @@ func (s *Storage) writeJSONAtomic(filename string, v any) error {
 	return nil
 }
 
-// notifySaveWithContext triggers the context-aware callback if registered.
+// notifySaveWithContext records the operation in the activity journal and
+// triggers the context-aware callback if registered.
 // This should be called after writeJSONAtomic when semantic context is available.
+// Inside an update cycle it is held back until the cycle's writes succeed.
 func (s *Storage) notifySaveWithContext(ctx SaveContext) {
+	if s.deferNotification(ctx) {
+		return
+	}
+	if err := s.appendActivity(ctx); err != nil {
+		s.reportActivityError(err)
+	}
 	if s.onSaveWithContext != nil {
 		s.onSaveWithContext(ctx)
 	}
@@ -183,16 +242,15 @@ func truncateForCommit(s string, maxLen int) string {
 }
 
 func (s *Storage) loadJSONWithRecovery(filename string, v any) error {
-	path := s.path(filename)
-	data, err := os.ReadFile(path)
+	data, err := s.loadMigrated(filename)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, os.ErrNotExist) {
 			if err := s.writeJSONAtomic(filename, v); err != nil {
 				return err
 			}
 			return nil
 		}
-		return fmt.Errorf("read %s: %w", filename, err)
+		return err
 	}
 
 	if len(bytes.TrimSpace(data)) == 0 {
@@ -210,6 +268,9 @@ func (s *Storage) recoverCorruptJSON(filename string, v any, cause error) error
 
 	// Try backup first.
 	bakData, bakErr := os.ReadFile(path + ".bak")
+	if bakErr == nil {
+		bakData, bakErr = s.key.Decode(bakData)
+	}
 	if bakErr == nil && len(bytes.TrimSpace(bakData)) > 0 {
 		if err := json.Unmarshal(bakData, v); err == nil {
 			corruptPath := fmt.Sprintf("%s.corrupt.%s", path, time.Now().Format("20060102-150405"))
@@ -232,37 +293,57 @@ func (s *Storage) recoverCorruptJSON(filename string, v any, cause error) error
 
 // LoadTasks reads tasks from disk
 func (s *Storage) LoadTasks() (*TaskStore, error) {
-	store := TaskStore{Tasks: []Task{}}
-	err := s.loadJSONWithRecovery("tasks.json", &store)
+	return s.readTasks(false)
+}
+
+// loadTasks is LoadTasks for the body of an update cycle, which sees the
+// cycle's own saves before they are written.
+func (s *Storage) loadTasks() (*TaskStore, error) {
+	return s.readTasks(true)
+}
+
+func (s *Storage) readTasks(working bool) (*TaskStore, error) {
+	store := TaskStore{Version: SchemaVersion, Tasks: []Task{}}
+	err := s.loadCollection("tasks.json", &store, working)
 	return &store, err
 }
 
 // SaveTasks writes tasks to disk
 func (s *Storage) SaveTasks(store *TaskStore) error {
-	return s.writeJSONAtomic("tasks.json", store)
+	store.Version = SchemaVersion
+	return s.saveCollection("tasks.json", store)
 }
 
-// AddTask adds a new task with optional priority and due date
-func (s *Storage) AddTask(text, project string, priority Priority, dueDate *time.Time) (*Task, error) {
-	text = strings.TrimSpace(text)
-	project = strings.TrimSpace(project)
+// AddTask adds a new task with optional priority, due date and tags
+func (s *Storage) AddTask(text, project string, priority Priority, dueDate *time.Time, tags ...string) (*Task, error) {
+	return s.CreateTask(Task{Text: text, Project: project, Priority: priority, DueDate: dueDate, Tags: tags})
+}
 
-	if text == "" {
-		return nil, fmt.Errorf("task text is required")
-	}
-	if len(text) > maxTaskTextLen {
-		return nil, fmt.Errorf("task text too long (max %d)", maxTaskTextLen)
-	}
-	if len(project) > maxProjectLen {
-		return nil, fmt.Errorf("project too long (max %d)", maxProjectLen)
-	}
+// CreateTask creates a new task from the fields set when adding one (text,
+// project, priority, due date with its time and zone, and tags) in a single
+// save. Its ID and creation time are assigned.
+func (s *Storage) CreateTask(task Task) (*Task, error) {
+	var created *Task
+	err := s.update(func() (err error) {
+		created, err = s.createTask(task)
+		return err
+	})
+	return created, err
+}
 
-	// Validate priority if provided
-	if priority != "" && priority != PriorityLow && priority != PriorityMedium && priority != PriorityHigh {
-		return nil, fmt.Errorf("invalid priority: must be low, medium, or high")
+func (s *Storage) createTask(fields Task) (*Task, error) {
+	text := strings.TrimSpace(fields.Text)
+	project := strings.TrimSpace(fields.Project)
+
+	if err := validateTaskFields(text, project, fields.Priority); err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(fields.Tags)
+	if err != nil {
+		return nil, err
 	}
 
-	store, err := s.LoadTasks()
+	store, err := s.loadTasks()
 	if err != nil {
 		return nil, err
 	}
@@ -276,10 +357,14 @@ func (s *Storage) AddTask(text, project string, priority Priority, dueDate *time
 		ID:        id,
 		Text:      text,
 		Project:   project,
-		Priority:  priority,
-		DueDate:   dueDate,
+		Priority:  fields.Priority,
+		DueDate:   fields.DueDate,
 		Done:      false,
 		CreatedAt: time.Now(),
+		Tags:      tags,
+	}
+	if task.DueDate != nil && fields.DueTimed {
+		task.DueTimed, task.DueZone = true, fields.DueZone
 	}
 
 	store.Tasks = append(store.Tasks, task)
@@ -294,6 +379,8 @@ func (s *Storage) AddTask(text, project string, priority Priority, dueDate *time
 		Operation: "add",
 		ItemType:  "task",
 		ItemName:  truncateForCommit(task.Text, 50),
+		ItemID:    task.ID,
+		After:     snapshot(task),
 	})
 
 	return &task, nil
@@ -302,23 +389,31 @@ func (s *Storage) AddTask(text, project string, priority Priority, dueDate *time
 // RestoreTask restores a previously existing task (used for undo/redo).
 // It preserves the task ID and timestamps.
 func (s *Storage) RestoreTask(task Task) error {
+	return s.update(func() error { return s.restoreTask(task) })
+}
+
+func (s *Storage) restoreTask(task Task) error {
 	task.Text = strings.TrimSpace(task.Text)
 	task.Project = strings.TrimSpace(task.Project)
 
 	if strings.TrimSpace(task.ID) == "" {
 		return fmt.Errorf("task id is required")
 	}
-	if task.Text == "" {
-		return fmt.Errorf("task text is required")
+	if err := validateTaskFields(task.Text, task.Project, task.Priority); err != nil {
+		return err
 	}
-	if len(task.Text) > maxTaskTextLen {
-		return fmt.Errorf("task text too long (max %d)", maxTaskTextLen)
+	if task.Recurrence != nil {
+		if err := task.Recurrence.Validate(); err != nil {
+			return err
+		}
 	}
-	if len(task.Project) > maxProjectLen {
-		return fmt.Errorf("project too long (max %d)", maxProjectLen)
+	tags, err := normalizeTags(task.Tags)
+	if err != nil {
+		return err
 	}
-	if task.Priority != "" && task.Priority != PriorityLow && task.Priority != PriorityMedium && task.Priority != PriorityHigh {
-		return fmt.Errorf("invalid priority: must be low, medium, or high")
+	task.Tags = tags
+	if err := validateNotes(task.Notes); err != nil {
+		return err
 	}
 	if task.CreatedAt.IsZero() {
 		task.CreatedAt = time.Now()
@@ -331,7 +426,7 @@ func (s *Storage) RestoreTask(task Task) error {
 		task.CompletedAt = nil
 	}
 
-	store, err := s.LoadTasks()
+	store, err := s.loadTasks()
 	if err != nil {
 		return err
 	}
@@ -345,31 +440,170 @@ func (s *Storage) RestoreTask(task Task) error {
 	if err := s.SaveTasks(store); err != nil {
 		return err
 	}
+	// Undoing a delete takes the task back out of the trash.
+	if _, err := s.removeFromTrash(task.ID); err != nil {
+		return err
+	}
 
 	// Notify with semantic context for git commit
 	s.notifySaveWithContext(SaveContext{
 		Filename:  "tasks.json",
+		Files:     []string{trashFile},
 		Operation: "restore",
 		ItemType:  "task",
 		ItemName:  truncateForCommit(task.Text, 50),
+		ItemID:    task.ID,
+		After:     snapshot(task),
 	})
 
 	return nil
 }
 
-// CompleteTask marks a task as done
+// UpdateTask replaces the editable fields (text, project, priority, due date
+// and time, start date, recurrence, tags, notes) of an existing task. The task ID, creation time
+// and completion state are preserved so edits never lose history.
+func (s *Storage) UpdateTask(task Task) error {
+	return s.update(func() error { return s.updateTask(task) })
+}
+
+func (s *Storage) updateTask(task Task) error {
+	task.Text = strings.TrimSpace(task.Text)
+	task.Project = strings.TrimSpace(task.Project)
+
+	if strings.TrimSpace(task.ID) == "" {
+		return fmt.Errorf("task id is required")
+	}
+	if err := validateTaskFields(task.Text, task.Project, task.Priority); err != nil {
+		return err
+	}
+	if task.Recurrence != nil {
+		if err := task.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+	tags, err := normalizeTags(task.Tags)
+	if err != nil {
+		return err
+	}
+	task.Tags = tags
+	if err := validateNotes(task.Notes); err != nil {
+		return err
+	}
+	if task.DueDate == nil || !task.DueTimed {
+		task.DueTimed, task.DueZone = false, ""
+	}
+
+	store, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
+
+	for i := range store.Tasks {
+		if store.Tasks[i].ID == task.ID {
+			before := snapshot(store.Tasks[i])
+			store.Tasks[i].Text = task.Text
+			store.Tasks[i].Project = task.Project
+			store.Tasks[i].Priority = task.Priority
+			store.Tasks[i].DueDate = task.DueDate
+			store.Tasks[i].DueTimed = task.DueTimed
+			store.Tasks[i].DueZone = task.DueZone
+			store.Tasks[i].StartDate = task.StartDate
+			store.Tasks[i].Recurrence = task.Recurrence
+			store.Tasks[i].Tags = task.Tags
+			store.Tasks[i].Notes = task.Notes
+			if err := s.SaveTasks(store); err != nil {
+				return err
+			}
+			// Notify with semantic context for git commit
+			s.notifySaveWithContext(SaveContext{
+				Filename:  "tasks.json",
+				Operation: "update",
+				ItemType:  "task",
+				ItemName:  truncateForCommit(task.Text, 50),
+				ItemID:    task.ID,
+				Before:    before,
+				After:     snapshot(store.Tasks[i]),
+			})
+			return nil
+		}
+	}
+
+	return fmt.Errorf("task not found: %s", task.ID)
+}
+
+// SetTaskNotes replaces only the notes of a task, leaving the rest of it as
+// it is on disk. It returns the task before and after the change.
+func (s *Storage) SetTaskNotes(id, notes string) (before, after *Task, err error) {
+	err = s.update(func() (err error) {
+		before, after, err = s.setTaskNotes(id, notes)
+		return err
+	})
+	return before, after, err
+}
+
+func (s *Storage) setTaskNotes(id, notes string) (*Task, *Task, error) {
+	if err := validateNotes(notes); err != nil {
+		return nil, nil, err
+	}
+
+	store, err := s.loadTasks()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range store.Tasks {
+		if store.Tasks[i].ID != id {
+			continue
+		}
+		before := store.Tasks[i]
+		store.Tasks[i].Notes = notes
+		after := store.Tasks[i]
+		if err := s.SaveTasks(store); err != nil {
+			return nil, nil, err
+		}
+		// Notify with semantic context for git commit
+		s.notifySaveWithContext(SaveContext{
+			Filename:  "tasks.json",
+			Operation: "update",
+			ItemType:  "task",
+			ItemName:  truncateForCommit(after.Text, 50),
+			ItemID:    id,
+			Before:    snapshot(before),
+			After:     snapshot(after),
+		})
+		return &before, &after, nil
+	}
+
+	return nil, nil, fmt.Errorf("task not found: %s", id)
+}
+
+// CompleteTask marks a task as done. Completing a recurring task also adds
+// its next occurrence, due according to the task's recurrence rule.
 func (s *Storage) CompleteTask(id string) error {
-	store, err := s.LoadTasks()
+	return s.update(func() error { return s.completeTask(id) })
+}
+
+func (s *Storage) completeTask(id string) error {
+	store, err := s.loadTasks()
 	if err != nil {
 		return err
 	}
 
 	for i := range store.Tasks {
 		if store.Tasks[i].ID == id {
-			taskText := store.Tasks[i].Text
+			task := store.Tasks[i]
+			taskText := task.Text
+			now := s.Now()
 			store.Tasks[i].Done = true
-			now := time.Now()
 			store.Tasks[i].CompletedAt = &now
+			after := snapshot(store.Tasks[i])
+			if task.Recurrence != nil && !task.Done {
+				next, err := nextOccurrence(task, now)
+				if err != nil {
+					return err
+				}
+				store.Tasks = append(store.Tasks, next)
+			}
 			if err := s.SaveTasks(store); err != nil {
 				return err
 			}
@@ -379,6 +613,9 @@ func (s *Storage) CompleteTask(id string) error {
 				Operation: "complete",
 				ItemType:  "task",
 				ItemName:  truncateForCommit(taskText, 50),
+				ItemID:    id,
+				Before:    snapshot(task),
+				After:     after,
 			})
 			return nil
 		}
@@ -387,18 +624,36 @@ func (s *Storage) CompleteTask(id string) error {
 	return fmt.Errorf("task not found: %s", id)
 }
 
-// UncompleteTask marks a task as not done
+// UncompleteTask marks a task as not done. If completing it spawned a next
+// occurrence that is still pending and unedited, that occurrence is removed
+// again so reopening a recurring task does not leave a duplicate behind. An
+// occurrence that was edited since is kept.
 func (s *Storage) UncompleteTask(id string) error {
-	store, err := s.LoadTasks()
+	return s.update(func() error { return s.uncompleteTask(id) })
+}
+
+func (s *Storage) uncompleteTask(id string) error {
+	store, err := s.loadTasks()
 	if err != nil {
 		return err
 	}
 
 	for i := range store.Tasks {
 		if store.Tasks[i].ID == id {
-			taskText := store.Tasks[i].Text
+			task := store.Tasks[i]
+			taskText := task.Text
+			before := snapshot(task)
 			store.Tasks[i].Done = false
 			store.Tasks[i].CompletedAt = nil
+			after := snapshot(store.Tasks[i])
+			kept := store.Tasks[:0]
+			for _, t := range store.Tasks {
+				if t.SpawnedFrom == id && !t.Done && spawnedUnchanged(t, task) {
+					continue
+				}
+				kept = append(kept, t)
+			}
+			store.Tasks = kept
 			if err := s.SaveTasks(store); err != nil {
 				return err
 			}
@@ -408,6 +663,9 @@ func (s *Storage) UncompleteTask(id string) error {
 				Operation: "reopen",
 				ItemType:  "task",
 				ItemName:  truncateForCommit(taskText, 50),
+				ItemID:    id,
+				Before:    before,
+				After:     after,
 			})
 			return nil
 		}
@@ -416,16 +674,25 @@ func (s *Storage) UncompleteTask(id string) error {
 	return fmt.Errorf("task not found: %s", id)
 }
 
-// DeleteTask removes a task
+// DeleteTask moves a task to the trash
 func (s *Storage) DeleteTask(id string) error {
-	store, err := s.LoadTasks()
+	return s.update(func() error { return s.deleteTask(id) })
+}
+
+func (s *Storage) deleteTask(id string) error {
+	store, err := s.loadTasks()
 	if err != nil {
 		return err
 	}
 
 	for i := range store.Tasks {
 		if store.Tasks[i].ID == id {
-			taskText := store.Tasks[i].Text
+			task := store.Tasks[i]
+			taskText := task.Text
+			// The trash is written first, so an interrupted delete never loses the task.
+			if err := s.addToTrash(TrashItem{Kind: TrashKindTask, DeletedAt: s.Now(), Task: &task}); err != nil {
+				return err
+			}
 			store.Tasks = append(store.Tasks[:i], store.Tasks[i+1:]...)
 			if err := s.SaveTasks(store); err != nil {
 				return err
@@ -433,9 +700,12 @@ func (s *Storage) DeleteTask(id string) error {
 			// Notify with semantic context for git commit
 			s.notifySaveWithContext(SaveContext{
 				Filename:  "tasks.json",
+				Files:     []string{trashFile},
 				Operation: "delete",
 				ItemType:  "task",
 				ItemName:  truncateForCommit(taskText, 50),
+				ItemID:    id,
+				Before:    snapshot(task),
 			})
 			return nil
 		}
@@ -444,12 +714,15 @@ func (s *Storage) DeleteTask(id string) error {
 	return fmt.Errorf("task not found: %s", id)
 }
 
-// SortTasks sorts tasks by priority (high > medium > low) and due date (earliest first)
+// SortTasks sorts tasks by priority (high > medium > low) and due date (earliest first).
+// Blocked tasks sort below actionable ones.
 func (s *Storage) SortTasks(tasks []Task) []Task {
 	sorted := make([]Task, len(tasks))
 	copy(sorted, tasks)
+	blocked := blockedIDs(tasks)
 
-	// Custom sort: completed last, then priority, due date, then creation time.
+	// Custom sort: completed last, blocked next, then priority, due date,
+	// then creation time.
 	sort.SliceStable(sorted, func(i, j int) bool {
 		a := sorted[i]
 		b := sorted[j]
@@ -459,6 +732,11 @@ func (s *Storage) SortTasks(tasks []Task) []Task {
 			return !a.Done
 		}
 
+		// Blocked tasks go below everything that can be started now.
+		if blocked[a.ID] != blocked[b.ID] {
+			return !blocked[a.ID]
+		}
+
 		// Priority comparison (high=3, medium=2, low=1, none=0).
 		aPrio := priorityValue(a.Priority)
 		bPrio := priorityValue(b.Priority)
@@ -484,6 +762,325 @@ func (s *Storage) SortTasks(tasks []Task) []Task {
 	return sorted
 }
 
+// SortTasksManual sorts tasks by their manual position, keeping completed
+// tasks at the bottom. Tasks that were never reordered come first, newest
+// first, so new tasks show up at the top of the list.
+func (s *Storage) SortTasksManual(tasks []Task) []Task {
+	sorted := make([]Task, len(tasks))
+	copy(sorted, tasks)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a := sorted[i]
+		b := sorted[j]
+
+		if a.Done != b.Done {
+			return !a.Done
+		}
+		if (a.Position == 0) != (b.Position == 0) {
+			return a.Position == 0
+		}
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+
+	return sorted
+}
+
+// SetTaskOrder stores a manual task order: the tasks with the given IDs get
+// positions 1..n in that order, and any other tasks follow in their current
+// manual order.
+func (s *Storage) SetTaskOrder(ids []string) error {
+	return s.update(func() error { return s.setTaskOrder(ids) })
+}
+
+func (s *Storage) setTaskOrder(ids []string) error {
+	store, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
+
+	rank := make(map[string]int, len(ids))
+	for i, id := range ids {
+		if _, dup := rank[id]; !dup {
+			rank[id] = i
+		}
+	}
+	ordered := s.SortTasksManual(store.Tasks)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, iok := rank[ordered[i].ID]
+		rj, jok := rank[ordered[j].ID]
+		if iok != jok {
+			return iok
+		}
+		return iok && ri < rj
+	})
+
+	position := make(map[string]int, len(ordered))
+	for i, task := range ordered {
+		position[task.ID] = i + 1
+	}
+	for i := range store.Tasks {
+		store.Tasks[i].Position = position[store.Tasks[i].ID]
+	}
+
+	if err := s.SaveTasks(store); err != nil {
+		return err
+	}
+	// Notify with semantic context for git commit
+	s.notifySaveWithContext(SaveContext{
+		Filename:  "tasks.json",
+		Operation: "reorder",
+		ItemType:  "tasks",
+	})
+	return nil
+}
+
+// nextOccurrence builds the pending task that follows a recurring task
+// completed at completedAt.
+func nextOccurrence(task Task, completedAt time.Time) (Task, error) {
+	id, err := newID("t")
+	if err != nil {
+		return Task{}, err
+	}
+	// Timed deadlines roll forward in their own zone so 14:00 stays 14:00
+	// across daylight saving changes.
+	prevDue := task.DueDate
+	if prevDue != nil && task.DueTimed {
+		inZone := prevDue.In(task.DueLocation())
+		prevDue = &inZone
+	}
+	due := task.Recurrence.NextDue(prevDue, completedAt)
+	if task.DueTimed && prevDue != nil && task.Recurrence.Kind == RecurAfterCompletion {
+		// after_completion counts whole days; keep the deadline's clock time.
+		due = time.Date(due.Year(), due.Month(), due.Day(), prevDue.Hour(), prevDue.Minute(), 0, 0, prevDue.Location())
+	}
+	rule := *task.Recurrence
+	rule.Weekdays = append([]int(nil), task.Recurrence.Weekdays...)
+	// The checklist carries over with every item unchecked.
+	var checklist []ChecklistItem
+	for _, item := range task.Checklist {
+		checklist = append(checklist, ChecklistItem{ID: item.ID, Text: item.Text})
+	}
+	// A start date keeps the same lead time before the new due date.
+	var start *time.Time
+	if task.StartDate != nil && task.DueDate != nil {
+		lead := startOfDay(*task.DueDate).Sub(startOfDay(*task.StartDate))
+		days := int(math.Round(lead.Hours() / 24))
+		day := startOfDay(due).AddDate(0, 0, -days)
+		start = &day
+	}
+	return Task{
+		ID:          id,
+		Text:        task.Text,
+		Project:     task.Project,
+		Priority:    task.Priority,
+		DueDate:     &due,
+		DueTimed:    task.DueTimed && task.DueDate != nil,
+		DueZone:     task.DueZone,
+		StartDate:   start,
+		CreatedAt:   completedAt,
+		Recurrence:  &rule,
+		SpawnedFrom: task.ID,
+		Checklist:   checklist,
+		Tags:        append([]string(nil), task.Tags...),
+		Notes:       task.Notes,
+	}, nil
+}
+
+// spawnedUnchanged reports whether the pending occurrence spawned still
+// matches what completing task produced, so it can be removed without losing
+// edits. Its ID and list position are not compared.
+func spawnedUnchanged(spawned, task Task) bool {
+	if task.CompletedAt == nil || task.Recurrence == nil {
+		return false
+	}
+	want, err := nextOccurrence(task, *task.CompletedAt)
+	if err != nil {
+		return false
+	}
+	if !sameTime(want.DueDate, spawned.DueDate) || !sameTime(want.StartDate, spawned.StartDate) || !want.CreatedAt.Equal(spawned.CreatedAt) {
+		return false
+	}
+	// Times are compared as instants above; the rest must match exactly.
+	want.ID, want.Position = spawned.ID, spawned.Position
+	want.DueDate, want.StartDate, want.CreatedAt = spawned.DueDate, spawned.StartDate, spawned.CreatedAt
+	return bytes.Equal(snapshot(want), snapshot(spawned))
+}
+
+// sameTime reports whether two optional times are both unset or the same instant.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// validateTaskFields checks user-editable task fields against storage limits.
+// Text and project are expected to be trimmed already.
+func validateTaskFields(text, project string, priority Priority) error {
+	if text == "" {
+		return fmt.Errorf("task text is required")
+	}
+	if len(text) > maxTaskTextLen {
+		return fmt.Errorf("task text too long (max %d)", maxTaskTextLen)
+	}
+	if len(project) > maxProjectLen {
+		return fmt.Errorf("project too long (max %d)", maxProjectLen)
+	}
+	if priority != "" && priority != PriorityLow && priority != PriorityMedium && priority != PriorityHigh {
+		return fmt.Errorf("invalid priority: must be low, medium, or high")
+	}
+	return nil
+}
+
+// validateNotes checks task notes against storage limits.
+func validateNotes(notes string) error {
+	if len(notes) > maxNotesLen {
+		return fmt.Errorf("task notes too long (max %d)", maxNotesLen)
+	}
+	return nil
+}
+
+// normalizeTags lowercases tags, strips a leading '#', drops blanks and
+// duplicates, and checks them against storage limits.
+func normalizeTags(tags []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.ContainsAny(tag, " \t#,") {
+			return nil, fmt.Errorf("invalid tag %q: tags cannot contain spaces, commas or '#'", tag)
+		}
+		if len(tag) > maxTagLen {
+			return nil, fmt.Errorf("tag too long (max %d)", maxTagLen)
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	if len(out) > maxTagsPerTask {
+		return nil, fmt.Errorf("too many tags (max %d)", maxTagsPerTask)
+	}
+	return out, nil
+}
+
+// ParseTagList parses a space- or comma-separated list of tags (with or
+// without a leading '#') into normalized tags.
+func ParseTagList(s string) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	return normalizeTags(fields)
+}
+
+// ExtractTags pulls "#tag" tokens out of task text, returning the remaining
+// text and the tags found. A tag must start with a letter, so references
+// like "#123" stay part of the text.
+func ExtractTags(text string) (string, []string) {
+	var words, tags []string
+	for _, word := range strings.Fields(text) {
+		if isTagToken(word) {
+			tags = append(tags, strings.ToLower(word[1:]))
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), tags
+}
+
+func isTagToken(word string) bool {
+	if len(word) < 2 || word[0] != '#' {
+		return false
+	}
+	for i, r := range word[1:] {
+		switch {
+		case unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '_' || r == '/' || r == ':'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// HasTag reports whether the task carries the given tag (case-insensitive).
+func (t Task) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	for _, existing := range t.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeferred reports whether the task has a start date after the day of now,
+// meaning it cannot be acted on yet. Completed tasks are never deferred.
+func (t Task) IsDeferred(now time.Time) bool {
+	return !t.Done && t.StartDate != nil && startOfDay(*t.StartDate).After(startOfDay(now))
+}
+
+// DueLocation returns the zone a timed deadline was set in, falling back to
+// the zone stored with the due date when the zone name is unknown here.
+func (t Task) DueLocation() *time.Location {
+	if t.DueZone != "" {
+		if loc, err := time.LoadLocation(t.DueZone); err == nil {
+			return loc
+		}
+	}
+	if t.DueDate != nil {
+		return t.DueDate.Location()
+	}
+	return time.Local
+}
+
+// Overdue reports whether the task is past its deadline at now: the due time
+// for timed tasks, or the end of the due day otherwise.
+func (t Task) Overdue(now time.Time) bool {
+	if t.Done || t.DueDate == nil {
+		return false
+	}
+	if t.DueTimed {
+		return now.After(*t.DueDate)
+	}
+	return startOfDay(*t.DueDate).Before(startOfDay(now))
+}
+
+// LocalZoneName returns the IANA name of the local time zone (for example
+// "Europe/Berlin"), or "" if it cannot be determined.
+func LocalZoneName() string {
+	if name := time.Local.String(); name != "Local" && name != "" {
+		return name
+	}
+	if tz := os.Getenv("TZ"); tz != "" {
+		return strings.TrimPrefix(tz, ":")
+	}
+	// Most Unix systems link /etc/localtime into the zoneinfo database.
+	target, err := os.Readlink("/etc/localtime")
+	if err != nil {
+		return ""
+	}
+	if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+		return target[i+len("zoneinfo/"):]
+	}
+	return ""
+}
+
+// SnoozeStart returns the start date that snoozing the task by days sets:
+// days after its current start date, or after today if it has none or it
+// has already passed.
+func SnoozeStart(task Task, now time.Time, days int) time.Time {
+	from := startOfDay(now)
+	if task.StartDate != nil && startOfDay(*task.StartDate).After(from) {
+		from = startOfDay(*task.StartDate)
+	}
+	return from.AddDate(0, 0, days)
+}
+
 func priorityValue(p Priority) int {
 	switch p {
 	case PriorityHigh:
@@ -498,92 +1095,801 @@ func priorityValue(p Priority) int {
 }
 
 // ============================================================================
-// Habits
+// Checklists
 // ============================================================================
 
-// LoadHabits reads habits from disk
-func (s *Storage) LoadHabits() (*HabitStore, error) {
-	store := HabitStore{Habits: []Habit{}, Logs: []HabitLog{}}
-	err := s.loadJSONWithRecovery("habits.json", &store)
-	return &store, err
+// ChecklistProgress returns how many of the task's checklist items are done.
+func (t Task) ChecklistProgress() (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
+}
+
+// AddChecklistItem appends a new checklist item to a task.
+func (s *Storage) AddChecklistItem(taskID, text string) (*ChecklistItem, error) {
+	var item *ChecklistItem
+	err := s.update(func() (err error) {
+		item, err = s.addChecklistItem(taskID, text)
+		return err
+	})
+	return item, err
+}
+
+func (s *Storage) addChecklistItem(taskID, text string) (*ChecklistItem, error) {
+	text = strings.TrimSpace(text)
+	if err := validateChecklistText(text); err != nil {
+		return nil, err
+	}
+
+	id, err := newID("c")
+	if err != nil {
+		return nil, err
+	}
+	item := ChecklistItem{ID: id, Text: text}
+
+	err = s.modifyChecklist(taskID, "add", func(task *Task) (string, error) {
+		task.Checklist = append(task.Checklist, item)
+		return item.Text, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SetChecklistItemDone marks a checklist item as done or not done.
+func (s *Storage) SetChecklistItemDone(taskID, itemID string, done bool) error {
+	return s.update(func() error { return s.setChecklistItemDone(taskID, itemID, done) })
+}
+
+func (s *Storage) setChecklistItemDone(taskID, itemID string, done bool) error {
+	operation := "reopen"
+	if done {
+		operation = "complete"
+	}
+	return s.modifyChecklist(taskID, operation, func(task *Task) (string, error) {
+		for i := range task.Checklist {
+			if task.Checklist[i].ID == itemID {
+				task.Checklist[i].Done = done
+				return task.Checklist[i].Text, nil
+			}
+		}
+		return "", fmt.Errorf("checklist item not found: %s", itemID)
+	})
+}
+
+// DeleteChecklistItem removes a checklist item from a task.
+func (s *Storage) DeleteChecklistItem(taskID, itemID string) error {
+	return s.update(func() error { return s.deleteChecklistItem(taskID, itemID) })
+}
+
+func (s *Storage) deleteChecklistItem(taskID, itemID string) error {
+	return s.modifyChecklist(taskID, "delete", func(task *Task) (string, error) {
+		for i := range task.Checklist {
+			if task.Checklist[i].ID == itemID {
+				text := task.Checklist[i].Text
+				task.Checklist = append(task.Checklist[:i], task.Checklist[i+1:]...)
+				return text, nil
+			}
+		}
+		return "", fmt.Errorf("checklist item not found: %s", itemID)
+	})
+}
+
+// RestoreChecklistItem re-inserts a previously deleted checklist item at the
+// given position (used for undo/redo). Out-of-range positions append.
+func (s *Storage) RestoreChecklistItem(taskID string, item ChecklistItem, index int) error {
+	return s.update(func() error { return s.restoreChecklistItem(taskID, item, index) })
+}
+
+func (s *Storage) restoreChecklistItem(taskID string, item ChecklistItem, index int) error {
+	item.Text = strings.TrimSpace(item.Text)
+	if strings.TrimSpace(item.ID) == "" {
+		return fmt.Errorf("checklist item id is required")
+	}
+	if err := validateChecklistText(item.Text); err != nil {
+		return err
+	}
+
+	return s.modifyChecklist(taskID, "restore", func(task *Task) (string, error) {
+		for _, existing := range task.Checklist {
+			if existing.ID == item.ID {
+				return "", fmt.Errorf("checklist item already exists: %s", item.ID)
+			}
+		}
+		if index < 0 || index > len(task.Checklist) {
+			index = len(task.Checklist)
+		}
+		task.Checklist = append(task.Checklist, ChecklistItem{})
+		copy(task.Checklist[index+1:], task.Checklist[index:])
+		task.Checklist[index] = item
+		return item.Text, nil
+	})
+}
+
+// modifyChecklist applies fn to the checklist of the task with the given ID,
+// saves, and notifies using the item name returned by fn.
+func (s *Storage) modifyChecklist(taskID, operation string, fn func(task *Task) (string, error)) error {
+	store, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
+
+	for i := range store.Tasks {
+		if store.Tasks[i].ID != taskID {
+			continue
+		}
+		before := snapshot(store.Tasks[i])
+		itemName, err := fn(&store.Tasks[i])
+		if err != nil {
+			return err
+		}
+		if err := s.SaveTasks(store); err != nil {
+			return err
+		}
+		// Notify with semantic context for git commit
+		s.notifySaveWithContext(SaveContext{
+			Filename:  "tasks.json",
+			Operation: operation,
+			ItemType:  "checklist item",
+			ItemName:  truncateForCommit(itemName, 50),
+			ItemID:    taskID,
+			Before:    before,
+			After:     snapshot(store.Tasks[i]),
+		})
+		return nil
+	}
+
+	return fmt.Errorf("task not found: %s", taskID)
+}
+
+func validateChecklistText(text string) error {
+	if text == "" {
+		return fmt.Errorf("checklist item text is required")
+	}
+	if len(text) > maxTaskTextLen {
+		return fmt.Errorf("checklist item text too long (max %d)", maxTaskTextLen)
+	}
+	return nil
+}
+
+// ============================================================================
+// Dependencies
+// ============================================================================
+
+// AddBlocker records that the task cannot start until blockerID is done.
+// The relation is kept after the blocker is completed, so the task unblocks
+// automatically and blocks again if the blocker is reopened.
+func (s *Storage) AddBlocker(taskID, blockerID string) error {
+	return s.update(func() error { return s.addBlocker(taskID, blockerID) })
+}
+
+func (s *Storage) addBlocker(taskID, blockerID string) error {
+	if taskID == blockerID {
+		return fmt.Errorf("a task cannot block itself")
+	}
+	return s.modifyBlockers(taskID, blockerID, "block", func(store *TaskStore, task *Task) error {
+		if !hasTask(store.Tasks, blockerID) {
+			return fmt.Errorf("task not found: %s", blockerID)
+		}
+		for _, id := range task.BlockedBy {
+			if id == blockerID {
+				return fmt.Errorf("task is already blocked by %s", blockerID)
+			}
+		}
+		if dependsOn(store.Tasks, blockerID, taskID) {
+			return fmt.Errorf("dependency cycle: the blocker already waits on this task")
+		}
+		task.BlockedBy = append(task.BlockedBy, blockerID)
+		return nil
+	})
+}
+
+// RemoveBlocker deletes the relation added by AddBlocker.
+func (s *Storage) RemoveBlocker(taskID, blockerID string) error {
+	return s.update(func() error { return s.removeBlocker(taskID, blockerID) })
+}
+
+func (s *Storage) removeBlocker(taskID, blockerID string) error {
+	return s.modifyBlockers(taskID, blockerID, "unblock", func(_ *TaskStore, task *Task) error {
+		for i, id := range task.BlockedBy {
+			if id == blockerID {
+				task.BlockedBy = append(task.BlockedBy[:i], task.BlockedBy[i+1:]...)
+				if len(task.BlockedBy) == 0 {
+					task.BlockedBy = nil
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("task is not blocked by %s", blockerID)
+	})
+}
+
+// modifyBlockers applies fn to the task with the given ID, saves, and
+// notifies with the task text.
+func (s *Storage) modifyBlockers(taskID, blockerID, operation string, fn func(store *TaskStore, task *Task) error) error {
+	store, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
+
+	for i := range store.Tasks {
+		if store.Tasks[i].ID != taskID {
+			continue
+		}
+		before := snapshot(store.Tasks[i])
+		if err := fn(store, &store.Tasks[i]); err != nil {
+			return err
+		}
+		if err := s.SaveTasks(store); err != nil {
+			return err
+		}
+		// Notify with semantic context for git commit
+		s.notifySaveWithContext(SaveContext{
+			Filename:  "tasks.json",
+			Operation: operation,
+			ItemType:  "task",
+			ItemName:  truncateForCommit(store.Tasks[i].Text, 50),
+			ItemID:    taskID,
+			Before:    before,
+			After:     snapshot(store.Tasks[i]),
+		})
+		return nil
+	}
+
+	return fmt.Errorf("task not found: %s", taskID)
+}
+
+// PendingBlockers returns the tasks (looked up in tasks) that block task and
+// are not done yet. A task is blocked while this is non-empty; blockers that
+// were deleted no longer count.
+func PendingBlockers(tasks []Task, task Task) []Task {
+	var blockers []Task
+	for _, id := range task.BlockedBy {
+		for _, other := range tasks {
+			if other.ID == id && !other.Done {
+				blockers = append(blockers, other)
+				break
+			}
+		}
+	}
+	return blockers
+}
+
+// blockedIDs returns the IDs of tasks that have at least one pending blocker.
+func blockedIDs(tasks []Task) map[string]bool {
+	pending := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if !task.Done {
+			pending[task.ID] = true
+		}
+	}
+	blocked := make(map[string]bool)
+	for _, task := range tasks {
+		for _, id := range task.BlockedBy {
+			if pending[id] {
+				blocked[task.ID] = true
+				break
+			}
+		}
+	}
+	return blocked
+}
+
+// dependsOn reports whether the task with ID from waits, directly or through
+// other tasks, on the task with ID target.
+func dependsOn(tasks []Task, from, target string) bool {
+	byID := make(map[string][]string, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task.BlockedBy
+	}
+	seen := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == target {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		stack = append(stack, byID[id]...)
+	}
+	return false
+}
+
+func hasTask(tasks []Task, id string) bool {
+	for _, task := range tasks {
+		if task.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// Habits
+// ============================================================================
+
+// LoadHabits reads habits from disk
+func (s *Storage) LoadHabits() (*HabitStore, error) {
+	return s.readHabits(false)
+}
+
+// loadHabits is LoadHabits for the body of an update cycle.
+func (s *Storage) loadHabits() (*HabitStore, error) {
+	return s.readHabits(true)
+}
+
+func (s *Storage) readHabits(working bool) (*HabitStore, error) {
+	store := HabitStore{Version: SchemaVersion, Habits: []Habit{}, Logs: []HabitLog{}}
+	if err := s.loadCollection("habits.json", &store, working); err != nil {
+		return nil, err
+	}
+	s.fillHabitStateSince(&store)
+	return &store, nil
+}
+
+// fillHabitStateSince dates paused and archived habits that lack the day
+// they were set aside (hand-edited or imported files) to the day after their
+// last log, or today if they have none, so their history stays tracked.
+func (s *Storage) fillHabitStateSince(store *HabitStore) {
+	for i := range store.Habits {
+		h := &store.Habits[i]
+		if h.IsActive() || h.StateSince != "" {
+			continue
+		}
+		since := s.Now().Format("2006-01-02")
+		last := ""
+		for _, log := range store.Logs {
+			if log.HabitID == h.ID && log.Date > last {
+				last = log.Date
+			}
+		}
+		if day, err := time.Parse("2006-01-02", last); err == nil {
+			since = min(since, day.AddDate(0, 0, 1).Format("2006-01-02"))
+		}
+		h.StateSince = since
+	}
+}
+
+// SaveHabits writes habits to disk
+func (s *Storage) SaveHabits(store *HabitStore) error {
+	store.Version = SchemaVersion
+	return s.saveCollection("habits.json", store)
+}
+
+// RestoreHabit restores a previously existing habit and its logs (used for undo/redo).
+func (s *Storage) RestoreHabit(habit Habit, logs []HabitLog) error {
+	return s.update(func() error { return s.restoreHabit(habit, logs) })
+}
+
+func (s *Storage) restoreHabit(habit Habit, logs []HabitLog) error {
+	habit.Name = strings.TrimSpace(habit.Name)
+	habit.Icon = strings.TrimSpace(habit.Icon)
+
+	if strings.TrimSpace(habit.ID) == "" {
+		return fmt.Errorf("habit id is required")
+	}
+	if err := validateHabitFields(habit.Name, habit.Icon); err != nil {
+		return err
+	}
+	if habit.CreatedAt.IsZero() {
+		habit.CreatedAt = time.Now()
+	}
+
+	store, err := s.loadHabits()
+	if err != nil {
+		return err
+	}
+	for _, existing := range store.Habits {
+		if existing.ID == habit.ID {
+			return fmt.Errorf("habit already exists: %s", habit.ID)
+		}
+	}
+
+	store.Habits = append(store.Habits, habit)
+
+	// Restore logs, deduping by (habit_id,date).
+	seen := make(map[string]struct{}, len(store.Logs)+len(logs))
+	for _, log := range store.Logs {
+		seen[log.HabitID+"|"+log.Date] = struct{}{}
+	}
+	for _, log := range logs {
+		date := strings.TrimSpace(log.Date)
+		if date == "" {
+			continue
+		}
+		key := habit.ID + "|" + date
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		store.Logs = append(store.Logs, HabitLog{HabitID: habit.ID, Date: date, Value: log.Value, Skipped: log.Skipped})
+	}
+
+	if err := s.SaveHabits(store); err != nil {
+		return err
+	}
+	// Undoing a delete takes the habit back out of the trash.
+	if _, err := s.removeFromTrash(habit.ID); err != nil {
+		return err
+	}
+
+	// Notify with semantic context for git commit
+	s.notifySaveWithContext(SaveContext{
+		Filename:  "habits.json",
+		Files:     []string{trashFile},
+		Operation: "restore",
+		ItemType:  "habit",
+		ItemName:  truncateForCommit(habit.Name, 50),
+		ItemID:    habit.ID,
+		After:     snapshot(habit),
+	})
+
+	return nil
 }
 
-// SaveHabits writes habits to disk
-func (s *Storage) SaveHabits(store *HabitStore) error {
-	return s.writeJSONAtomic("habits.json", store)
+// SetHabitDoneOnDate sets a habit's completion for a specific YYYY-MM-DD date.
+// Completing a habit with a target logs the full target.
+func (s *Storage) SetHabitDoneOnDate(habitID, date string, done bool) error {
+	return s.update(func() error { return s.setHabitDoneOnDate(habitID, date, done) })
+}
+
+func (s *Storage) setHabitDoneOnDate(habitID, date string, done bool) error {
+	value := 0
+	if done {
+		store, err := s.loadHabits()
+		if err != nil {
+			return err
+		}
+		value = findHabit(store, strings.TrimSpace(habitID)).Goal()
+	}
+	return s.setHabitValueOnDate(habitID, date, value)
+}
+
+// SetHabitValueOnDate sets the amount logged for a habit on a specific
+// YYYY-MM-DD date. A value of 0 removes the day's log. Habits without a
+// target only record whether the value is positive.
+func (s *Storage) SetHabitValueOnDate(habitID, date string, value int) error {
+	return s.update(func() error { return s.setHabitValueOnDate(habitID, date, value) })
+}
+
+func (s *Storage) setHabitValueOnDate(habitID, date string, value int) error {
+	habitID = strings.TrimSpace(habitID)
+	date = strings.TrimSpace(date)
+	if habitID == "" {
+		return fmt.Errorf("habit id is required")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+	}
+	if value < 0 {
+		return fmt.Errorf("invalid habit value %d: must not be negative", value)
+	}
+
+	store, err := s.loadHabits()
+	if err != nil {
+		return err
+	}
+
+	var habit *Habit
+	for i := range store.Habits {
+		if store.Habits[i].ID == habitID {
+			habit = &store.Habits[i]
+			break
+		}
+	}
+	if habit == nil {
+		return fmt.Errorf("habit not found: %s", habitID)
+	}
+
+	newLogs := store.Logs[:0]
+	for _, log := range store.Logs {
+		if log.HabitID == habitID && log.Date == date {
+			continue
+		}
+		newLogs = append(newLogs, log)
+	}
+	store.Logs = newLogs
+
+	if value > 0 {
+		log := HabitLog{HabitID: habitID, Date: date}
+		if habit.HasTarget() {
+			log.Value = value
+		}
+		store.Logs = append(store.Logs, log)
+	}
+	return s.SaveHabits(store)
+}
+
+// AdjustHabitToday adds delta to the amount logged for a habit today and
+// returns the new amount, which never drops below 0. For habits without a
+// target this checks (delta > 0) or unchecks (delta < 0) today.
+func (s *Storage) AdjustHabitToday(habitID string, delta int) (int, error) {
+	var value int
+	err := s.update(func() (err error) {
+		value, err = s.adjustHabitToday(habitID, delta)
+		return err
+	})
+	return value, err
+}
+
+func (s *Storage) adjustHabitToday(habitID string, delta int) (int, error) {
+	today := s.Now().Format("2006-01-02")
+	store, err := s.loadHabits()
+	if err != nil {
+		return 0, err
+	}
+	var habit Habit
+	found := false
+	for _, h := range store.Habits {
+		if h.ID == habitID {
+			habit, found = h, true
+			break
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("habit not found: %s", habitID)
+	}
+
+	before := s.HabitValueOnDate(store, habitID, today)
+	after := max(0, before+delta)
+	if !habit.HasTarget() {
+		after = min(after, 1)
+	}
+	if after == before {
+		return before, nil
+	}
+	if err := s.setHabitValueOnDate(habitID, today, after); err != nil {
+		return 0, err
+	}
+
+	// Notify with semantic context for git commit
+	goal := habit.Goal()
+	s.notifySaveWithContext(SaveContext{
+		Filename:  "habits.json",
+		Operation: "log",
+		ItemType:  "habit",
+		ItemName:  truncateForCommit(habit.Name, 50),
+		ItemID:    habitID,
+		Before:    snapshot(habitDay{Date: today, Done: before >= goal, Value: before}),
+		After:     snapshot(habitDay{Date: today, Done: after >= goal, Value: after}),
+	})
+
+	return after, nil
+}
+
+// AddHabit creates a new habit due at the given frequency. customDays
+// (0=Sunday) are the days a FrequencyCustom habit is due on.
+func (s *Storage) AddHabit(name, icon string, frequency HabitFrequency, customDays ...int) (*Habit, error) {
+	return s.CreateHabit(Habit{Name: name, Icon: icon, Frequency: frequency, CustomDays: customDays})
+}
+
+// CreateHabit creates a new habit from the editable fields of habit (name,
+// icon, schedule, target and unit). Its ID and creation time are assigned.
+func (s *Storage) CreateHabit(habit Habit) (*Habit, error) {
+	var created *Habit
+	err := s.update(func() (err error) {
+		created, err = s.createHabit(habit)
+		return err
+	})
+	return created, err
+}
+
+func (s *Storage) createHabit(fields Habit) (*Habit, error) {
+	name := strings.TrimSpace(fields.Name)
+	icon := strings.TrimSpace(fields.Icon)
+
+	if err := validateHabitFields(name, icon); err != nil {
+		return nil, err
+	}
+	frequency, customDays, err := normalizeHabitSchedule(fields.Frequency, fields.CustomDays)
+	if err != nil {
+		return nil, err
+	}
+	target, unit, err := normalizeHabitTarget(fields.Target, fields.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := s.loadHabits()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newID("h")
+	if err != nil {
+		return nil, err
+	}
+
+	habit := Habit{
+		ID:         id,
+		Name:       name,
+		Icon:       icon,
+		Frequency:  frequency,
+		CustomDays: customDays,
+		Target:     target,
+		Unit:       unit,
+		CreatedAt:  time.Now(),
+	}
+
+	store.Habits = append(store.Habits, habit)
+
+	if err := s.SaveHabits(store); err != nil {
+		return nil, err
+	}
+
+	// Notify with semantic context for git commit
+	s.notifySaveWithContext(SaveContext{
+		Filename:  "habits.json",
+		Operation: "add",
+		ItemType:  "habit",
+		ItemName:  truncateForCommit(habit.Name, 50),
+		ItemID:    habit.ID,
+		After:     snapshot(habit),
+	})
+
+	return &habit, nil
+}
+
+// UpdateHabit replaces the editable fields (name, icon, frequency, custom
+// days, target and unit) of an existing habit. Its ID, creation time and logs are preserved.
+func (s *Storage) UpdateHabit(habit Habit) error {
+	return s.update(func() error { return s.updateHabit(habit) })
+}
+
+func (s *Storage) updateHabit(habit Habit) error {
+	habit.Name = strings.TrimSpace(habit.Name)
+	habit.Icon = strings.TrimSpace(habit.Icon)
+
+	if strings.TrimSpace(habit.ID) == "" {
+		return fmt.Errorf("habit id is required")
+	}
+	if err := validateHabitFields(habit.Name, habit.Icon); err != nil {
+		return err
+	}
+	frequency, customDays, err := normalizeHabitSchedule(habit.Frequency, habit.CustomDays)
+	if err != nil {
+		return err
+	}
+	target, unit, err := normalizeHabitTarget(habit.Target, habit.Unit)
+	if err != nil {
+		return err
+	}
+
+	store, err := s.loadHabits()
+	if err != nil {
+		return err
+	}
+
+	for i := range store.Habits {
+		if store.Habits[i].ID == habit.ID {
+			before := snapshot(store.Habits[i])
+			store.Habits[i].Name = habit.Name
+			store.Habits[i].Icon = habit.Icon
+			store.Habits[i].Frequency = frequency
+			store.Habits[i].CustomDays = customDays
+			store.Habits[i].Target = target
+			store.Habits[i].Unit = unit
+			if err := s.SaveHabits(store); err != nil {
+				return err
+			}
+			// Notify with semantic context for git commit
+			s.notifySaveWithContext(SaveContext{
+				Filename:  "habits.json",
+				Operation: "update",
+				ItemType:  "habit",
+				ItemName:  truncateForCommit(habit.Name, 50),
+				ItemID:    habit.ID,
+				Before:    before,
+				After:     snapshot(store.Habits[i]),
+			})
+			return nil
+		}
+	}
+
+	return fmt.Errorf("habit not found: %s", habit.ID)
 }
 
-// RestoreHabit restores a previously existing habit and its logs (used for undo/redo).
-func (s *Storage) RestoreHabit(habit Habit, logs []HabitLog) error {
-	habit.Name = strings.TrimSpace(habit.Name)
-	habit.Icon = strings.TrimSpace(habit.Icon)
-
-	if strings.TrimSpace(habit.ID) == "" {
-		return fmt.Errorf("habit id is required")
-	}
-	if habit.Name == "" {
+// validateHabitFields checks a habit's trimmed name and icon.
+func validateHabitFields(name, icon string) error {
+	if name == "" {
 		return fmt.Errorf("habit name is required")
 	}
-	if len(habit.Name) > maxHabitNameLen {
+	if len(name) > maxHabitNameLen {
 		return fmt.Errorf("habit name too long (max %d)", maxHabitNameLen)
 	}
-	if habit.Icon == "" {
+	if icon == "" {
 		return fmt.Errorf("habit icon is required")
 	}
-	if len(habit.Icon) > maxHabitIconLen {
+	if len(icon) > maxHabitIconLen {
 		return fmt.Errorf("habit icon too long (max %d)", maxHabitIconLen)
 	}
-	if habit.CreatedAt.IsZero() {
-		habit.CreatedAt = time.Now()
-	}
+	return nil
+}
 
-	store, err := s.LoadHabits()
-	if err != nil {
+// ToggleHabitToday toggles a habit for today
+func (s *Storage) ToggleHabitToday(habitID string) (bool, error) {
+	var done bool
+	err := s.update(func() (err error) {
+		done, err = s.toggleHabitToday(habitID)
 		return err
-	}
-	for _, existing := range store.Habits {
-		if existing.ID == habit.ID {
-			return fmt.Errorf("habit already exists: %s", habit.ID)
-		}
-	}
-
-	store.Habits = append(store.Habits, habit)
+	})
+	return done, err
+}
 
-	// Restore logs, deduping by (habit_id,date).
-	seen := make(map[string]struct{}, len(store.Logs)+len(logs))
-	for _, log := range store.Logs {
-		seen[log.HabitID+"|"+log.Date] = struct{}{}
+func (s *Storage) toggleHabitToday(habitID string) (bool, error) {
+	today := s.Now().Format("2006-01-02")
+	store, err := s.loadHabits()
+	if err != nil {
+		return false, err
 	}
-	for _, log := range logs {
-		date := strings.TrimSpace(log.Date)
-		if date == "" {
-			continue
-		}
-		key := habit.ID + "|" + date
-		if _, ok := seen[key]; ok {
-			continue
+
+	// Find habit name for context
+	var habitName string
+	for _, h := range store.Habits {
+		if h.ID == habitID {
+			habitName = h.Name
+			break
 		}
-		seen[key] = struct{}{}
-		store.Logs = append(store.Logs, HabitLog{HabitID: habit.ID, Date: date})
 	}
 
-	if err := s.SaveHabits(store); err != nil {
-		return err
+	wasDone := s.IsHabitDoneOnDate(store, habitID, today)
+	if err := s.setHabitDoneOnDate(habitID, today, !wasDone); err != nil {
+		return false, err
 	}
 
 	// Notify with semantic context for git commit
 	s.notifySaveWithContext(SaveContext{
 		Filename:  "habits.json",
-		Operation: "restore",
+		Operation: "toggle",
 		ItemType:  "habit",
-		ItemName:  truncateForCommit(habit.Name, 50),
+		ItemName:  truncateForCommit(habitName, 50),
+		ItemID:    habitID,
+		Before:    snapshot(habitDay{Date: today, Done: wasDone}),
+		After:     snapshot(habitDay{Date: today, Done: !wasDone}),
 	})
 
-	return nil
+	return !wasDone, nil
 }
 
-// SetHabitDoneOnDate sets a habit's completion for a specific YYYY-MM-DD date.
-func (s *Storage) SetHabitDoneOnDate(habitID, date string, done bool) error {
+// IsHabitDoneOnDate checks if a habit was completed on a specific date. A
+// habit with a target is only completed once the amount logged reaches it.
+func (s *Storage) IsHabitDoneOnDate(store *HabitStore, habitID, date string) bool {
+	habit := findHabit(store, habitID)
+	return s.HabitValueOnDate(store, habitID, date) >= habit.Goal()
+}
+
+// HabitValueOnDate returns the amount logged for a habit on a specific date,
+// 0 if there is none. A plain check-in counts as the habit's goal.
+func (s *Storage) HabitValueOnDate(store *HabitStore, habitID, date string) int {
+	for _, log := range store.Logs {
+		if log.HabitID == habitID && log.Date == date {
+			return log.amount(findHabit(store, habitID))
+		}
+	}
+	return 0
+}
+
+// IsHabitSkippedOnDate reports whether a habit is excused on a specific
+// date, because it was skipped that day or the date is part of the vacation.
+func (s *Storage) IsHabitSkippedOnDate(store *HabitStore, habitID, date string) bool {
+	return habitExcuses(store, findHabit(store, habitID))(date)
+}
+
+// SetHabitSkippedOnDate marks a habit as skipped on a specific YYYY-MM-DD
+// date, replacing anything logged for it that day, or removes the mark.
+func (s *Storage) SetHabitSkippedOnDate(habitID, date string, skipped bool) error {
+	return s.update(func() error { return s.setHabitSkippedOnDate(habitID, date, skipped) })
+}
+
+func (s *Storage) setHabitSkippedOnDate(habitID, date string, skipped bool) error {
 	habitID = strings.TrimSpace(habitID)
 	date = strings.TrimSpace(date)
 	if habitID == "" {
@@ -593,11 +1899,10 @@ func (s *Storage) SetHabitDoneOnDate(habitID, date string, done bool) error {
 		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
 	}
 
-	store, err := s.LoadHabits()
+	store, err := s.loadHabits()
 	if err != nil {
 		return err
 	}
-
 	foundHabit := false
 	for _, h := range store.Habits {
 		if h.ID == habitID {
@@ -611,141 +1916,257 @@ func (s *Storage) SetHabitDoneOnDate(habitID, date string, done bool) error {
 
 	newLogs := store.Logs[:0]
 	for _, log := range store.Logs {
-		if log.HabitID == habitID && log.Date == date {
+		if log.HabitID == habitID && log.Date == date && (skipped || log.Skipped) {
 			continue
 		}
 		newLogs = append(newLogs, log)
 	}
 	store.Logs = newLogs
 
-	if done {
-		store.Logs = append(store.Logs, HabitLog{HabitID: habitID, Date: date})
+	if skipped {
+		store.Logs = append(store.Logs, HabitLog{HabitID: habitID, Date: date, Skipped: true})
 	}
 	return s.SaveHabits(store)
 }
 
-// AddHabit creates a new habit
-func (s *Storage) AddHabit(name, icon string) (*Habit, error) {
-	name = strings.TrimSpace(name)
-	icon = strings.TrimSpace(icon)
-
-	if name == "" {
-		return nil, fmt.Errorf("habit name is required")
-	}
-	if len(name) > maxHabitNameLen {
-		return nil, fmt.Errorf("habit name too long (max %d)", maxHabitNameLen)
-	}
-	if icon == "" {
-		return nil, fmt.Errorf("habit icon is required")
-	}
-	if len(icon) > maxHabitIconLen {
-		return nil, fmt.Errorf("habit icon too long (max %d)", maxHabitIconLen)
-	}
-
-	store, err := s.LoadHabits()
-	if err != nil {
-		return nil, err
-	}
+// ToggleHabitSkipToday skips a habit for today, or removes today's skip
+// mark. It returns whether the habit is now skipped.
+func (s *Storage) ToggleHabitSkipToday(habitID string) (bool, error) {
+	var skipped bool
+	err := s.update(func() (err error) {
+		skipped, err = s.toggleHabitSkipToday(habitID)
+		return err
+	})
+	return skipped, err
+}
 
-	id, err := newID("h")
+func (s *Storage) toggleHabitSkipToday(habitID string) (bool, error) {
+	today := s.Now().Format("2006-01-02")
+	store, err := s.loadHabits()
 	if err != nil {
-		return nil, err
+		return false, err
 	}
+	habit := findHabit(store, habitID)
 
-	habit := Habit{
-		ID:        id,
-		Name:      name,
-		Icon:      icon,
-		CreatedAt: time.Now(),
+	wasSkipped := false
+	for _, log := range store.Logs {
+		if log.HabitID == habitID && log.Date == today && log.Skipped {
+			wasSkipped = true
+		}
 	}
-
-	store.Habits = append(store.Habits, habit)
-
-	if err := s.SaveHabits(store); err != nil {
-		return nil, err
+	value := s.HabitValueOnDate(store, habitID, today)
+	if err := s.setHabitSkippedOnDate(habitID, today, !wasSkipped); err != nil {
+		return false, err
 	}
 
 	// Notify with semantic context for git commit
 	s.notifySaveWithContext(SaveContext{
 		Filename:  "habits.json",
-		Operation: "add",
+		Operation: "skip",
 		ItemType:  "habit",
 		ItemName:  truncateForCommit(habit.Name, 50),
+		ItemID:    habitID,
+		Before:    snapshot(habitDay{Date: today, Done: value >= habit.Goal(), Value: value, Skipped: wasSkipped}),
+		After:     snapshot(habitDay{Date: today, Skipped: !wasSkipped}),
 	})
 
-	return &habit, nil
+	return !wasSkipped, nil
 }
 
-// ToggleHabitToday toggles a habit for today
-func (s *Storage) ToggleHabitToday(habitID string) (bool, error) {
-	today := s.Now().Format("2006-01-02")
-	store, err := s.LoadHabits()
-	if err != nil {
-		return false, err
-	}
+// SetVacation sets the vacation, the days every habit is excused on, to the
+// inclusive range from start to end (YYYY-MM-DD). A nil range ends it.
+func (s *Storage) SetVacation(vacation *DateRange) error {
+	return s.update(func() error { return s.setVacation(vacation) })
+}
 
-	// Find habit name for context
-	var habitName string
-	for _, h := range store.Habits {
-		if h.ID == habitID {
-			habitName = h.Name
-			break
+func (s *Storage) setVacation(vacation *DateRange) error {
+	operation, name := "stop", ""
+	if vacation != nil {
+		r := DateRange{Start: strings.TrimSpace(vacation.Start), End: strings.TrimSpace(vacation.End)}
+		start, err := time.Parse("2006-01-02", r.Start)
+		if err != nil {
+			return fmt.Errorf("invalid vacation start %q: expected YYYY-MM-DD", r.Start)
 		}
+		end, err := time.Parse("2006-01-02", r.End)
+		if err != nil {
+			return fmt.Errorf("invalid vacation end %q: expected YYYY-MM-DD", r.End)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("vacation ends before it starts")
+		}
+		vacation = &r
+		operation, name = "start", r.Start+" to "+r.End
 	}
 
-	wasDone := s.IsHabitDoneOnDate(store, habitID, today)
-	if err := s.SetHabitDoneOnDate(habitID, today, !wasDone); err != nil {
-		return false, err
+	store, err := s.loadHabits()
+	if err != nil {
+		return err
+	}
+	before := store.Vacation
+	store.Vacation = vacation
+	if err := s.SaveHabits(store); err != nil {
+		return err
 	}
 
 	// Notify with semantic context for git commit
-	s.notifySaveWithContext(SaveContext{
+	ctx := SaveContext{
 		Filename:  "habits.json",
-		Operation: "toggle",
-		ItemType:  "habit",
-		ItemName:  truncateForCommit(habitName, 50),
-	})
+		Operation: operation,
+		ItemType:  "vacation",
+		ItemName:  name,
+	}
+	if before != nil {
+		ctx.Before = snapshot(before)
+	}
+	if vacation != nil {
+		ctx.After = snapshot(vacation)
+	}
+	s.notifySaveWithContext(ctx)
+	return nil
+}
 
-	return !wasDone, nil
+// SetHabitState pauses, archives or resumes (HabitActive) a habit from
+// today on. Resuming records the time it spent paused or archived as a
+// break, so those days stay excused. It returns the updated habit.
+func (s *Storage) SetHabitState(habitID string, state HabitState) (*Habit, error) {
+	var habit *Habit
+	err := s.update(func() (err error) {
+		habit, err = s.setHabitState(habitID, state)
+		return err
+	})
+	return habit, err
 }
 
-// IsHabitDoneOnDate checks if a habit was completed on a specific date
-func (s *Storage) IsHabitDoneOnDate(store *HabitStore, habitID, date string) bool {
-	for _, log := range store.Logs {
-		if log.HabitID == habitID && log.Date == date {
-			return true
+func (s *Storage) setHabitState(habitID string, state HabitState) (*Habit, error) {
+	operation := "resume"
+	switch state {
+	case HabitActive:
+	case HabitPaused:
+		operation = "pause"
+	case HabitArchived:
+		operation = "archive"
+	default:
+		return nil, fmt.Errorf("invalid habit state %q", state)
+	}
+
+	store, err := s.loadHabits()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range store.Habits {
+		h := &store.Habits[i]
+		if h.ID != habitID {
+			continue
+		}
+		if h.State == state {
+			return nil, fmt.Errorf("habit is already %s", stateName(state))
 		}
+		before := snapshot(*h)
+
+		today := startOfDay(s.Now())
+		if !h.IsActive() {
+			// Yesterday ends the break; today the habit is tracked again
+			// (or, switching between paused and archived, still is not).
+			end := today.AddDate(0, 0, -1).Format("2006-01-02")
+			if h.StateSince != "" && end >= h.StateSince {
+				h.Breaks = append(h.Breaks, DateRange{Start: h.StateSince, End: end})
+			}
+		}
+		h.State = state
+		h.StateSince = ""
+		if state != HabitActive {
+			h.StateSince = today.Format("2006-01-02")
+		}
+
+		if err := s.SaveHabits(store); err != nil {
+			return nil, err
+		}
+		// Notify with semantic context for git commit
+		s.notifySaveWithContext(SaveContext{
+			Filename:  "habits.json",
+			Operation: operation,
+			ItemType:  "habit",
+			ItemName:  truncateForCommit(h.Name, 50),
+			ItemID:    h.ID,
+			Before:    before,
+			After:     snapshot(*h),
+		})
+		habit := *h
+		return &habit, nil
 	}
-	return false
+
+	return nil, fmt.Errorf("habit not found: %s", habitID)
 }
 
-// GetHabitStreak calculates the current streak for a habit
-func (s *Storage) GetHabitStreak(store *HabitStore, habitID string) int {
-	return s.GetHabitStreakAt(store, habitID, s.Now())
+// stateName describes a habit state for messages.
+func stateName(state HabitState) string {
+	if state == HabitActive {
+		return "active"
+	}
+	return string(state)
 }
 
-// GetHabitStreakAt calculates the streak for a habit as of the given time.
-// If the habit is not completed on that date, it counts back starting from the previous day.
-func (s *Storage) GetHabitStreakAt(store *HabitStore, habitID string, at time.Time) int {
-	streak := 0
-	date := startOfDay(at)
+// RestoreHabitState puts back a habit's state, the date it took effect and
+// its breaks, as they were in habit (used for undo/redo).
+func (s *Storage) RestoreHabitState(habit Habit) error {
+	return s.update(func() error { return s.restoreHabitState(habit) })
+}
 
-	// If the target date isn't done, start from the previous day.
-	if !s.IsHabitDoneOnDate(store, habitID, date.Format("2006-01-02")) {
-		date = date.AddDate(0, 0, -1)
+func (s *Storage) restoreHabitState(habit Habit) error {
+	store, err := s.loadHabits()
+	if err != nil {
+		return err
 	}
 
-	for {
-		dateStr := date.Format("2006-01-02")
-		if s.IsHabitDoneOnDate(store, habitID, dateStr) {
-			streak++
-			date = date.AddDate(0, 0, -1)
+	for i := range store.Habits {
+		h := &store.Habits[i]
+		if h.ID != habit.ID {
 			continue
 		}
-		break
+		before := snapshot(*h)
+		h.State = habit.State
+		h.StateSince = habit.StateSince
+		h.Breaks = append([]DateRange(nil), habit.Breaks...)
+		if err := s.SaveHabits(store); err != nil {
+			return err
+		}
+		// Notify with semantic context for git commit
+		s.notifySaveWithContext(SaveContext{
+			Filename:  "habits.json",
+			Operation: "update",
+			ItemType:  "habit",
+			ItemName:  truncateForCommit(h.Name, 50),
+			ItemID:    h.ID,
+			Before:    before,
+			After:     snapshot(*h),
+		})
+		return nil
 	}
 
-	return streak
+	return fmt.Errorf("habit not found: %s", habit.ID)
+}
+
+// GetHabitStreak calculates the current streak for a habit
+func (s *Storage) GetHabitStreak(store *HabitStore, habitID string) int {
+	return s.GetHabitStreakAt(store, habitID, s.Now())
+}
+
+// GetHabitStreakAt calculates the streak for a habit as of the given time,
+// following its frequency: days the habit is not due on or is excused on
+// (skipped or on vacation) are passed over, and weekly habits count weeks. If the habit is not completed on that date (or,
+// for weekly habits, in that week), the streak so far still counts.
+func (s *Storage) GetHabitStreakAt(store *HabitStore, habitID string, at time.Time) int {
+	habit := findHabit(store, habitID)
+	dates, earliest := habitDates(store, habit)
+	if len(dates) == 0 {
+		return 0
+	}
+	excused := habitExcuses(store, habit)
+	if habit.EffectiveFrequency() == FrequencyWeekly {
+		return weeklyStreak(dates, excused, earliest, startOfDay(at))
+	}
+	return dailyStreak(habit, dates, excused, earliest, startOfDay(at))
 }
 
 // GetHabitWeek returns the last 7 days of a habit (for display)
@@ -762,19 +2183,63 @@ func (s *Storage) GetHabitWeek(store *HabitStore, habitID string) []bool {
 	return week
 }
 
-// DeleteHabit removes a habit and its logs
+// GetHabitWeekProgress returns how often a habit was done and was due in
+// the last 7 days, counting only the days it is due on and not excused on.
+// Weekly habits are due once in the current week (from Sunday) unless they
+// were excused in it, and done if they were done in it.
+func (s *Storage) GetHabitWeekProgress(store *HabitStore, habitID string) (done, due int) {
+	habit := findHabit(store, habitID)
+	excused := habitExcuses(store, habit)
+	today := startOfDay(s.Now())
+
+	if habit.EffectiveFrequency() == FrequencyWeekly {
+		due = 1
+		for day := startOfWeekSunday(today); !day.After(today); day = day.AddDate(0, 0, 1) {
+			date := day.Format("2006-01-02")
+			if s.IsHabitDoneOnDate(store, habitID, date) {
+				return 1, 1
+			}
+			if excused(date) {
+				due = 0
+			}
+		}
+		return 0, due
+	}
+
+	for i := 0; i < 7; i++ {
+		day := today.AddDate(0, 0, -i)
+		if !habit.IsScheduledOn(day.Weekday()) {
+			continue
+		}
+		date := day.Format("2006-01-02")
+		switch {
+		case s.IsHabitDoneOnDate(store, habitID, date):
+			done++
+			due++
+		case !excused(date):
+			due++
+		}
+	}
+	return done, due
+}
+
+// DeleteHabit moves a habit and its logs to the trash
 func (s *Storage) DeleteHabit(id string) error {
-	store, err := s.LoadHabits()
+	return s.update(func() error { return s.deleteHabit(id) })
+}
+
+func (s *Storage) deleteHabit(id string) error {
+	store, err := s.loadHabits()
 	if err != nil {
 		return err
 	}
 
 	// Remove habit and capture name for context
-	var habitName string
+	var habit Habit
 	found := false
 	for i := range store.Habits {
 		if store.Habits[i].ID == id {
-			habitName = store.Habits[i].Name
+			habit = store.Habits[i]
 			store.Habits = append(store.Habits[:i], store.Habits[i+1:]...)
 			found = true
 			break
@@ -783,16 +2248,25 @@ func (s *Storage) DeleteHabit(id string) error {
 	if !found {
 		return fmt.Errorf("habit not found: %s", id)
 	}
+	habitName := habit.Name
 
 	// Remove associated logs
 	newLogs := []HabitLog{}
+	var habitLogs []HabitLog
 	for _, log := range store.Logs {
 		if log.HabitID != id {
 			newLogs = append(newLogs, log)
+		} else {
+			habitLogs = append(habitLogs, log)
 		}
 	}
 	store.Logs = newLogs
 
+	// The trash is written first, so an interrupted delete never loses the habit.
+	if err := s.addToTrash(TrashItem{Kind: TrashKindHabit, DeletedAt: s.Now(), Habit: &habit, Logs: habitLogs}); err != nil {
+		return err
+	}
+
 	if err := s.SaveHabits(store); err != nil {
 		return err
 	}
@@ -800,9 +2274,12 @@ func (s *Storage) DeleteHabit(id string) error {
 	// Notify with semantic context for git commit
 	s.notifySaveWithContext(SaveContext{
 		Filename:  "habits.json",
+		Files:     []string{trashFile},
 		Operation: "delete",
 		ItemType:  "habit",
 		ItemName:  truncateForCommit(habitName, 50),
+		ItemID:    id,
+		Before:    snapshot(habit),
 	})
 
 	return nil
@@ -814,18 +2291,63 @@ func (s *Storage) DeleteHabit(id string) error {
 
 // LoadTimer reads timer state from disk
 func (s *Storage) LoadTimer() (*TimerStore, error) {
-	store := TimerStore{Entries: []TimerEntry{}}
-	err := s.loadJSONWithRecovery("timer.json", &store)
+	return s.readTimer(false)
+}
+
+// loadTimer is LoadTimer for the body of an update cycle.
+func (s *Storage) loadTimer() (*TimerStore, error) {
+	return s.readTimer(true)
+}
+
+func (s *Storage) readTimer(working bool) (*TimerStore, error) {
+	store := TimerStore{Version: SchemaVersion, Entries: []TimerEntry{}}
+	err := s.loadCollection("timer.json", &store, working)
 	return &store, err
 }
 
 // SaveTimer writes timer state to disk
 func (s *Storage) SaveTimer(store *TimerStore) error {
-	return s.writeJSONAtomic("timer.json", store)
+	store.Version = SchemaVersion
+	return s.saveCollection("timer.json", store)
 }
 
 // StartTimer starts a new timer for a project
 func (s *Storage) StartTimer(project string) error {
+	return s.update(func() error { return s.startTimer(project, "") })
+}
+
+// StartTaskTimer starts a new timer tracking time on a task. The timer is
+// filed under the task's project, or under the task text when it has none.
+func (s *Storage) StartTaskTimer(taskID string) error {
+	return s.update(func() error { return s.startTaskTimer(taskID) })
+}
+
+func (s *Storage) startTaskTimer(taskID string) error {
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks.Tasks {
+		if task.ID != taskID {
+			continue
+		}
+		if task.Done {
+			return fmt.Errorf("task is already done")
+		}
+		project := task.Project
+		if project == "" {
+			project = task.Text
+			for len(project) > maxTimerProjLen {
+				_, size := utf8.DecodeLastRuneInString(project)
+				project = project[:len(project)-size]
+			}
+		}
+		return s.startTimer(project, task.ID)
+	}
+	return fmt.Errorf("task not found: %s", taskID)
+}
+
+func (s *Storage) startTimer(project, taskID string) error {
 	project = strings.TrimSpace(project)
 
 	if project == "" {
@@ -835,17 +2357,20 @@ func (s *Storage) StartTimer(project string) error {
 		return fmt.Errorf("project too long (max %d)", maxTimerProjLen)
 	}
 
-	store, err := s.LoadTimer()
+	store, err := s.loadTimer()
 	if err != nil {
 		return err
 	}
 
 	now := time.Now()
+	var before json.RawMessage
 
 	// Stop any existing timer first
 	if store.Current != nil {
+		before = snapshot(store.Current)
 		entry := TimerEntry{
 			Project:   store.Current.Project,
+			TaskID:    store.Current.TaskID,
 			StartedAt: store.Current.StartedAt,
 			EndedAt:   now,
 		}
@@ -854,6 +2379,7 @@ func (s *Storage) StartTimer(project string) error {
 
 	store.Current = &CurrentTimer{
 		Project:   project,
+		TaskID:    taskID,
 		StartedAt: now,
 	}
 
@@ -867,6 +2393,9 @@ func (s *Storage) StartTimer(project string) error {
 		Operation: "start",
 		ItemType:  "timer",
 		ItemName:  truncateForCommit(project, 50),
+		ItemID:    taskID,
+		Before:    before,
+		After:     snapshot(store.Current),
 	})
 
 	return nil
@@ -874,7 +2403,11 @@ func (s *Storage) StartTimer(project string) error {
 
 // StopTimer stops the current timer
 func (s *Storage) StopTimer() error {
-	store, err := s.LoadTimer()
+	return s.update(func() error { return s.stopTimer() })
+}
+
+func (s *Storage) stopTimer() error {
+	store, err := s.loadTimer()
 	if err != nil {
 		return err
 	}
@@ -884,9 +2417,11 @@ func (s *Storage) StopTimer() error {
 	}
 
 	projectName := store.Current.Project
+	before := snapshot(store.Current)
 	now := time.Now()
 	entry := TimerEntry{
 		Project:   store.Current.Project,
+		TaskID:    store.Current.TaskID,
 		StartedAt: store.Current.StartedAt,
 		EndedAt:   now,
 	}
@@ -903,6 +2438,9 @@ func (s *Storage) StopTimer() error {
 		Operation: "stop",
 		ItemType:  "timer",
 		ItemName:  truncateForCommit(projectName, 50),
+		ItemID:    entry.TaskID,
+		Before:    before,
+		After:     snapshot(entry),
 	})
 
 	return nil
@@ -1027,6 +2565,18 @@ func (s *Storage) GetProjectTotals(store *TimerStore) []ProjectTotal {
 	return totals
 }
 
+// TaskTotals sums the finished timer entries per task ID. Entries not linked
+// to a task are skipped; a running timer is left to the caller.
+func TaskTotals(entries []TimerEntry) map[string]time.Duration {
+	totals := make(map[string]time.Duration)
+	for _, entry := range entries {
+		if entry.TaskID != "" {
+			totals[entry.TaskID] += entry.EndedAt.Sub(entry.StartedAt)
+		}
+	}
+	return totals
+}
+
 // DayBreakdown represents time tracked for a specific day
 type DayBreakdown struct {
 	Date  string // YYYY-MM-DD format
@@ -1126,6 +2676,9 @@ func (s *Storage) ExportTasksCSV() (string, error) {
 		dueDate := ""
 		if task.DueDate != nil {
 			dueDate = task.DueDate.Format("2006-01-02")
+			if task.DueTimed {
+				dueDate = task.DueDate.Format(time.RFC3339)
+			}
 		}
 		completedAt := ""
 		if task.CompletedAt != nil {
@@ -1,11 +1,19 @@
 package storage
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"today/internal/crypt"
 )
 
 // createTestStorage creates a Storage instance with a temporary directory.
@@ -18,6 +26,10 @@ func createTestStorage(t *testing.T) *Storage {
 	return store
 }
 
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 // =============================================================================
 // Task Tests
 // =============================================================================
@@ -190,463 +202,2522 @@ func TestDeleteTask_NotFound(t *testing.T) {
 	}
 }
 
-// =============================================================================
-// Habit Tests
-// =============================================================================
+func TestUpdateTask(t *testing.T) {
+	store := createTestStorage(t)
 
-func TestAddHabit(t *testing.T) {
+	task, _ := store.AddTask("Fix typo", "", PriorityNone, nil)
+	store.CompleteTask(task.ID)
+
+	loaded, _ := store.LoadTasks()
+	original := loaded.Tasks[0]
+
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	edited := original
+	edited.Text = "  Fix typo in README  "
+	edited.Project = "docs"
+	edited.Priority = PriorityHigh
+	edited.DueDate = &due
+
+	if err := store.UpdateTask(edited); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	loaded, _ = store.LoadTasks()
+	got := loaded.Tasks[0]
+	if got.Text != "Fix typo in README" {
+		t.Errorf("task.Text = %q, want %q", got.Text, "Fix typo in README")
+	}
+	if got.Project != "docs" {
+		t.Errorf("task.Project = %q, want %q", got.Project, "docs")
+	}
+	if got.Priority != PriorityHigh {
+		t.Errorf("task.Priority = %q, want %q", got.Priority, PriorityHigh)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(due) {
+		t.Errorf("task.DueDate = %v, want %v", got.DueDate, due)
+	}
+
+	// Identity and completion state must survive the edit.
+	if !got.CreatedAt.Equal(original.CreatedAt) {
+		t.Errorf("task.CreatedAt = %v, want %v", got.CreatedAt, original.CreatedAt)
+	}
+	if !got.Done || got.CompletedAt == nil {
+		t.Error("UpdateTask() should preserve completion state")
+	}
+}
+
+func TestUpdateTask_Validation(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Task", "", PriorityNone, nil)
+
+	if err := store.UpdateTask(Task{ID: "nonexistent", Text: "x"}); err == nil {
+		t.Error("UpdateTask() expected error for nonexistent task")
+	}
+	if err := store.UpdateTask(Task{ID: task.ID, Text: "   "}); err == nil {
+		t.Error("UpdateTask() expected error for empty text")
+	}
+	if err := store.UpdateTask(Task{ID: task.ID, Text: "Task", Priority: "urgent"}); err == nil {
+		t.Error("UpdateTask() expected error for invalid priority")
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
 	tests := []struct {
-		name  string
-		hName string
-		icon  string
+		input   string
+		want    string // canonical String() form; "" for no recurrence
+		wantErr bool
 	}{
-		{
-			name:  "simple habit",
-			hName: "Exercise",
-			icon:  "🏃",
-		},
-		{
-			name:  "habit with text icon",
-			hName: "Read",
-			icon:  "📚",
-		},
+		{"", "", false},
+		{"none", "", false},
+		{"daily", "daily", false},
+		{"every 3 days", "every 3 days", false},
+		{"every 1 day", "daily", false},
+		{"weekly", "weekly", false},
+		{"weekly fri,mon", "weekly mon,fri", false},
+		{"Weekly Tuesday, thu", "weekly tue,thu", false},
+		{"monthly", "monthly", false},
+		{"monthly 31", "monthly 31", false},
+		{"after 10 days", "after 10 days", false},
+		{"after 1 day", "after 1 day", false},
+		{"monthly 0", "", true},
+		{"every 0 days", "", true},
+		{"weekly funday", "", true},
+		{"every week", "", true},
+		{"yearly", "", true},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			store := createTestStorage(t)
-
-			habit, err := store.AddHabit(tt.hName, tt.icon)
-			if err != nil {
-				t.Fatalf("AddHabit() error = %v", err)
-			}
-
-			if habit.Name != tt.hName {
-				t.Errorf("habit.Name = %q, want %q", habit.Name, tt.hName)
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecurrence(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
 			}
-			if habit.Icon != tt.icon {
-				t.Errorf("habit.Icon = %q, want %q", habit.Icon, tt.icon)
+			if tt.wantErr {
+				return
 			}
-			if habit.ID == "" {
-				t.Error("habit.ID is empty")
+			gotStr := ""
+			if got != nil {
+				gotStr = got.String()
 			}
-
-			// Verify persistence
-			loaded, _ := store.LoadHabits()
-			if len(loaded.Habits) != 1 {
-				t.Fatalf("len(habits) = %d, want 1", len(loaded.Habits))
+			if gotStr != tt.want {
+				t.Errorf("ParseRecurrence(%q) = %q, want %q", tt.input, gotStr, tt.want)
 			}
 		})
 	}
 }
 
-func TestAddHabit_Validation(t *testing.T) {
-	store := createTestStorage(t)
+func TestRecurrenceNextDue(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	// Wednesday 2025-01-15
+	completed := time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC)
 
-	if _, err := store.AddHabit("   ", "🏃"); err == nil {
-		t.Fatal("AddHabit() expected error for empty habit name")
+	tests := []struct {
+		name string
+		rule Recurrence
+		due  *time.Time
+		want time.Time
+	}{
+		{"daily from due", Recurrence{Kind: RecurDaily}, timePtr(date(2025, 1, 15)), date(2025, 1, 16)},
+		{"daily skips missed days", Recurrence{Kind: RecurDaily}, timePtr(date(2025, 1, 10)), date(2025, 1, 16)},
+		{"every 3 days early completion", Recurrence{Kind: RecurDaily, Interval: 3}, timePtr(date(2025, 1, 20)), date(2025, 1, 23)},
+		{"daily without due", Recurrence{Kind: RecurDaily}, nil, date(2025, 1, 16)},
+		{"weekly same weekday", Recurrence{Kind: RecurWeekly}, timePtr(date(2025, 1, 15)), date(2025, 1, 22)},
+		{"weekly on mon,fri", Recurrence{Kind: RecurWeekly, Weekdays: []int{1, 5}}, timePtr(date(2025, 1, 13)), date(2025, 1, 17)},
+		{"monthly on 31st clamps", Recurrence{Kind: RecurMonthly, DayOfMonth: 31}, timePtr(date(2025, 1, 31)), date(2025, 2, 28)},
+		{"monthly on 5th", Recurrence{Kind: RecurMonthly, DayOfMonth: 5}, timePtr(date(2025, 1, 5)), date(2025, 2, 5)},
+		{"monthly from due day", Recurrence{Kind: RecurMonthly}, timePtr(date(2024, 12, 20)), date(2025, 1, 20)},
+		{"after completion", Recurrence{Kind: RecurAfterCompletion, Interval: 10}, timePtr(date(2025, 1, 1)), date(2025, 1, 25)},
 	}
-	if _, err := store.AddHabit("Exercise", ""); err == nil {
-		t.Fatal("AddHabit() expected error for empty icon")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.NextDue(tt.due, completed)
+			if !got.Equal(tt.want) {
+				t.Errorf("NextDue() = %s, want %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
 	}
 }
 
-func TestToggleHabitToday(t *testing.T) {
+func TestCompleteTask_Recurring(t *testing.T) {
 	store := createTestStorage(t)
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	store.SetNowFunc(func() time.Time { return now })
 
-	habit, _ := store.AddHabit("Exercise", "🏃")
-
-	// Toggle on
-	isDone, err := store.ToggleHabitToday(habit.ID)
-	if err != nil {
-		t.Fatalf("ToggleHabitToday() error = %v", err)
+	due := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	task, _ := store.AddTask("Water plants", "home", PriorityLow, &due)
+	task.Recurrence = &Recurrence{Kind: RecurDaily, Interval: 2}
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
 	}
-	if !isDone {
-		t.Error("isDone = false, want true after toggle on")
+
+	if err := store.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
 	}
 
-	// Verify log was created
-	loaded, _ := store.LoadHabits()
-	if len(loaded.Logs) != 1 {
-		t.Fatalf("len(logs) = %d, want 1", len(loaded.Logs))
+	ts, _ := store.LoadTasks()
+	if len(ts.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks after completing recurring task, got %d", len(ts.Tasks))
+	}
+	next := ts.Tasks[1]
+	if next.Done || next.SpawnedFrom != task.ID || next.Text != "Water plants" || next.Project != "home" {
+		t.Errorf("unexpected next occurrence: %+v", next)
+	}
+	if next.DueDate == nil || !next.DueDate.Equal(due.AddDate(0, 0, 2)) {
+		t.Errorf("next occurrence due = %v, want %v", next.DueDate, due.AddDate(0, 0, 2))
+	}
+	if next.Recurrence == nil || next.Recurrence.Interval != 2 {
+		t.Errorf("next occurrence should keep the recurrence rule, got %+v", next.Recurrence)
 	}
 
-	// Toggle off
-	isDone, err = store.ToggleHabitToday(habit.ID)
-	if err != nil {
-		t.Fatalf("ToggleHabitToday() error = %v", err)
+	// Completing again must not spawn a second occurrence
+	if err := store.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
 	}
-	if isDone {
-		t.Error("isDone = true, want false after toggle off")
+	ts, _ = store.LoadTasks()
+	if len(ts.Tasks) != 2 {
+		t.Errorf("expected 2 tasks after re-completing, got %d", len(ts.Tasks))
 	}
 
-	// Verify log was removed
-	loaded, _ = store.LoadHabits()
-	if len(loaded.Logs) != 0 {
-		t.Fatalf("len(logs) = %d, want 0", len(loaded.Logs))
+	// Reopening removes the pending spawned occurrence
+	if err := store.UncompleteTask(task.ID); err != nil {
+		t.Fatalf("UncompleteTask() error = %v", err)
+	}
+	ts, _ = store.LoadTasks()
+	if len(ts.Tasks) != 1 || ts.Tasks[0].ID != task.ID || ts.Tasks[0].Done {
+		t.Errorf("expected only the reopened task to remain, got %+v", ts.Tasks)
 	}
 }
 
-func TestToggleHabitToday_RemovesDuplicates(t *testing.T) {
+func TestUncompleteTask_KeepsEditedOccurrence(t *testing.T) {
 	store := createTestStorage(t)
+	now := time.Date(2025, 3, 7, 16, 30, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return now })
 
-	habit, _ := store.AddHabit("Exercise", "🏃")
-	today := time.Now().Format("2006-01-02")
-
-	hs, _ := store.LoadHabits()
-	hs.Logs = append(hs.Logs,
-		HabitLog{HabitID: habit.ID, Date: today},
-		HabitLog{HabitID: habit.ID, Date: today},
-	)
-	if err := store.SaveHabits(hs); err != nil {
-		t.Fatalf("SaveHabits() error = %v", err)
+	zone, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("zone database unavailable: %v", err)
+	}
+	due := time.Date(2025, 3, 7, 14, 0, 0, 0, zone)
+	task, _ := store.AddTask("Send timesheet", "work", PriorityNone, &due)
+	task.DueTimed = true
+	task.DueZone = "America/New_York"
+	task.Recurrence = &Recurrence{Kind: RecurWeekly}
+	task.Checklist = []ChecklistItem{{ID: "c1", Text: "Export hours", Done: true}}
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
 	}
 
-	isDone, err := store.ToggleHabitToday(habit.ID)
-	if err != nil {
-		t.Fatalf("ToggleHabitToday() error = %v", err)
+	// An untouched occurrence goes away again, even after a round trip
+	// through the file.
+	if err := store.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
 	}
-	if isDone {
-		t.Fatal("ToggleHabitToday() expected to toggle off (false)")
+	if err := store.UncompleteTask(task.ID); err != nil {
+		t.Fatalf("UncompleteTask() error = %v", err)
+	}
+	ts, _ := store.LoadTasks()
+	if len(ts.Tasks) != 1 {
+		t.Fatalf("expected the unedited occurrence to be removed, got %d tasks", len(ts.Tasks))
 	}
 
-	hs, _ = store.LoadHabits()
-	for _, log := range hs.Logs {
-		if log.HabitID == habit.ID && log.Date == today {
-			t.Fatal("expected all duplicate logs for today to be removed")
-		}
+	// An occurrence edited since completion is kept.
+	if err := store.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	ts, _ = store.LoadTasks()
+	next := ts.Tasks[1]
+	next.Notes = "Include the travel day"
+	if err := store.UpdateTask(next); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if err := store.UncompleteTask(task.ID); err != nil {
+		t.Fatalf("UncompleteTask() error = %v", err)
+	}
+	ts, _ = store.LoadTasks()
+	if len(ts.Tasks) != 2 || ts.Tasks[1].ID != next.ID || ts.Tasks[1].Notes != next.Notes {
+		t.Errorf("expected the edited occurrence to be kept, got %+v", ts.Tasks)
 	}
 }
 
-func TestIsHabitDoneOnDate(t *testing.T) {
+func TestDeferredTasks(t *testing.T) {
 	store := createTestStorage(t)
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	store.SetNowFunc(func() time.Time { return now })
 
-	habit, _ := store.AddHabit("Exercise", "🏃")
-	today := time.Now().Format("2006-01-02")
-
-	// Not done initially
-	hs, _ := store.LoadHabits()
-	if store.IsHabitDoneOnDate(hs, habit.ID, today) {
-		t.Error("IsHabitDoneOnDate() = true, want false before toggle")
+	task, _ := store.AddTask("Renew passport", "", PriorityNone, nil)
+	if task.IsDeferred(now) {
+		t.Error("task without a start date should not be deferred")
 	}
 
-	// Toggle on
-	store.ToggleHabitToday(habit.ID)
-	hs, _ = store.LoadHabits()
-	if !store.IsHabitDoneOnDate(hs, habit.ID, today) {
-		t.Error("IsHabitDoneOnDate() = false, want true after toggle")
+	// Snoozing starts from today, then stacks on a future start date
+	start := SnoozeStart(*task, now, 1)
+	if !start.Equal(time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("SnoozeStart() = %v, want Jan 16", start)
+	}
+	task.StartDate = &start
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	ts, _ := store.LoadTasks()
+	got := ts.Tasks[0]
+	if got.StartDate == nil || !got.StartDate.Equal(start) {
+		t.Fatalf("persisted StartDate = %v, want %v", got.StartDate, start)
+	}
+	if !got.IsDeferred(now) || got.IsDeferred(start) {
+		t.Error("task should be deferred until its start date and actionable on it")
+	}
+	if again := SnoozeStart(got, now, 1); again.Day() != 17 {
+		t.Errorf("SnoozeStart() on a deferred task = %v, want Jan 17", again)
+	}
+	got.Done = true
+	if got.IsDeferred(now) {
+		t.Error("completed tasks are never deferred")
 	}
 }
 
-func TestGetHabitStreak(t *testing.T) {
+func TestCompleteTask_RecurringKeepsStartLead(t *testing.T) {
 	store := createTestStorage(t)
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	store.SetNowFunc(func() time.Time { return now })
 
-	habit, _ := store.AddHabit("Exercise", "🏃")
-	hs, _ := store.LoadHabits()
-
-	// No streak initially
-	streak := store.GetHabitStreak(hs, habit.ID)
-	if streak != 0 {
-		t.Errorf("GetHabitStreak() = %d, want 0", streak)
+	due := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	task, _ := store.AddTask("Submit timesheet", "", PriorityNone, &due)
+	task.Recurrence = &Recurrence{Kind: RecurWeekly}
+	task.StartDate = timePtr(due.AddDate(0, 0, -2))
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if err := store.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
 	}
 
-	// Add log for today
-	store.ToggleHabitToday(habit.ID)
-	hs, _ = store.LoadHabits()
-	streak = store.GetHabitStreak(hs, habit.ID)
-	if streak != 1 {
-		t.Errorf("GetHabitStreak() = %d, want 1", streak)
+	ts, _ := store.LoadTasks()
+	next := ts.Tasks[1]
+	want := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	if next.StartDate == nil || !next.StartDate.Equal(want) {
+		t.Errorf("next occurrence start = %v, want %v (two days before due)", next.StartDate, want)
 	}
 }
 
-func TestGetHabitWeek(t *testing.T) {
+func TestTimedDueDates(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("zoneinfo not available")
+	}
 	store := createTestStorage(t)
+	// Completed on Friday, two days before clocks go forward.
+	now := time.Date(2025, 3, 28, 15, 0, 0, 0, berlin)
+	store.SetNowFunc(func() time.Time { return now })
 
-	habit, _ := store.AddHabit("Exercise", "🏃")
+	due := time.Date(2025, 3, 28, 14, 0, 0, 0, berlin)
+	task, _ := store.AddTask("Send report", "", PriorityNone, &due)
+	task.DueTimed = true
+	task.DueZone = "Europe/Berlin"
+	task.Recurrence = &Recurrence{Kind: RecurDaily, Interval: 3}
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
 
-	// All false initially
-	hs, _ := store.LoadHabits()
-	week := store.GetHabitWeek(hs, habit.ID)
-	if len(week) != 7 {
-		t.Fatalf("len(week) = %d, want 7", len(week))
+	ts, _ := store.LoadTasks()
+	got := ts.Tasks[0]
+	if !got.DueTimed || got.DueZone != "Europe/Berlin" || !got.DueDate.Equal(due) {
+		t.Fatalf("persisted due = %v (timed %v, zone %q), want %v in Europe/Berlin", got.DueDate, got.DueTimed, got.DueZone, due)
 	}
-	for i, done := range week {
-		if done {
-			t.Errorf("week[%d] = true, want false", i)
-		}
+	if !got.Overdue(now) || got.Overdue(due.Add(-time.Minute)) {
+		t.Error("timed task should be overdue only after its due time")
 	}
 
-	// Toggle today
-	store.ToggleHabitToday(habit.ID)
-	hs, _ = store.LoadHabits()
-	week = store.GetHabitWeek(hs, habit.ID)
+	// The next occurrence keeps 14:00 Berlin time across the DST change.
+	if err := store.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	ts, _ = store.LoadTasks()
+	next := ts.Tasks[1]
+	want := time.Date(2025, 3, 31, 14, 0, 0, 0, berlin)
+	if !next.DueTimed || next.DueZone != "Europe/Berlin" || !next.DueDate.Equal(want) {
+		t.Errorf("next occurrence due = %v (timed %v), want %v", next.DueDate, next.DueTimed, want)
+	}
 
-	// Last element (today) should be true
-	if !week[6] {
-		t.Error("week[6] (today) = false, want true")
+	// Clearing the time drops the zone too.
+	got.DueTimed = false
+	if err := store.UpdateTask(got); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	ts, _ = store.LoadTasks()
+	if ts.Tasks[0].DueZone != "" {
+		t.Errorf("DueZone = %q after clearing the time, want empty", ts.Tasks[0].DueZone)
 	}
 }
 
-func TestDeleteHabit(t *testing.T) {
+func TestUpdateTask_Notes(t *testing.T) {
 	store := createTestStorage(t)
 
-	habit, _ := store.AddHabit("Exercise", "🏃")
-	store.ToggleHabitToday(habit.ID) // Add a log
-
-	// Delete
-	if err := store.DeleteHabit(habit.ID); err != nil {
-		t.Fatalf("DeleteHabit() error = %v", err)
+	task, _ := store.AddTask("Plan offsite", "", PriorityNone, nil)
+	task.Notes = "Venue options:\n- lake house\n- city hotel"
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
 	}
 
-	// Verify habit and logs are removed
-	hs, _ := store.LoadHabits()
-	if len(hs.Habits) != 0 {
-		t.Errorf("len(habits) = %d, want 0", len(hs.Habits))
+	tasks, _ := store.LoadTasks()
+	if tasks.Tasks[0].Notes != task.Notes {
+		t.Errorf("Notes = %q, want %q", tasks.Tasks[0].Notes, task.Notes)
 	}
-	if len(hs.Logs) != 0 {
-		t.Errorf("len(logs) = %d, want 0", len(hs.Logs))
+
+	task.Notes = strings.Repeat("x", maxNotesLen+1)
+	if err := store.UpdateTask(*task); err == nil {
+		t.Error("UpdateTask() expected error for overlong notes")
 	}
 }
 
-func TestDeleteHabit_NotFound(t *testing.T) {
+func TestSetTaskNotes(t *testing.T) {
 	store := createTestStorage(t)
 
-	if err := store.DeleteHabit("missing"); err == nil {
-		t.Fatal("DeleteHabit() expected error for missing habit")
-	}
-}
-
-// =============================================================================
-// Timer Tests
-// =============================================================================
+	task, _ := store.AddTask("Plan offsite", "", PriorityNone, nil)
+	opened := *task
 
-func TestStartTimer(t *testing.T) {
-	store := createTestStorage(t)
+	// Renamed elsewhere while the notes were being edited
+	task.Text = "Plan team offsite"
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
 
-	if err := store.StartTimer("Project A"); err != nil {
-		t.Fatalf("StartTimer() error = %v", err)
+	before, after, err := store.SetTaskNotes(opened.ID, "Book the lake house")
+	if err != nil {
+		t.Fatalf("SetTaskNotes() error = %v", err)
+	}
+	if before.Notes != "" || before.Text != "Plan team offsite" || after.Notes != "Book the lake house" {
+		t.Errorf("SetTaskNotes() = %+v → %+v, want the current task with new notes", before, after)
+	}
+	tasks, _ := store.LoadTasks()
+	if got := tasks.Tasks[0]; got.Text != "Plan team offsite" || got.Notes != "Book the lake house" {
+		t.Errorf("task = %q with notes %q, want the rename kept and the notes set", got.Text, got.Notes)
 	}
 
-	ts, _ := store.LoadTimer()
-	if ts.Current == nil {
-		t.Fatal("ts.Current is nil")
+	if _, _, err := store.SetTaskNotes(opened.ID, strings.Repeat("x", maxNotesLen+1)); err == nil {
+		t.Error("SetTaskNotes() expected error for overlong notes")
 	}
-	if ts.Current.Project != "Project A" {
-		t.Errorf("Current.Project = %q, want %q", ts.Current.Project, "Project A")
+	if _, _, err := store.SetTaskNotes("missing", "notes"); err == nil {
+		t.Error("SetTaskNotes() expected error for an unknown task")
 	}
 }
 
-func TestStartTimer_Validation(t *testing.T) {
+func TestUpdateTask_InvalidRecurrence(t *testing.T) {
 	store := createTestStorage(t)
-	if err := store.StartTimer("   "); err == nil {
-		t.Fatal("StartTimer() expected error for empty project")
+
+	task, _ := store.AddTask("Task", "", PriorityNone, nil)
+	task.Recurrence = &Recurrence{Kind: "hourly"}
+	if err := store.UpdateTask(*task); err == nil {
+		t.Error("UpdateTask() expected error for invalid recurrence")
 	}
 }
 
-func TestStartTimer_SwitchProject(t *testing.T) {
+func TestChecklistItems(t *testing.T) {
 	store := createTestStorage(t)
 
-	// Start first timer
-	store.StartTimer("Project A")
-	time.Sleep(10 * time.Millisecond) // Ensure some time passes
+	task, _ := store.AddTask("Ship release 1.4", "", PriorityNone, nil)
 
-	// Switch to second project
-	if err := store.StartTimer("Project B"); err != nil {
-		t.Fatalf("StartTimer() error = %v", err)
+	first, err := store.AddChecklistItem(task.ID, "  Tag release  ")
+	if err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	if first.Text != "Tag release" || first.Done {
+		t.Errorf("AddChecklistItem() = %+v, want trimmed pending item", first)
 	}
+	second, _ := store.AddChecklistItem(task.ID, "Publish notes")
 
-	ts, _ := store.LoadTimer()
+	if err := store.SetChecklistItemDone(task.ID, first.ID, true); err != nil {
+		t.Fatalf("SetChecklistItemDone() error = %v", err)
+	}
 
-	// Current should be Project B
-	if ts.Current.Project != "Project B" {
-		t.Errorf("Current.Project = %q, want %q", ts.Current.Project, "Project B")
+	ts, _ := store.LoadTasks()
+	if done, total := ts.Tasks[0].ChecklistProgress(); done != 1 || total != 2 {
+		t.Errorf("ChecklistProgress() = %d/%d, want 1/2", done, total)
 	}
 
-	// Should have one entry for Project A
-	if len(ts.Entries) != 1 {
-		t.Fatalf("len(Entries) = %d, want 1", len(ts.Entries))
+	if err := store.DeleteChecklistItem(task.ID, first.ID); err != nil {
+		t.Fatalf("DeleteChecklistItem() error = %v", err)
+	}
+	if err := store.RestoreChecklistItem(task.ID, *first, 0); err != nil {
+		t.Fatalf("RestoreChecklistItem() error = %v", err)
+	}
+	ts, _ = store.LoadTasks()
+	items := ts.Tasks[0].Checklist
+	if len(items) != 2 || items[0].ID != first.ID || items[1].ID != second.ID {
+		t.Errorf("checklist after restore = %+v, want original order", items)
+	}
+
+	data, _ := store.ExportTasksJSON()
+	if !bytes.Contains(data, []byte(`"checklist"`)) || !bytes.Contains(data, []byte("Publish notes")) {
+		t.Error("ExportTasksJSON() should include checklist items")
+	}
+}
+
+func TestChecklistItems_Errors(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Task", "", PriorityNone, nil)
+
+	if _, err := store.AddChecklistItem(task.ID, "   "); err == nil {
+		t.Error("AddChecklistItem() expected error for empty text")
+	}
+	if _, err := store.AddChecklistItem("nonexistent", "Item"); err == nil {
+		t.Error("AddChecklistItem() expected error for nonexistent task")
+	}
+	if err := store.SetChecklistItemDone(task.ID, "nonexistent", true); err == nil {
+		t.Error("SetChecklistItemDone() expected error for nonexistent item")
+	}
+	if err := store.DeleteChecklistItem(task.ID, "nonexistent"); err == nil {
+		t.Error("DeleteChecklistItem() expected error for nonexistent item")
+	}
+}
+
+func TestCompleteTask_RecurringResetsChecklist(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Weekly review", "", PriorityNone, nil)
+	task.Recurrence = &Recurrence{Kind: RecurWeekly}
+	store.UpdateTask(*task)
+	item, _ := store.AddChecklistItem(task.ID, "Inbox zero")
+	store.SetChecklistItemDone(task.ID, item.ID, true)
+
+	store.CompleteTask(task.ID)
+
+	ts, _ := store.LoadTasks()
+	next := ts.Tasks[1]
+	if len(next.Checklist) != 1 || next.Checklist[0].Done {
+		t.Errorf("next occurrence checklist = %+v, want one unchecked item", next.Checklist)
+	}
+}
+
+func TestBlockers(t *testing.T) {
+	store := createTestStorage(t)
+
+	design, _ := store.AddTask("Design", "", PriorityNone, nil)
+	build, _ := store.AddTask("Build", "", PriorityHigh, nil)
+	ship, _ := store.AddTask("Ship", "", PriorityNone, nil)
+
+	if err := store.AddBlocker(build.ID, design.ID); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+	if err := store.AddBlocker(ship.ID, build.ID); err != nil {
+		t.Fatalf("AddBlocker() error = %v", err)
+	}
+
+	// Direct, transitive and self cycles are rejected, as are duplicates
+	for _, tc := range []struct{ task, blocker string }{
+		{design.ID, build.ID},
+		{design.ID, ship.ID},
+		{design.ID, design.ID},
+		{build.ID, design.ID},
+		{build.ID, "nonexistent"},
+	} {
+		if err := store.AddBlocker(tc.task, tc.blocker); err == nil {
+			t.Errorf("AddBlocker(%s, %s) expected error", tc.task, tc.blocker)
+		}
+	}
+
+	// Blocked tasks sort below actionable ones, even with higher priority
+	ts, _ := store.LoadTasks()
+	sorted := store.SortTasks(ts.Tasks)
+	if sorted[0].ID != design.ID {
+		t.Errorf("SortTasks() first = %q, want the only actionable task", sorted[0].Text)
+	}
+	if blockers := PendingBlockers(ts.Tasks, sorted[1]); len(blockers) != 1 {
+		t.Errorf("PendingBlockers(%q) = %d, want 1", sorted[1].Text, len(blockers))
+	}
+
+	// Completing the blocker unblocks the task; reopening blocks it again
+	store.CompleteTask(design.ID)
+	ts, _ = store.LoadTasks()
+	if blockers := PendingBlockers(ts.Tasks, ts.Tasks[1]); len(blockers) != 0 {
+		t.Errorf("Build should be unblocked once Design is done, got %d blockers", len(blockers))
+	}
+	if sorted := store.SortTasks(ts.Tasks); sorted[0].ID != build.ID {
+		t.Errorf("SortTasks() first = %q, want Build once unblocked", sorted[0].Text)
+	}
+	store.UncompleteTask(design.ID)
+	ts, _ = store.LoadTasks()
+	if blockers := PendingBlockers(ts.Tasks, ts.Tasks[1]); len(blockers) != 1 {
+		t.Error("Build should be blocked again once Design is reopened")
+	}
+
+	if err := store.RemoveBlocker(build.ID, design.ID); err != nil {
+		t.Fatalf("RemoveBlocker() error = %v", err)
+	}
+	if err := store.RemoveBlocker(build.ID, design.ID); err == nil {
+		t.Error("RemoveBlocker() expected error when not blocked")
+	}
+	ts, _ = store.LoadTasks()
+	if ts.Tasks[1].BlockedBy != nil {
+		t.Errorf("BlockedBy = %v, want nil", ts.Tasks[1].BlockedBy)
+	}
+}
+
+func TestSetTaskOrder(t *testing.T) {
+	store := createTestStorage(t)
+
+	var ids []string
+	for _, text := range []string{"One", "Two", "Three", "Four"} {
+		task, _ := store.AddTask(text, "", PriorityNone, nil)
+		ids = append(ids, task.ID)
+	}
+	store.CompleteTask(ids[0])
+
+	texts := func() string {
+		ts, _ := store.LoadTasks()
+		var out []string
+		for _, task := range store.SortTasksManual(ts.Tasks) {
+			out = append(out, task.Text)
+		}
+		return strings.Join(out, ",")
+	}
+
+	// Never reordered: newest first, completed last
+	if got := texts(); got != "Four,Three,Two,One" {
+		t.Errorf("initial manual order = %s", got)
+	}
+
+	// Listed tasks come first in the given order; the rest keep theirs
+	if err := store.SetTaskOrder([]string{ids[1], ids[3]}); err != nil {
+		t.Fatalf("SetTaskOrder() error = %v", err)
+	}
+	if got := texts(); got != "Two,Four,Three,One" {
+		t.Errorf("manual order = %s, want Two,Four,Three,One", got)
+	}
+
+	// A new task shows up on top without disturbing the manual order
+	store.AddTask("Five", "", PriorityHigh, nil)
+	if got := texts(); got != "Five,Two,Four,Three,One" {
+		t.Errorf("manual order after add = %s", got)
+	}
+}
+
+func TestArchiveTasks(t *testing.T) {
+	store := createTestStorage(t)
+
+	completeAt := func(text string, at time.Time) *Task {
+		task, _ := store.AddTask(text, "", PriorityNone, nil)
+		store.SetNowFunc(func() time.Time { return at })
+		store.CompleteTask(task.ID)
+		return task
+	}
+	completeAt("January report", time.Date(2025, 1, 20, 9, 0, 0, 0, time.Local))
+	completeAt("February report", time.Date(2025, 2, 3, 9, 0, 0, 0, time.Local))
+	completeAt("Recent", time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local))
+	store.AddTask("Pending", "", PriorityNone, nil)
+
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local) })
+	var saved []SaveContext
+	store.SetOnSaveWithContext(func(ctx SaveContext) { saved = append(saved, ctx) })
+	n, err := store.ArchiveTasks(30)
+	if err != nil {
+		t.Fatalf("ArchiveTasks() error = %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("ArchiveTasks() = %d, want 2", n)
+	}
+	// Git sync stages every file the context names.
+	wantFiles := []string{filepath.Join("archive", "tasks-2025-01.json"), filepath.Join("archive", "tasks-2025-02.json")}
+	if len(saved) != 1 || saved[0].Filename != "tasks.json" || !reflect.DeepEqual(saved[0].Files, wantFiles) {
+		t.Errorf("archive save contexts = %+v, want tasks.json with files %v", saved, wantFiles)
+	}
+
+	ts, _ := store.LoadTasks()
+	if len(ts.Tasks) != 2 {
+		t.Errorf("tasks.json keeps %d tasks, want 2 (recent + pending)", len(ts.Tasks))
+	}
+	for _, month := range []string{"2025-01", "2025-02"} {
+		if _, err := os.Stat(filepath.Join(store.GetDataDir(), "archive", "tasks-"+month+".json")); err != nil {
+			t.Errorf("archive for %s missing: %v", month, err)
+		}
+	}
+
+	// Only archives from the requested month onward are read
+	archived, err := store.LoadArchivedTasks(time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("LoadArchivedTasks() error = %v", err)
+	}
+	if len(archived) != 1 || archived[0].Text != "February report" {
+		t.Errorf("LoadArchivedTasks(Feb) = %+v, want the February task", archived)
+	}
+
+	// Archiving again is a no-op; archiving everything appends to the months
+	if n, _ := store.ArchiveTasks(30); n != 0 {
+		t.Errorf("second ArchiveTasks() = %d, want 0", n)
+	}
+	if n, _ := store.ArchiveTasks(0); n != 1 {
+		t.Errorf("ArchiveTasks(0) = %d, want 1", n)
+	}
+	archived, _ = store.LoadArchivedTasks(time.Time{})
+	if len(archived) != 3 {
+		t.Errorf("LoadArchivedTasks() = %d tasks, want 3", len(archived))
+	}
+
+	if _, err := store.ArchiveTasks(-1); err == nil {
+		t.Error("ArchiveTasks() expected error for negative age")
+	}
+}
+
+func TestExtractTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantText string
+		wantTags string
+	}{
+		{"Buy milk", "Buy milk", ""},
+		{"Email Bob #Client-A #low-energy", "Email Bob", "client-a,low-energy"},
+		{"Fix #123 in #backend", "Fix #123 in", "backend"},
+		{"# heading #", "# heading #", ""},
+		{"#only", "", "only"},
+	}
+
+	for _, tt := range tests {
+		text, tags := ExtractTags(tt.input)
+		if text != tt.wantText || strings.Join(tags, ",") != tt.wantTags {
+			t.Errorf("ExtractTags(%q) = %q, %v; want %q, %q", tt.input, text, tags, tt.wantText, tt.wantTags)
+		}
+	}
+}
+
+func TestAddTask_Tags(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, err := store.AddTask("Review contract", "", PriorityNone, nil, "#Client-A", "legal", "client-a", " ")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if got := strings.Join(task.Tags, ","); got != "client-a,legal" {
+		t.Errorf("AddTask() tags = %q, want normalized and deduplicated", got)
+	}
+	if !task.HasTag("#LEGAL") {
+		t.Error("HasTag() should match case-insensitively with optional '#'")
+	}
+
+	if _, err := store.AddTask("Bad", "", PriorityNone, nil, "two words"); err == nil {
+		t.Error("AddTask() expected error for tag with a space")
+	}
+
+	task.Tags = []string{strings.Repeat("x", maxTagLen+1)}
+	if err := store.UpdateTask(*task); err == nil {
+		t.Error("UpdateTask() expected error for overlong tag")
+	}
+
+	tags, err := ParseTagList("#a, b  c")
+	if err != nil || strings.Join(tags, ",") != "a,b,c" {
+		t.Errorf("ParseTagList() = %v, %v; want [a b c]", tags, err)
+	}
+}
+
+// =============================================================================
+// Habit Tests
+// =============================================================================
+
+func TestAddHabit(t *testing.T) {
+	tests := []struct {
+		name  string
+		hName string
+		icon  string
+	}{
+		{
+			name:  "simple habit",
+			hName: "Exercise",
+			icon:  "🏃",
+		},
+		{
+			name:  "habit with text icon",
+			hName: "Read",
+			icon:  "📚",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := createTestStorage(t)
+
+			habit, err := store.AddHabit(tt.hName, tt.icon, FrequencyDaily)
+			if err != nil {
+				t.Fatalf("AddHabit() error = %v", err)
+			}
+
+			if habit.Name != tt.hName {
+				t.Errorf("habit.Name = %q, want %q", habit.Name, tt.hName)
+			}
+			if habit.Icon != tt.icon {
+				t.Errorf("habit.Icon = %q, want %q", habit.Icon, tt.icon)
+			}
+			if habit.ID == "" {
+				t.Error("habit.ID is empty")
+			}
+
+			// Verify persistence
+			loaded, _ := store.LoadHabits()
+			if len(loaded.Habits) != 1 {
+				t.Fatalf("len(habits) = %d, want 1", len(loaded.Habits))
+			}
+		})
+	}
+}
+
+func TestAddHabit_Validation(t *testing.T) {
+	store := createTestStorage(t)
+
+	if _, err := store.AddHabit("   ", "🏃", FrequencyDaily); err == nil {
+		t.Fatal("AddHabit() expected error for empty habit name")
+	}
+	if _, err := store.AddHabit("Exercise", "", FrequencyDaily); err == nil {
+		t.Fatal("AddHabit() expected error for empty icon")
+	}
+}
+
+func TestToggleHabitToday(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+
+	// Toggle on
+	isDone, err := store.ToggleHabitToday(habit.ID)
+	if err != nil {
+		t.Fatalf("ToggleHabitToday() error = %v", err)
+	}
+	if !isDone {
+		t.Error("isDone = false, want true after toggle on")
+	}
+
+	// Verify log was created
+	loaded, _ := store.LoadHabits()
+	if len(loaded.Logs) != 1 {
+		t.Fatalf("len(logs) = %d, want 1", len(loaded.Logs))
+	}
+
+	// Toggle off
+	isDone, err = store.ToggleHabitToday(habit.ID)
+	if err != nil {
+		t.Fatalf("ToggleHabitToday() error = %v", err)
+	}
+	if isDone {
+		t.Error("isDone = true, want false after toggle off")
+	}
+
+	// Verify log was removed
+	loaded, _ = store.LoadHabits()
+	if len(loaded.Logs) != 0 {
+		t.Fatalf("len(logs) = %d, want 0", len(loaded.Logs))
+	}
+}
+
+func TestToggleHabitToday_RemovesDuplicates(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+	today := time.Now().Format("2006-01-02")
+
+	hs, _ := store.LoadHabits()
+	hs.Logs = append(hs.Logs,
+		HabitLog{HabitID: habit.ID, Date: today},
+		HabitLog{HabitID: habit.ID, Date: today},
+	)
+	if err := store.SaveHabits(hs); err != nil {
+		t.Fatalf("SaveHabits() error = %v", err)
+	}
+
+	isDone, err := store.ToggleHabitToday(habit.ID)
+	if err != nil {
+		t.Fatalf("ToggleHabitToday() error = %v", err)
+	}
+	if isDone {
+		t.Fatal("ToggleHabitToday() expected to toggle off (false)")
+	}
+
+	hs, _ = store.LoadHabits()
+	for _, log := range hs.Logs {
+		if log.HabitID == habit.ID && log.Date == today {
+			t.Fatal("expected all duplicate logs for today to be removed")
+		}
+	}
+}
+
+func TestIsHabitDoneOnDate(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+	today := time.Now().Format("2006-01-02")
+
+	// Not done initially
+	hs, _ := store.LoadHabits()
+	if store.IsHabitDoneOnDate(hs, habit.ID, today) {
+		t.Error("IsHabitDoneOnDate() = true, want false before toggle")
+	}
+
+	// Toggle on
+	store.ToggleHabitToday(habit.ID)
+	hs, _ = store.LoadHabits()
+	if !store.IsHabitDoneOnDate(hs, habit.ID, today) {
+		t.Error("IsHabitDoneOnDate() = false, want true after toggle")
+	}
+}
+
+func TestGetHabitStreak(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+	hs, _ := store.LoadHabits()
+
+	// No streak initially
+	streak := store.GetHabitStreak(hs, habit.ID)
+	if streak != 0 {
+		t.Errorf("GetHabitStreak() = %d, want 0", streak)
+	}
+
+	// Add log for today
+	store.ToggleHabitToday(habit.ID)
+	hs, _ = store.LoadHabits()
+	streak = store.GetHabitStreak(hs, habit.ID)
+	if streak != 1 {
+		t.Errorf("GetHabitStreak() = %d, want 1", streak)
+	}
+}
+
+func TestUpdateHabit(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyCustom, 5, 1, 5)
+	if habit.Frequency != FrequencyCustom || len(habit.CustomDays) != 2 || habit.CustomDays[0] != 1 {
+		t.Fatalf("AddHabit() custom days = %v, want sorted and unique [1 5]", habit.CustomDays)
+	}
+	if _, err := store.AddHabit("Nap", "😴", FrequencyCustom); err == nil {
+		t.Error("AddHabit() should reject a custom frequency without days")
+	}
+	if _, err := store.AddHabit("Nap", "😴", "hourly"); err == nil {
+		t.Error("AddHabit() should reject an unknown frequency")
+	}
+	store.ToggleHabitToday(habit.ID)
+
+	edited := *habit
+	edited.Name = "  Workout "
+	edited.Frequency = FrequencyWeekly
+	if err := store.UpdateHabit(edited); err != nil {
+		t.Fatalf("UpdateHabit() error = %v", err)
+	}
+
+	hs, _ := store.LoadHabits()
+	got := hs.Habits[0]
+	if got.Name != "Workout" || got.Frequency != FrequencyWeekly || got.CustomDays != nil {
+		t.Errorf("UpdateHabit() habit = %+v, want trimmed name, weekly and no custom days", got)
+	}
+	if !got.CreatedAt.Equal(habit.CreatedAt) || len(hs.Logs) != 1 {
+		t.Error("UpdateHabit() should keep the creation time and logs")
+	}
+
+	edited.ID = "h-missing"
+	if err := store.UpdateHabit(edited); err == nil {
+		t.Error("UpdateHabit() should fail for an unknown habit")
+	}
+}
+
+func TestParseHabitSchedule(t *testing.T) {
+	tests := []struct {
+		in   string
+		freq HabitFrequency
+		want string
+	}{
+		{"", FrequencyDaily, "daily"},
+		{"Weekdays", FrequencyWeekdays, "weekdays"},
+		{"weekly", FrequencyWeekly, "weekly"},
+		{"fri, mon tue", FrequencyCustom, "mon,tue,fri"},
+		{"sunday,saturday", FrequencyCustom, "sun,sat"},
+	}
+	for _, tt := range tests {
+		freq, days, err := ParseHabitSchedule(tt.in)
+		if err != nil {
+			t.Errorf("ParseHabitSchedule(%q) error = %v", tt.in, err)
+			continue
+		}
+		got := Habit{Frequency: freq, CustomDays: days}.ScheduleString()
+		if freq != tt.freq || got != tt.want {
+			t.Errorf("ParseHabitSchedule(%q) = %s %q, want %s %q", tt.in, freq, got, tt.freq, tt.want)
+		}
+	}
+	if _, _, err := ParseHabitSchedule("mon,funday"); err == nil {
+		t.Error("ParseHabitSchedule() should reject unknown weekdays")
+	}
+}
+
+func TestGetHabitStreakFollowsFrequency(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Standup", "🗣", FrequencyDaily)
+	hs, _ := store.LoadHabits()
+
+	// Monday 2025-03-03 through Friday 2025-03-14, weekends skipped
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.Local)
+	for _, offset := range []int{0, 1, 2, 3, 4, 7, 8, 9, 10, 11} {
+		hs.Logs = append(hs.Logs, HabitLog{HabitID: habit.ID, Date: monday.AddDate(0, 0, offset).Format("2006-01-02")})
+	}
+	nextMonday := monday.AddDate(0, 0, 14)
+
+	tests := []struct {
+		name       string
+		frequency  HabitFrequency
+		customDays []int
+		want       int
+	}{
+		// A daily habit broke on the weekend
+		{name: "daily", frequency: FrequencyDaily, want: 0},
+		// Weekends are not due, and Monday is not over yet
+		{name: "weekdays", frequency: FrequencyWeekdays, want: 10},
+		// Only Mon/Wed/Fri count
+		{name: "custom", frequency: FrequencyCustom, customDays: []int{1, 3, 5}, want: 6},
+		// Two weeks done, the current one not yet
+		{name: "weekly", frequency: FrequencyWeekly, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs.Habits[0].Frequency = tt.frequency
+			hs.Habits[0].CustomDays = tt.customDays
+			if got := store.GetHabitStreakAt(hs, habit.ID, nextMonday); got != tt.want {
+				t.Errorf("GetHabitStreakAt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// A weekdays habit missed on a weekday breaks the streak
+	hs.Habits[0].Frequency = FrequencyWeekdays
+	hs.Habits[0].CustomDays = nil
+	if got := store.GetHabitStreakAt(hs, habit.ID, nextMonday.AddDate(0, 0, 1)); got != 0 {
+		t.Errorf("GetHabitStreakAt() after a missed Monday = %d, want 0", got)
+	}
+
+	// Weekly progress covers the current week from Sunday
+	store.SetNowFunc(func() time.Time { return nextMonday })
+	hs.Habits[0].Frequency = FrequencyWeekly
+	if done, due := store.GetHabitWeekProgress(hs, habit.ID); done != 0 || due != 1 {
+		t.Errorf("GetHabitWeekProgress() = %d/%d, want 0/1", done, due)
+	}
+	hs.Habits[0].Frequency = FrequencyWeekdays
+	if done, due := store.GetHabitWeekProgress(hs, habit.ID); done != 4 || due != 5 {
+		t.Errorf("GetHabitWeekProgress() = %d/%d, want 4/5", done, due)
+	}
+}
+
+func TestParseHabitTarget(t *testing.T) {
+	tests := []struct {
+		in     string
+		target int
+		unit   string
+	}{
+		{"", 0, ""},
+		{"8 glasses", 8, "glasses"},
+		{"30min", 30, "min"},
+		{" 12 ", 12, ""},
+		{"0 glasses", 0, ""},
+	}
+	for _, tt := range tests {
+		target, unit, err := ParseHabitTarget(tt.in)
+		if err != nil || target != tt.target || unit != tt.unit {
+			t.Errorf("ParseHabitTarget(%q) = %d %q, %v; want %d %q", tt.in, target, unit, err, tt.target, tt.unit)
+		}
+	}
+	for _, in := range []string{"lots", "-3 km", "99999 steps"} {
+		if _, _, err := ParseHabitTarget(in); err == nil {
+			t.Errorf("ParseHabitTarget(%q) should fail", in)
+		}
+	}
+}
+
+func TestHabitTarget(t *testing.T) {
+	store := createTestStorage(t)
+	now := time.Date(2025, 3, 5, 9, 0, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return now })
+
+	habit, err := store.CreateHabit(Habit{Name: "Water", Icon: "🥤", Target: 3, Unit: " glasses "})
+	if err != nil {
+		t.Fatalf("CreateHabit() error = %v", err)
+	}
+	if habit.Target != 3 || habit.Unit != "glasses" {
+		t.Errorf("CreateHabit() = %+v, want a target of 3 glasses", habit)
+	}
+
+	// Done two days ago, a plain check-in yesterday, partial today
+	store.SetHabitValueOnDate(habit.ID, "2025-03-03", 4)
+	store.SetHabitDoneOnDate(habit.ID, "2025-03-04", true)
+	for i := 0; i < 2; i++ {
+		if _, err := store.AdjustHabitToday(habit.ID, 1); err != nil {
+			t.Fatalf("AdjustHabitToday() error = %v", err)
+		}
+	}
+
+	hs, _ := store.LoadHabits()
+	if got := store.HabitValueOnDate(hs, habit.ID, "2025-03-04"); got != 3 {
+		t.Errorf("HabitValueOnDate() after SetHabitDoneOnDate = %d, want the target", got)
+	}
+	if got := store.HabitValueOnDate(hs, habit.ID, "2025-03-05"); got != 2 {
+		t.Errorf("HabitValueOnDate() today = %d, want 2", got)
+	}
+	if store.IsHabitDoneOnDate(hs, habit.ID, "2025-03-05") {
+		t.Error("partial progress should not count as done")
+	}
+	if got := store.GetHabitStreak(hs, habit.ID); got != 2 {
+		t.Errorf("GetHabitStreak() = %d, want 2 (today is not over yet)", got)
+	}
+
+	// Reaching the target completes the day
+	if value, _ := store.AdjustHabitToday(habit.ID, 1); value != 3 {
+		t.Errorf("AdjustHabitToday() = %d, want 3", value)
+	}
+	hs, _ = store.LoadHabits()
+	if got := store.GetHabitStreak(hs, habit.ID); got != 3 {
+		t.Errorf("GetHabitStreak() = %d, want 3", got)
+	}
+
+	// Amounts never drop below zero, and zero removes the log
+	if value, _ := store.AdjustHabitToday(habit.ID, -5); value != 0 {
+		t.Errorf("AdjustHabitToday() = %d, want 0", value)
+	}
+	hs, _ = store.LoadHabits()
+	if len(hs.Logs) != 2 {
+		t.Errorf("logs = %v, want today's removed", hs.Logs)
+	}
+
+	data, err := store.ExportHabitsJSON()
+	if err != nil {
+		t.Fatalf("ExportHabitsJSON() error = %v", err)
+	}
+	for _, want := range []string{`"target": 3`, `"unit": "glasses"`, `"value": 4`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("export is missing %s:\n%s", want, data)
+		}
+	}
+
+	// Habits without a target are only checked or unchecked
+	plain, _ := store.AddHabit("Stretch", "🧘", FrequencyDaily)
+	store.AdjustHabitToday(plain.ID, 1)
+	if value, _ := store.AdjustHabitToday(plain.ID, 1); value != 1 {
+		t.Errorf("AdjustHabitToday() on a yes/no habit = %d, want 1", value)
+	}
+}
+
+func TestHabitSkipsAndVacation(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Run", "🏃", FrequencyDaily)
+	weekly, _ := store.AddHabit("Review", "📝", FrequencyWeekly)
+
+	// Done Monday 2025-03-03 to Friday, sick on Wednesday
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.Local)
+	for _, offset := range []int{0, 1, 3, 4} {
+		store.SetHabitDoneOnDate(habit.ID, monday.AddDate(0, 0, offset).Format("2006-01-02"), true)
+	}
+	if err := store.SetHabitSkippedOnDate(habit.ID, "2025-03-05", true); err != nil {
+		t.Fatalf("SetHabitSkippedOnDate() error = %v", err)
+	}
+	// The review done in the first week, skipped in the second
+	store.SetHabitDoneOnDate(weekly.ID, "2025-03-04", true)
+	store.SetHabitSkippedOnDate(weekly.ID, "2025-03-12", true)
+
+	friday := monday.AddDate(0, 0, 4)
+	hs, _ := store.LoadHabits()
+	if !store.IsHabitSkippedOnDate(hs, habit.ID, "2025-03-05") || store.IsHabitDoneOnDate(hs, habit.ID, "2025-03-05") {
+		t.Error("Wednesday should be skipped and not done")
+	}
+	if got := store.GetHabitStreakAt(hs, habit.ID, friday); got != 4 {
+		t.Errorf("GetHabitStreakAt() = %d, want 4 (the skipped day neither breaks nor counts)", got)
+	}
+	if got := store.GetHabitStreakAt(hs, weekly.ID, monday.AddDate(0, 0, 14)); got != 1 {
+		t.Errorf("weekly GetHabitStreakAt() = %d, want 1 (the skipped week is passed over)", got)
+	}
+
+	// A weekend away: the streak survives until Monday's run is due
+	if err := store.SetVacation(&DateRange{Start: "2025-03-08", End: "2025-03-09"}); err != nil {
+		t.Fatalf("SetVacation() error = %v", err)
+	}
+	hs, _ = store.LoadHabits()
+	if got := store.GetHabitStreakAt(hs, habit.ID, friday.AddDate(0, 0, 3)); got != 4 {
+		t.Errorf("GetHabitStreakAt() after the vacation = %d, want 4", got)
+	}
+	store.SetNowFunc(func() time.Time { return friday.AddDate(0, 0, 2) })
+	if done, due := store.GetHabitWeekProgress(hs, habit.ID); done != 4 || due != 4 {
+		t.Errorf("GetHabitWeekProgress() = %d/%d, want 4/4", done, due)
+	}
+
+	// Skipping today replaces what was logged, and toggles back off
+	store.SetNowFunc(func() time.Time { return friday })
+	if skipped, err := store.ToggleHabitSkipToday(habit.ID); err != nil || !skipped {
+		t.Fatalf("ToggleHabitSkipToday() = %v, %v; want skipped", skipped, err)
+	}
+	hs, _ = store.LoadHabits()
+	if store.IsHabitDoneOnDate(hs, habit.ID, "2025-03-07") {
+		t.Error("skipping should replace the completion")
+	}
+	if skipped, _ := store.ToggleHabitSkipToday(habit.ID); skipped {
+		t.Error("second ToggleHabitSkipToday() should remove the skip")
+	}
+
+	if err := store.SetVacation(&DateRange{Start: "2025-03-09", End: "2025-03-08"}); err == nil {
+		t.Error("SetVacation() should reject a range that ends before it starts")
+	}
+	if err := store.SetVacation(nil); err != nil {
+		t.Fatalf("SetVacation(nil) error = %v", err)
+	}
+	if hs, _ := store.LoadHabits(); hs.Vacation != nil {
+		t.Errorf("vacation = %+v, want none", hs.Vacation)
+	}
+}
+
+func TestHabitPauseAndArchive(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Run", "🏃", FrequencyDaily)
+	other, _ := store.AddHabit("Read", "📚", FrequencyDaily)
+
+	// Done Monday 2025-03-03 and Tuesday, paused on Wednesday
+	monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.Local)
+	store.SetHabitDoneOnDate(habit.ID, "2025-03-03", true)
+	store.SetHabitDoneOnDate(habit.ID, "2025-03-04", true)
+
+	store.SetNowFunc(func() time.Time { return monday.AddDate(0, 0, 2) })
+	paused, err := store.SetHabitState(habit.ID, HabitPaused)
+	if err != nil {
+		t.Fatalf("SetHabitState(paused) error = %v", err)
+	}
+	if paused.State != HabitPaused || paused.StateSince != "2025-03-05" {
+		t.Errorf("paused habit = %q since %q, want paused since 2025-03-05", paused.State, paused.StateSince)
+	}
+	if _, err := store.SetHabitState(habit.ID, HabitPaused); err == nil {
+		t.Error("pausing a paused habit should fail")
+	}
+	if !paused.ActiveOn("2025-03-04") || paused.ActiveOn("2025-03-05") {
+		t.Error("ActiveOn() should be true before the pause and false from it")
+	}
+
+	// Back on Saturday: the paused days neither break nor extend the streak
+	saturday := monday.AddDate(0, 0, 5)
+	store.SetNowFunc(func() time.Time { return saturday })
+	resumed, err := store.SetHabitState(habit.ID, HabitActive)
+	if err != nil {
+		t.Fatalf("SetHabitState(active) error = %v", err)
+	}
+	want := []DateRange{{Start: "2025-03-05", End: "2025-03-07"}}
+	if resumed.State != HabitActive || resumed.StateSince != "" || !reflect.DeepEqual(resumed.Breaks, want) {
+		t.Errorf("resumed habit = %q since %q, breaks %v; want active with breaks %v", resumed.State, resumed.StateSince, resumed.Breaks, want)
+	}
+	store.SetHabitDoneOnDate(habit.ID, "2025-03-08", true)
+	hs, _ := store.LoadHabits()
+	if got := store.GetHabitStreakAt(hs, habit.ID, saturday); got != 3 {
+		t.Errorf("GetHabitStreakAt() = %d, want 3 (the paused days are passed over)", got)
+	}
+
+	// Archiving keeps the logs; undo puts the earlier state back
+	if _, err := store.SetHabitState(other.ID, HabitArchived); err != nil {
+		t.Fatalf("SetHabitState(archived) error = %v", err)
+	}
+	if _, err := store.SetHabitState(habit.ID, HabitArchived); err != nil {
+		t.Fatalf("SetHabitState(archived) error = %v", err)
+	}
+	hs, _ = store.LoadHabits()
+	if len(hs.Habits) != 2 || !store.IsHabitDoneOnDate(hs, habit.ID, "2025-03-08") {
+		t.Error("archiving should keep the habit and its logs")
+	}
+	if err := store.RestoreHabitState(*resumed); err != nil {
+		t.Fatalf("RestoreHabitState() error = %v", err)
+	}
+	hs, _ = store.LoadHabits()
+	if h := findHabit(hs, habit.ID); !h.IsActive() || !reflect.DeepEqual(h.Breaks, want) {
+		t.Errorf("restored habit = %q, breaks %v; want active with breaks %v", h.State, h.Breaks, want)
+	}
+
+	if _, err := store.SetHabitState(habit.ID, "gone"); err == nil {
+		t.Error("SetHabitState() should reject an unknown state")
+	}
+	if _, err := store.SetHabitState("missing", HabitPaused); err == nil {
+		t.Error("SetHabitState() should fail for an unknown habit")
+	}
+}
+
+func TestHabitStateWithoutSince(t *testing.T) {
+	dir := t.TempDir()
+	// Paused by hand after a three day streak, archived without any logs
+	habits := `{"version": 4, "habits": [
+		{"id": "h1", "name": "Run", "icon": "🏃", "state": "paused", "created_at": "2025-03-01T00:00:00Z"},
+		{"id": "h2", "name": "Read", "icon": "📚", "state": "archived", "created_at": "2025-03-01T00:00:00Z"}],
+		"logs": [{"habit_id": "h1", "date": "2025-03-03"}, {"habit_id": "h1", "date": "2025-03-04"}, {"habit_id": "h1", "date": "2025-03-05"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "habits.json"), []byte(habits), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local) })
+
+	hs, err := store.LoadHabits()
+	if err != nil {
+		t.Fatalf("LoadHabits: %v", err)
+	}
+	run, read := findHabit(hs, "h1"), findHabit(hs, "h2")
+	if run.StateSince != "2025-03-06" || read.StateSince != "2025-03-10" {
+		t.Errorf("StateSince = %q and %q, want the day after the last log and today", run.StateSince, read.StateSince)
+	}
+	if !run.ActiveOn("2025-03-05") || run.ActiveOn("2025-03-06") {
+		t.Error("the paused habit should be tracked up to its last log")
+	}
+	if got := store.GetHabitStreakAt(hs, "h1", time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)); got != 3 {
+		t.Errorf("GetHabitStreakAt() = %d, want 3 (history kept, paused days passed over)", got)
+	}
+}
+
+func TestGetHabitWeek(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+
+	// All false initially
+	hs, _ := store.LoadHabits()
+	week := store.GetHabitWeek(hs, habit.ID)
+	if len(week) != 7 {
+		t.Fatalf("len(week) = %d, want 7", len(week))
+	}
+	for i, done := range week {
+		if done {
+			t.Errorf("week[%d] = true, want false", i)
+		}
+	}
+
+	// Toggle today
+	store.ToggleHabitToday(habit.ID)
+	hs, _ = store.LoadHabits()
+	week = store.GetHabitWeek(hs, habit.ID)
+
+	// Last element (today) should be true
+	if !week[6] {
+		t.Error("week[6] (today) = false, want true")
+	}
+}
+
+func TestDeleteHabit(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+	store.ToggleHabitToday(habit.ID) // Add a log
+
+	// Delete
+	if err := store.DeleteHabit(habit.ID); err != nil {
+		t.Fatalf("DeleteHabit() error = %v", err)
+	}
+
+	// Verify habit and logs are removed
+	hs, _ := store.LoadHabits()
+	if len(hs.Habits) != 0 {
+		t.Errorf("len(habits) = %d, want 0", len(hs.Habits))
+	}
+	if len(hs.Logs) != 0 {
+		t.Errorf("len(logs) = %d, want 0", len(hs.Logs))
+	}
+}
+
+func TestDeleteHabit_NotFound(t *testing.T) {
+	store := createTestStorage(t)
+
+	if err := store.DeleteHabit("missing"); err == nil {
+		t.Fatal("DeleteHabit() expected error for missing habit")
+	}
+}
+
+func TestTrash(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Write report", "", PriorityNone, nil)
+	habit, _ := store.AddHabit("Exercise", "🏃", FrequencyDaily)
+	store.ToggleHabitToday(habit.ID)
+
+	deletedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return deletedAt })
+	if err := store.DeleteTask(task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	store.SetNowFunc(func() time.Time { return deletedAt.AddDate(0, 0, 10) })
+	if err := store.DeleteHabit(habit.ID); err != nil {
+		t.Fatalf("DeleteHabit() error = %v", err)
+	}
+
+	trash, err := store.LoadTrash()
+	if err != nil {
+		t.Fatalf("LoadTrash() error = %v", err)
+	}
+	if len(trash.Items) != 2 {
+		t.Fatalf("len(trash) = %d, want 2", len(trash.Items))
+	}
+	if trash.Items[0].ID() != habit.ID || len(trash.Items[0].Logs) != 1 {
+		t.Errorf("trash[0] = %s with %d logs, want the habit (newest first) with its log", trash.Items[0].ID(), len(trash.Items[0].Logs))
+	}
+	if !trash.Items[1].DeletedAt.Equal(deletedAt) {
+		t.Errorf("task DeletedAt = %v, want %v", trash.Items[1].DeletedAt, deletedAt)
+	}
+
+	// Restoring brings back the habit with its history and empties its slot
+	item, err := store.RestoreFromTrash(habit.ID)
+	if err != nil {
+		t.Fatalf("RestoreFromTrash() error = %v", err)
+	}
+	if item.Kind != TrashKindHabit {
+		t.Errorf("restored kind = %s, want habit", item.Kind)
+	}
+	hs, _ := store.LoadHabits()
+	if len(hs.Habits) != 1 || len(hs.Logs) != 1 {
+		t.Errorf("after restore: %d habits, %d logs, want 1 and 1", len(hs.Habits), len(hs.Logs))
+	}
+	if trash, _ = store.LoadTrash(); len(trash.Items) != 1 {
+		t.Errorf("len(trash) after restore = %d, want 1", len(trash.Items))
+	}
+
+	// Expired items are purged; recent ones stay
+	store.SetNowFunc(func() time.Time { return deletedAt.AddDate(0, 0, 31) })
+	if err := store.DeleteHabit(habit.ID); err != nil {
+		t.Fatalf("DeleteHabit() error = %v", err)
+	}
+	n, err := store.PurgeTrash(30)
+	if err != nil {
+		t.Fatalf("PurgeTrash() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("PurgeTrash() = %d, want 1 (the task)", n)
+	}
+	if err := store.PurgeFromTrash(habit.ID); err != nil {
+		t.Fatalf("PurgeFromTrash() error = %v", err)
+	}
+	if trash, _ = store.LoadTrash(); len(trash.Items) != 0 {
+		t.Errorf("len(trash) after purge = %d, want 0", len(trash.Items))
+	}
+	if _, err := store.RestoreFromTrash(task.ID); err == nil {
+		t.Error("RestoreFromTrash() expected error for purged task")
+	}
+}
+
+func TestTrash_UndoDelete(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Write report", "", PriorityNone, nil)
+	store.DeleteTask(task.ID)
+
+	// Undo restores the task directly; it must not linger in the trash
+	if err := store.RestoreTask(*task); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	trash, _ := store.LoadTrash()
+	if len(trash.Items) != 0 {
+		t.Errorf("len(trash) = %d, want 0", len(trash.Items))
+	}
+}
+
+func TestTrash_SaveContextsNameTrash(t *testing.T) {
+	store := createTestStorage(t)
+	var saved []SaveContext
+	store.SetOnSaveWithContext(func(ctx SaveContext) { saved = append(saved, ctx) })
+
+	task, _ := store.AddTask("Write report", "", PriorityNone, nil)
+	habit, _ := store.AddHabit("Read", "📚", FrequencyDaily)
+	saved = nil
+	store.DeleteTask(task.ID)
+	store.RestoreTask(*task)
+	store.DeleteHabit(habit.ID)
+	store.RestoreHabit(*habit, nil)
+
+	// Git sync commits the trash along with the collection it moved items to or from.
+	if len(saved) != 4 {
+		t.Fatalf("got %d save contexts, want 4", len(saved))
+	}
+	for _, ctx := range saved {
+		if !reflect.DeepEqual(ctx.Files, []string{trashFile}) {
+			t.Errorf("%s %s context files = %v, want [%s]", ctx.Operation, ctx.ItemType, ctx.Files, trashFile)
+		}
+	}
+}
+
+// =============================================================================
+// Timer Tests
+// =============================================================================
+
+func TestStartTimer(t *testing.T) {
+	store := createTestStorage(t)
+
+	if err := store.StartTimer("Project A"); err != nil {
+		t.Fatalf("StartTimer() error = %v", err)
+	}
+
+	ts, _ := store.LoadTimer()
+	if ts.Current == nil {
+		t.Fatal("ts.Current is nil")
+	}
+	if ts.Current.Project != "Project A" {
+		t.Errorf("Current.Project = %q, want %q", ts.Current.Project, "Project A")
+	}
+}
+
+func TestStartTimer_Validation(t *testing.T) {
+	store := createTestStorage(t)
+	if err := store.StartTimer("   "); err == nil {
+		t.Fatal("StartTimer() expected error for empty project")
+	}
+}
+
+func TestStartTimer_SwitchProject(t *testing.T) {
+	store := createTestStorage(t)
+
+	// Start first timer
+	store.StartTimer("Project A")
+	time.Sleep(10 * time.Millisecond) // Ensure some time passes
+
+	// Switch to second project
+	if err := store.StartTimer("Project B"); err != nil {
+		t.Fatalf("StartTimer() error = %v", err)
+	}
+
+	ts, _ := store.LoadTimer()
+
+	// Current should be Project B
+	if ts.Current.Project != "Project B" {
+		t.Errorf("Current.Project = %q, want %q", ts.Current.Project, "Project B")
+	}
+
+	// Should have one entry for Project A
+	if len(ts.Entries) != 1 {
+		t.Fatalf("len(Entries) = %d, want 1", len(ts.Entries))
 	}
 	if ts.Entries[0].Project != "Project A" {
 		t.Errorf("Entries[0].Project = %q, want %q", ts.Entries[0].Project, "Project A")
 	}
 }
 
-func TestStopTimer(t *testing.T) {
-	store := createTestStorage(t)
+func TestStopTimer(t *testing.T) {
+	store := createTestStorage(t)
+
+	store.StartTimer("Project A")
+	time.Sleep(10 * time.Millisecond)
+
+	if err := store.StopTimer(); err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+
+	ts, _ := store.LoadTimer()
+
+	// Current should be nil
+	if ts.Current != nil {
+		t.Error("ts.Current should be nil after stop")
+	}
+
+	// Should have one entry
+	if len(ts.Entries) != 1 {
+		t.Fatalf("len(Entries) = %d, want 1", len(ts.Entries))
+	}
+}
+
+func TestStartTaskTimer(t *testing.T) {
+	store := createTestStorage(t)
+
+	report, _ := store.AddTask("Write report", "work", PriorityNone, nil)
+	inbox, _ := store.AddTask("Clear inbox", "", PriorityNone, nil)
+
+	if err := store.StartTaskTimer(report.ID); err != nil {
+		t.Fatalf("StartTaskTimer() error = %v", err)
+	}
+	// Switching to another task closes the first entry with its task ID
+	if err := store.StartTaskTimer(inbox.ID); err != nil {
+		t.Fatalf("StartTaskTimer() error = %v", err)
+	}
+
+	ts, _ := store.LoadTimer()
+	if len(ts.Entries) != 1 || ts.Entries[0].TaskID != report.ID || ts.Entries[0].Project != "work" {
+		t.Errorf("Entries = %+v, want one entry for %s under project work", ts.Entries, report.ID)
+	}
+	if ts.Current == nil || ts.Current.TaskID != inbox.ID || ts.Current.Project != "Clear inbox" {
+		t.Errorf("Current = %+v, want the inbox task filed under its text", ts.Current)
+	}
+
+	store.CompleteTask(report.ID)
+	if err := store.StartTaskTimer(report.ID); err == nil {
+		t.Error("StartTaskTimer() expected error for a done task")
+	}
+	if err := store.StartTaskTimer("missing"); err == nil {
+		t.Error("StartTaskTimer() expected error for a missing task")
+	}
+}
+
+func TestTaskTotals(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local)
+	entries := []TimerEntry{
+		{Project: "work", TaskID: "t_1", StartedAt: start, EndedAt: start.Add(time.Hour)},
+		{Project: "work", TaskID: "t_1", StartedAt: start.Add(2 * time.Hour), EndedAt: start.Add(150 * time.Minute)},
+		{Project: "work", StartedAt: start, EndedAt: start.Add(time.Hour)},
+	}
+
+	totals := TaskTotals(entries)
+	if len(totals) != 1 || totals["t_1"] != 90*time.Minute {
+		t.Errorf("TaskTotals() = %v, want t_1: 1h30m only", totals)
+	}
+}
+
+func TestStopTimer_NoCurrentTimer(t *testing.T) {
+	store := createTestStorage(t)
+
+	// Should not error when no timer is running
+	if err := store.StopTimer(); err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+}
+
+func TestGetTodayTotal(t *testing.T) {
+	store := createTestStorage(t)
+
+	// No entries initially
+	ts, _ := store.LoadTimer()
+	total := store.GetTodayTotal(ts)
+	if total != 0 {
+		t.Errorf("GetTodayTotal() = %v, want 0", total)
+	}
+
+	// Add a completed entry
+	store.StartTimer("Project A")
+	time.Sleep(50 * time.Millisecond)
+	store.StopTimer()
+
+	ts, _ = store.LoadTimer()
+	total = store.GetTodayTotal(ts)
+	if total < 50*time.Millisecond {
+		t.Errorf("GetTodayTotal() = %v, want >= 50ms", total)
+	}
+}
+
+func TestGetWeekTotal(t *testing.T) {
+	store := createTestStorage(t)
+
+	// Similar to GetTodayTotal but for the week
+	store.StartTimer("Project A")
+	time.Sleep(50 * time.Millisecond)
+	store.StopTimer()
+
+	ts, _ := store.LoadTimer()
+	total := store.GetWeekTotal(ts)
+	if total < 50*time.Millisecond {
+		t.Errorf("GetWeekTotal() = %v, want >= 50ms", total)
+	}
+}
+
+func TestTimerTotals_SplitAcrossBoundaries(t *testing.T) {
+	store := createTestStorage(t)
+	loc := time.Local
+
+	// Sunday, so week starts today (Sunday-based).
+	now := time.Date(2025, 12, 14, 10, 0, 0, 0, loc)
+	dayStart := time.Date(2025, 12, 14, 0, 0, 0, 0, loc)
+
+	ts := &TimerStore{
+		Entries: []TimerEntry{
+			// Spans midnight: counts 1h for today/week.
+			{Project: "X", StartedAt: dayStart.Add(-1 * time.Hour), EndedAt: dayStart.Add(1 * time.Hour)},
+			// Fully today: counts 1h.
+			{Project: "Y", StartedAt: dayStart.Add(9 * time.Hour), EndedAt: dayStart.Add(10 * time.Hour)},
+		},
+		Current: &CurrentTimer{
+			Project:   "Z",
+			StartedAt: dayStart.Add(9*time.Hour + 30*time.Minute),
+		},
+	}
+
+	today := store.getTodayTotalAt(ts, now)
+	week := store.getWeekTotalAt(ts, now)
+
+	// Today should include: 1h (midnight overlap) + 1h + 30m (current running from 09:30 -> 10:00)
+	want := 2*time.Hour + 30*time.Minute
+	if today != want {
+		t.Fatalf("getTodayTotalAt() = %v, want %v", today, want)
+	}
+	if week != want {
+		t.Fatalf("getWeekTotalAt() = %v, want %v", week, want)
+	}
+}
+
+// =============================================================================
+// Edge Cases
+// =============================================================================
+
+func TestStorageInitialization(t *testing.T) {
+	store := createTestStorage(t)
+
+	// All stores should be initialized and loadable
+	tasks, err := store.LoadTasks()
+	if err != nil {
+		t.Fatalf("LoadTasks() error = %v", err)
+	}
+	if tasks == nil {
+		t.Error("tasks is nil")
+	}
+
+	habits, err := store.LoadHabits()
+	if err != nil {
+		t.Fatalf("LoadHabits() error = %v", err)
+	}
+	if habits == nil {
+		t.Error("habits is nil")
+	}
+
+	timer, err := store.LoadTimer()
+	if err != nil {
+		t.Fatalf("LoadTimer() error = %v", err)
+	}
+	if timer == nil {
+		t.Error("timer is nil")
+	}
+}
+
+func TestMultipleTasks(t *testing.T) {
+	store := createTestStorage(t)
+
+	// Add multiple tasks
+	for i := 0; i < 10; i++ {
+		_, err := store.AddTask("Task", "", PriorityNone, nil)
+		if err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+
+	loaded, _ := store.LoadTasks()
+	if len(loaded.Tasks) != 10 {
+		t.Errorf("len(tasks) = %d, want 10", len(loaded.Tasks))
+	}
+}
+
+func TestStorage_PermissionsArePrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions are not meaningful on Windows")
+	}
+
+	dataDir := t.TempDir()
+	_, err := New(dataDir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	paths := []string{
+		filepath.Join(dataDir, "tasks.json"),
+		filepath.Join(dataDir, "habits.json"),
+		filepath.Join(dataDir, "timer.json"),
+	}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("Stat(%s) error = %v", p, err)
+		}
+		if info.Mode().Perm()&0o077 != 0 {
+			t.Fatalf("%s permissions = %o, want no group/other bits", p, info.Mode().Perm())
+		}
+	}
+}
+
+func TestStaleWriteDetected(t *testing.T) {
+	dir := t.TempDir()
+	first, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	second, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := first.AddTask("one", "", PriorityNone, nil); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+
+	stale, err := first.LoadTasks()
+	if err != nil {
+		t.Fatalf("LoadTasks: %v", err)
+	}
+	if _, err := second.AddTask("two", "", PriorityNone, nil); err != nil {
+		t.Fatalf("AddTask from second instance: %v", err)
+	}
+
+	stale.Tasks = append(stale.Tasks, Task{ID: "lost", Text: "lost"})
+	if err := first.SaveTasks(stale); !errors.Is(err, ErrStaleWrite) {
+		t.Fatalf("SaveTasks on stale data: got %v, want ErrStaleWrite", err)
+	}
+
+	// Mutators reload before saving, so both instances' edits survive.
+	if _, err := first.AddTask("three", "", PriorityNone, nil); err != nil {
+		t.Fatalf("AddTask after concurrent edit: %v", err)
+	}
+	store, err := second.LoadTasks()
+	if err != nil {
+		t.Fatalf("LoadTasks: %v", err)
+	}
+	if len(store.Tasks) != 3 {
+		t.Errorf("expected 3 tasks, got %d", len(store.Tasks))
+	}
+	if store.Revision != stale.Revision+2 {
+		t.Errorf("expected revision %d, got %d", stale.Revision+2, store.Revision)
+	}
+}
+
+func TestUpdateRetriesStaleWrite(t *testing.T) {
+	store := createTestStorage(t)
+
+	attempts := 0
+	err := store.update(func() error {
+		attempts++
+		if attempts == 1 {
+			return ErrStaleWrite
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	err = store.update(func() error {
+		attempts++
+		return ErrStaleWrite
+	})
+	if !errors.Is(err, ErrStaleWrite) {
+		t.Errorf("expected ErrStaleWrite after giving up, got %v", err)
+	}
+	if attempts != maxWriteAttempts {
+		t.Errorf("expected %d attempts, got %d", maxWriteAttempts, attempts)
+	}
+
+	// A cycle that already wrote some of its files is not run again.
+	attempts = 0
+	err = store.update(func() error {
+		attempts++
+		return &partialWriteError{written: []string{"tasks.json"}, err: ErrStaleWrite}
+	})
+	if !errors.Is(err, ErrStaleWrite) || attempts != 1 {
+		t.Errorf("partial write: %d attempts, %v; want 1 attempt and ErrStaleWrite", attempts, err)
+	}
+}
+
+func TestStaleCycleWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := 0
+	err = store.update(func() error {
+		attempts++
+		if _, err := store.createTask(Task{Text: "Once"}); err != nil {
+			return err
+		}
+		if _, err := store.createHabit(Habit{Name: "Read", Icon: "📚"}); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// A writer that does not take the lock moves habits.json on
+			// before the cycle's files are written.
+			habits, err := other.LoadHabits()
+			if err != nil {
+				return err
+			}
+			if err := other.SaveHabits(habits); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	tasks, err := other.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks.Tasks) != 1 {
+		t.Errorf("got %d tasks, want the retried cycle's change once", len(tasks.Tasks))
+	}
+}
+
+func TestUnwrittenSavesOnlySeenByCycle(t *testing.T) {
+	store := createTestStorage(t)
+	err := store.update(func() error {
+		if _, err := store.createTask(Task{Text: "Pending"}); err != nil {
+			return err
+		}
+		working, err := store.loadTasks()
+		if err != nil {
+			return err
+		}
+		if len(working.Tasks) != 1 {
+			t.Errorf("cycle sees %d tasks, want its own save", len(working.Tasks))
+		}
+		// Loads from outside the cycle get what is on disk.
+		committed, err := store.LoadTasks()
+		if err != nil {
+			return err
+		}
+		if len(committed.Tasks) != 0 {
+			t.Errorf("LoadTasks during the cycle returned %d unwritten tasks", len(committed.Tasks))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	tasks, err := store.LoadTasks()
+	if err != nil || len(tasks.Tasks) != 1 {
+		t.Errorf("LoadTasks after the cycle = %v, %v; want the task", tasks, err)
+	}
+}
+
+func TestStaleLockIsBroken(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// A lock left behind by a crashed process must not block writes forever.
+	path := filepath.Join(dir, lockDir, writeLockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("99999\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.AddTask("after crash", "", PriorityNone, nil); err != nil {
+		t.Fatalf("AddTask with stale lock: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected lock file to be released, got %v", err)
+	}
+}
+
+func TestLockLeavesOthersLocks(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	path := filepath.Join(dir, lockDir, writeLockFile)
+
+	// A lock held past staleLockAge is broken and taken by another process;
+	// unlocking afterwards must not remove the new owner's lock.
+	unlock, err := store.lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("99999 other\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if data, err := os.ReadFile(path); err != nil || string(data) != "99999 other\n" {
+		t.Errorf("unlock removed another process's lock: %q, %v", data, err)
+	}
+	if got := lockOwner(path); got != "99999" {
+		t.Errorf("lockOwner = %q, want 99999", got)
+	}
+
+	// A fresh lock is never broken.
+	if breakStaleLock(path) {
+		t.Error("breakStaleLock removed a fresh lock")
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if !breakStaleLock(path) {
+		t.Error("breakStaleLock kept a stale lock")
+	}
+}
+
+func TestStaleWriteSameRevision(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := store.AddTask("Mine", "", PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A git pull brings in a copy edited elsewhere at the same revision.
+	path := filepath.Join(dir, "tasks.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled := bytes.Replace(data, []byte(`"Mine"`), []byte(`"Theirs"`), 1)
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, pulled, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded.Tasks = append(loaded.Tasks, Task{ID: "lost", Text: "lost"})
+	if err := store.SaveTasks(loaded); !errors.Is(err, ErrStaleWrite) {
+		t.Fatalf("SaveTasks over a replaced file: got %v, want ErrStaleWrite", err)
+	}
+	// Mutators reload, so the pulled edit survives.
+	if _, err := store.AddTask("Next", "", PriorityNone, nil); err != nil {
+		t.Fatalf("AddTask: %v", err)
+	}
+	tasks, err := store.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks.Tasks) != 2 || tasks.Tasks[0].Text != "Theirs" {
+		t.Errorf("tasks = %+v, want the pulled task and the new one", tasks.Tasks)
+	}
+}
+
+func TestRegisterInstance(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	others, err := store.RegisterInstance()
+	if err != nil {
+		t.Fatalf("RegisterInstance: %v", err)
+	}
+	if len(others) != 0 {
+		t.Fatalf("expected no other instances, got %v", others)
+	}
+
+	locks := filepath.Join(dir, lockDir)
+	live := filepath.Join(locks, "instance-otherhost-1234.json")
+	crashed := filepath.Join(locks, "instance-otherhost-5678.json")
+	for _, path := range []string{live, crashed} {
+		if err := os.WriteFile(path, []byte(`{"pid":1234,"host":"otherhost"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleInstanceAge)
+	if err := os.Chtimes(crashed, old, old); err != nil {
+		t.Fatal(err)
+	}
 
-	store.StartTimer("Project A")
-	time.Sleep(10 * time.Millisecond)
+	others, err = store.RegisterInstance()
+	if err != nil {
+		t.Fatalf("RegisterInstance: %v", err)
+	}
+	if len(others) != 1 || others[0].PID != 1234 || others[0].Host != "otherhost" {
+		t.Errorf("expected only the live instance, got %v", others)
+	}
+	if _, err := os.Stat(crashed); !os.IsNotExist(err) {
+		t.Errorf("expected stale instance record to be removed")
+	}
 
-	if err := store.StopTimer(); err != nil {
-		t.Fatalf("StopTimer() error = %v", err)
+	if err := store.UnregisterInstance(); err != nil {
+		t.Fatalf("UnregisterInstance: %v", err)
+	}
+	entries, err := os.ReadDir(locks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the other instance's record to remain, got %d entries", len(entries))
 	}
+}
 
-	ts, _ := store.LoadTimer()
+func TestSchemaMigration(t *testing.T) {
+	dir := t.TempDir()
+	legacyHabits := `{"habits":[{"id":"h1","name":"Read","icon":"📚","created_at":"2025-01-01T00:00:00Z"}],"logs":[]}`
+	legacyTrash := `{"items":[{"kind":"habit","habit":{"id":"h2","name":"Run","icon":"🏃","created_at":"2025-01-01T00:00:00Z"},"deleted_at":"2025-02-01T00:00:00Z"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "habits.json"), []byte(legacyHabits), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "trash.json"), []byte(legacyTrash), 0600); err != nil {
+		t.Fatal(err)
+	}
 
-	// Current should be nil
-	if ts.Current != nil {
-		t.Error("ts.Current should be nil after stop")
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
 
-	// Should have one entry
-	if len(ts.Entries) != 1 {
-		t.Fatalf("len(Entries) = %d, want 1", len(ts.Entries))
+	habits, err := store.LoadHabits()
+	if err != nil {
+		t.Fatalf("LoadHabits: %v", err)
+	}
+	if habits.Version != SchemaVersion {
+		t.Errorf("habits version = %d, want %d", habits.Version, SchemaVersion)
+	}
+	if len(habits.Habits) != 1 || habits.Habits[0].Frequency != FrequencyDaily {
+		t.Errorf("expected migrated habit with daily frequency, got %+v", habits.Habits)
+	}
+	trash, err := store.LoadTrash()
+	if err != nil {
+		t.Fatalf("LoadTrash: %v", err)
+	}
+	if len(trash.Items) != 1 || trash.Items[0].Habit.Frequency != FrequencyDaily {
+		t.Errorf("expected migrated habit in trash, got %+v", trash.Items)
 	}
-}
 
-func TestStopTimer_NoCurrentTimer(t *testing.T) {
-	store := createTestStorage(t)
+	// The file is upgraded on disk and the original kept alongside it.
+	data, err := os.ReadFile(filepath.Join(dir, "habits.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf(`"version": %d`, SchemaVersion)) {
+		t.Errorf("habits.json was not rewritten at the current version:\n%s", data)
+	}
+	backup, err := os.ReadFile(filepath.Join(dir, "habits.json.v0.bak"))
+	if err != nil {
+		t.Fatalf("expected a pre-migration copy: %v", err)
+	}
+	if string(backup) != legacyHabits {
+		t.Errorf("pre-migration copy = %s, want the original file", backup)
+	}
 
-	// Should not error when no timer is running
-	if err := store.StopTimer(); err != nil {
-		t.Fatalf("StopTimer() error = %v", err)
+	// Current files are left alone.
+	if _, err := os.Stat(filepath.Join(dir, "tasks.json.v0.bak")); !os.IsNotExist(err) {
+		t.Errorf("tasks.json was created at the current version and should not be migrated")
 	}
 }
 
-func TestGetTodayTotal(t *testing.T) {
-	store := createTestStorage(t)
+func TestNewerSchemaRefused(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
 
-	// No entries initially
-	ts, _ := store.LoadTimer()
-	total := store.GetTodayTotal(ts)
-	if total != 0 {
-		t.Errorf("GetTodayTotal() = %v, want 0", total)
+	future := `{"version":99,"tasks":[{"id":"t1","text":"From the future","done":false,"created_at":"2030-01-01T00:00:00Z"}]}`
+	path := filepath.Join(dir, "tasks.json")
+	if err := os.WriteFile(path, []byte(future), 0600); err != nil {
+		t.Fatal(err)
 	}
 
-	// Add a completed entry
-	store.StartTimer("Project A")
-	time.Sleep(50 * time.Millisecond)
-	store.StopTimer()
+	if _, err := New(dir); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("New: got %v, want ErrNewerSchema", err)
+	}
+	if _, err := store.LoadTasks(); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("LoadTasks: got %v, want ErrNewerSchema", err)
+	}
+	if _, err := store.AddTask("overwrite", "", PriorityNone, nil); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("AddTask: got %v, want ErrNewerSchema", err)
+	}
+	if err := store.SaveTasks(&TaskStore{Tasks: []Task{}}); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("SaveTasks: got %v, want ErrNewerSchema", err)
+	}
 
-	ts, _ = store.LoadTimer()
-	total = store.GetTodayTotal(ts)
-	if total < 50*time.Millisecond {
-		t.Errorf("GetTodayTotal() = %v, want >= 50ms", total)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != future {
+		t.Errorf("tasks.json from a newer version was modified:\n%s", data)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "tasks.json.corrupt.*"))
+	if len(matches) != 0 {
+		t.Errorf("a newer file must not be treated as corrupt, got %v", matches)
 	}
 }
 
-func TestGetWeekTotal(t *testing.T) {
-	store := createTestStorage(t)
+func TestActivityJournal(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	store.SetNowFunc(func() time.Time { return now })
 
-	// Similar to GetTodayTotal but for the week
-	store.StartTimer("Project A")
-	time.Sleep(50 * time.Millisecond)
+	task, _ := store.AddTask("Write report", "", PriorityNone, nil)
+	store.CompleteTask(task.ID)
+	habit, _ := store.AddHabit("Read", "📚", FrequencyDaily)
+	store.ToggleHabitToday(habit.ID)
+	store.StartTimer("writing")
 	store.StopTimer()
+	now = now.Add(time.Hour)
+	store.DeleteTask(task.ID)
 
-	ts, _ := store.LoadTimer()
-	total := store.GetWeekTotal(ts)
-	if total < 50*time.Millisecond {
-		t.Errorf("GetWeekTotal() = %v, want >= 50ms", total)
+	// A line cut short by a crash is skipped, not fatal.
+	f, err := os.OpenFile(filepath.Join(dir, activityFile), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2025-03-01T10:00:00Z","op":"add","ty` + "\n")
+	f.Close()
+
+	entries, err := store.LoadActivity(time.Time{})
+	if err != nil {
+		t.Fatalf("LoadActivity: %v", err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Summary())
+	}
+	want := []string{"added task", "completed task", "added habit", "checked habit", "started timer", "stopped timer", "deleted task"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("journal = %v, want %v", got, want)
+	}
+
+	completed := entries[1]
+	if completed.ItemID != task.ID || completed.ItemName != "Write report" {
+		t.Errorf("complete entry = %+v, want the task's ID and name", completed)
+	}
+	var before, after Task
+	if err := json.Unmarshal(completed.Before, &before); err != nil || before.Done {
+		t.Errorf("complete entry before = %s, want the pending task", completed.Before)
+	}
+	if err := json.Unmarshal(completed.After, &after); err != nil || !after.Done || after.CompletedAt == nil {
+		t.Errorf("complete entry after = %s, want the done task", completed.After)
+	}
+	if deleted := entries[6].Task(); deleted == nil || deleted.ID != task.ID || !deleted.Done {
+		t.Errorf("delete entry task = %+v, want the deleted task", deleted)
+	}
+
+	recent, err := store.LoadActivity(now)
+	if err != nil {
+		t.Fatalf("LoadActivity: %v", err)
+	}
+	if len(recent) != 1 || recent[0].Operation != "delete" {
+		t.Errorf("LoadActivity(since) = %+v, want only the delete", recent)
 	}
 }
 
-func TestTimerTotals_SplitAcrossBoundaries(t *testing.T) {
-	store := createTestStorage(t)
-	loc := time.Local
+func TestPruneActivity(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	now := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	store.SetNowFunc(func() time.Time { return now })
 
-	// Sunday, so week starts today (Sunday-based).
-	now := time.Date(2025, 12, 14, 10, 0, 0, 0, loc)
-	dayStart := time.Date(2025, 12, 14, 0, 0, 0, 0, loc)
+	store.AddTask("Book flights", "", PriorityNone, nil)
+	now = now.AddDate(0, 6, 0)
+	store.AddTask("Pack bags", "", PriorityNone, nil)
+	now = now.AddDate(0, 6, 0)
 
-	ts := &TimerStore{
-		Entries: []TimerEntry{
-			// Spans midnight: counts 1h for today/week.
-			{Project: "X", StartedAt: dayStart.Add(-1 * time.Hour), EndedAt: dayStart.Add(1 * time.Hour)},
-			// Fully today: counts 1h.
-			{Project: "Y", StartedAt: dayStart.Add(9 * time.Hour), EndedAt: dayStart.Add(10 * time.Hour)},
-		},
-		Current: &CurrentTimer{
-			Project:   "Z",
-			StartedAt: dayStart.Add(9*time.Hour + 30*time.Minute),
-		},
+	if n, err := store.PruneActivity(0); err != nil || n != 0 {
+		t.Fatalf("PruneActivity(0) = %d, %v; want the journal kept", n, err)
+	}
+	if _, err := store.PruneActivity(-1); err == nil {
+		t.Error("PruneActivity(-1) should fail")
+	}
+	n, err := store.PruneActivity(200)
+	if err != nil {
+		t.Fatalf("PruneActivity: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("PruneActivity dropped %d entries, want 1", n)
+	}
+	entries, _ := store.LoadActivity(time.Time{})
+	if len(entries) != 1 || entries[0].ItemName != "Pack bags" {
+		t.Errorf("journal after pruning = %+v, want only the recent entry", entries)
 	}
 
-	today := store.getTodayTotalAt(ts, now)
-	week := store.getWeekTotalAt(ts, now)
+	// Appending continues after the rewrite.
+	store.AddTask("Water plants", "", PriorityNone, nil)
+	if entries, _ := store.LoadActivity(time.Time{}); len(entries) != 2 {
+		t.Errorf("journal has %d entries after another change, want 2", len(entries))
+	}
+}
 
-	// Today should include: 1h (midnight overlap) + 1h + 30m (current running from 09:30 -> 10:00)
-	want := 2*time.Hour + 30*time.Minute
-	if today != want {
-		t.Fatalf("getTodayTotalAt() = %v, want %v", today, want)
+func TestActivityWriteErrorReported(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
-	if week != want {
-		t.Fatalf("getWeekTotalAt() = %v, want %v", week, want)
+	// A directory in the journal's place makes every append fail.
+	if err := os.Mkdir(filepath.Join(dir, activityFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	var reported []error
+	store.SetOnActivityError(func(err error) { reported = append(reported, err) })
+
+	if _, err := store.AddTask("Call the plumber", "", PriorityNone, nil); err != nil {
+		t.Fatalf("AddTask should not fail on a journal error: %v", err)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), activityFile) {
+		t.Errorf("reported errors = %v, want one about %s", reported, activityFile)
 	}
 }
 
-// =============================================================================
-// Edge Cases
-// =============================================================================
+func TestExternalChanges(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	other, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
 
-func TestStorageInitialization(t *testing.T) {
+	if changed := store.ExternalChanges(); len(changed) != 0 {
+		t.Fatalf("first call should only record state, got %v", changed)
+	}
+
+	// Own writes are not reported.
+	if _, err := store.AddTask("Mine", "", PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	if changed := store.ExternalChanges(); len(changed) != 0 {
+		t.Errorf("own write reported as external: %v", changed)
+	}
+
+	if _, err := other.AddHabit("Theirs", "🌱", FrequencyDaily); err != nil {
+		t.Fatal(err)
+	}
+	if changed := store.ExternalChanges(); len(changed) != 1 || changed[0] != "habits.json" {
+		t.Errorf("ExternalChanges = %v, want [habits.json]", changed)
+	}
+	if changed := store.ExternalChanges(); len(changed) != 0 {
+		t.Errorf("change reported twice: %v", changed)
+	}
+}
+
+func TestCachedLoadsAreCopies(t *testing.T) {
 	store := createTestStorage(t)
+	task, err := store.AddTask("Original", "", PriorityNone, nil, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddChecklistItem(task.ID, "Step"); err != nil {
+		t.Fatal(err)
+	}
 
-	// All stores should be initialized and loadable
-	tasks, err := store.LoadTasks()
+	loaded, err := store.LoadTasks()
 	if err != nil {
-		t.Fatalf("LoadTasks() error = %v", err)
+		t.Fatal(err)
 	}
-	if tasks == nil {
-		t.Error("tasks is nil")
+	loaded.Tasks[0].Text = "Changed"
+	loaded.Tasks[0].Tags[0] = "home"
+	loaded.Tasks[0].Checklist[0].Done = true
+
+	again, err := store.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := again.Tasks[0]
+	if got.Text != "Original" || got.Tags[0] != "work" || got.Checklist[0].Done {
+		t.Errorf("changing a loaded store leaked into the cache: %+v", got)
 	}
+}
 
-	habits, err := store.LoadHabits()
+func TestCacheSeesOtherWriters(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
 	if err != nil {
-		t.Fatalf("LoadHabits() error = %v", err)
+		t.Fatalf("New: %v", err)
 	}
-	if habits == nil {
-		t.Error("habits is nil")
+	other, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
 
-	timer, err := store.LoadTimer()
+	if _, err := store.AddTask("Mine", "", PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadTasks(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.AddTask("Theirs", "", PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := store.LoadTasks()
 	if err != nil {
-		t.Fatalf("LoadTimer() error = %v", err)
+		t.Fatal(err)
 	}
-	if timer == nil {
-		t.Error("timer is nil")
+	if len(tasks.Tasks) != 2 {
+		t.Fatalf("got %d tasks after another instance added one, want 2", len(tasks.Tasks))
+	}
+
+	// A write in place, as an editor would make it.
+	data, err := os.ReadFile(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(`"Mine"`), []byte(`"Edited by hand"`), 1)
+	if err := os.WriteFile(filepath.Join(dir, "tasks.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = store.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tasks.Tasks[0].Text != "Edited by hand" && tasks.Tasks[1].Text != "Edited by hand" {
+		t.Errorf("edit on disk not picked up: %+v", tasks.Tasks)
 	}
 }
 
-func TestMultipleTasks(t *testing.T) {
+func TestUpdateCoalescesWrites(t *testing.T) {
 	store := createTestStorage(t)
-
-	// Add multiple tasks
-	for i := 0; i < 10; i++ {
-		_, err := store.AddTask("Task", "", PriorityNone, nil)
-		if err != nil {
-			t.Fatalf("AddTask() error = %v", err)
+	var writes []string
+	store.SetOnSave(func(filename string) { writes = append(writes, filename) })
+	var notes []SaveContext
+	store.SetOnSaveWithContext(func(ctx SaveContext) { notes = append(notes, ctx) })
+
+	err := store.update(func() error {
+		for _, text := range []string{"One", "Two", "Three"} {
+			if _, err := store.createTask(Task{Text: text}); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if len(writes) != 1 || writes[0] != "tasks.json" {
+		t.Errorf("writes = %v, want one write of tasks.json", writes)
+	}
+	if len(notes) != 3 {
+		t.Errorf("got %d notifications, want 3", len(notes))
+	}
+	tasks, _ := store.LoadTasks()
+	if len(tasks.Tasks) != 3 {
+		t.Errorf("got %d tasks, want 3", len(tasks.Tasks))
 	}
 
-	loaded, _ := store.LoadTasks()
-	if len(loaded.Tasks) != 10 {
-		t.Errorf("len(tasks) = %d, want 10", len(loaded.Tasks))
+	// A cycle that fails writes and announces nothing.
+	writes, notes = nil, nil
+	err = store.update(func() error {
+		if _, err := store.createTask(Task{Text: "Lost"}); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("update should return the cycle's error")
+	}
+	if len(writes) != 0 || len(notes) != 0 {
+		t.Errorf("failed cycle wrote %v and notified %d times", writes, len(notes))
+	}
+	tasks, _ = store.LoadTasks()
+	if len(tasks.Tasks) != 3 {
+		t.Errorf("failed cycle left %d tasks, want 3", len(tasks.Tasks))
 	}
 }
 
-func TestStorage_PermissionsArePrivate(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skip("POSIX permissions are not meaningful on Windows")
+func TestEncryptedStorage(t *testing.T) {
+	dir := t.TempDir()
+	store, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := store.AddTask("Call Acme Corp", "clients", PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := crypt.Create(dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Rekey(key); err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+	if n, err := store.Rekey(key); err != nil || n != 0 {
+		t.Errorf("repeated Rekey = %d, %v; want nothing rewritten", n, err)
+	}
+	// Later saves, their .bak copies and the journal are encrypted too.
+	if _, err := store.AddTask("Invoice Acme Corp", "clients", PriorityNone, nil); err != nil {
+		t.Fatal(err)
+	}
+	plaintextFiles := func() []string {
+		var found []string
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				if data, _ := os.ReadFile(path); bytes.Contains(data, []byte("Acme")) {
+					found = append(found, path)
+				}
+			}
+			return nil
+		})
+		return found
+	}
+	if found := plaintextFiles(); len(found) > 0 {
+		t.Errorf("plaintext left in %v", found)
+	}
+
+	if _, err := New(dir); !errors.Is(err, crypt.ErrLocked) {
+		t.Errorf("New on encrypted data = %v, want ErrLocked", err)
+	}
+	unlocked, err := crypt.Unlock(dir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewWithKey(dir, unlocked)
+	if err != nil {
+		t.Fatalf("NewWithKey: %v", err)
+	}
+	tasks, err := reopened.LoadTasks()
+	if err != nil || len(tasks.Tasks) != 2 {
+		t.Fatalf("LoadTasks = %d tasks, %v; want 2", len(tasks.Tasks), err)
+	}
+	entries, err := reopened.LoadActivity(time.Time{})
+	if err != nil || len(entries) != 2 {
+		t.Errorf("LoadActivity = %d entries, %v; want 2", len(entries), err)
+	}
+
+	if _, err := reopened.Rekey(nil); err != nil {
+		t.Fatalf("Rekey(nil): %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "tasks.json"))
+	if err != nil || !bytes.Contains(data, []byte("Invoice Acme Corp")) {
+		t.Errorf("tasks.json not decrypted: %.60q", data)
 	}
+}
 
+func TestScratchDir(t *testing.T) {
 	dataDir := t.TempDir()
-	_, err := New(dataDir)
+	store, err := New(dataDir)
 	if err != nil {
-		t.Fatalf("New() error = %v", err)
+		t.Fatal(err)
+	}
+	if dir, err := store.ScratchDir(); err != nil || dir != "" {
+		t.Errorf("ScratchDir without encryption = %q, %v; want the system temp dir", dir, err)
 	}
 
-	paths := []string{
-		filepath.Join(dataDir, "tasks.json"),
-		filepath.Join(dataDir, "habits.json"),
-		filepath.Join(dataDir, "timer.json"),
+	key, err := crypt.Create(dataDir, "passphrase")
+	if err != nil {
+		t.Fatal(err)
 	}
-	for _, p := range paths {
-		info, err := os.Stat(p)
-		if err != nil {
-			t.Fatalf("Stat(%s) error = %v", p, err)
-		}
-		if info.Mode().Perm()&0o077 != 0 {
-			t.Fatalf("%s permissions = %o, want no group/other bits", p, info.Mode().Perm())
-		}
+	if _, err := store.Rekey(key); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := store.ScratchDir()
+	if err != nil {
+		t.Fatalf("ScratchDir: %v", err)
+	}
+	if filepath.Dir(dir) != dataDir {
+		t.Errorf("ScratchDir = %q, want a directory in %q", dir, dataDir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("scratch dir mode = %v, want 0700", info.Mode().Perm())
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, ".gitignore")); err != nil || string(data) != "*\n" {
+		t.Errorf("scratch .gitignore = %q, %v; want everything ignored", data, err)
 	}
 }
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// trashFile holds deleted tasks and habits until they are restored or purged.
+const trashFile = "trash.json"
+
+// ID returns the ID of the deleted task or habit.
+func (item TrashItem) ID() string {
+	switch {
+	case item.Task != nil:
+		return item.Task.ID
+	case item.Habit != nil:
+		return item.Habit.ID
+	}
+	return ""
+}
+
+// Name returns the text of the deleted task or the name of the deleted habit.
+func (item TrashItem) Name() string {
+	switch {
+	case item.Task != nil:
+		return item.Task.Text
+	case item.Habit != nil:
+		return item.Habit.Name
+	}
+	return ""
+}
+
+// LoadTrash reads the trash from disk, most recently deleted first.
+func (s *Storage) LoadTrash() (*TrashStore, error) {
+	return s.readTrash(false)
+}
+
+// loadTrash is LoadTrash for the body of an update cycle.
+func (s *Storage) loadTrash() (*TrashStore, error) {
+	return s.readTrash(true)
+}
+
+func (s *Storage) readTrash(working bool) (*TrashStore, error) {
+	store := TrashStore{Version: SchemaVersion, Items: []TrashItem{}}
+	err := s.loadCollection(trashFile, &store, working)
+	sort.SliceStable(store.Items, func(i, j int) bool {
+		return store.Items[i].DeletedAt.After(store.Items[j].DeletedAt)
+	})
+	return &store, err
+}
+
+// SaveTrash writes the trash to disk
+func (s *Storage) SaveTrash(store *TrashStore) error {
+	store.Version = SchemaVersion
+	return s.saveCollection(trashFile, store)
+}
+
+// addToTrash stores a deleted item, replacing an older copy with the same ID.
+func (s *Storage) addToTrash(item TrashItem) error {
+	store, err := s.loadTrash()
+	if err != nil {
+		return err
+	}
+	items := store.Items[:0]
+	for _, existing := range store.Items {
+		if existing.ID() != item.ID() {
+			items = append(items, existing)
+		}
+	}
+	store.Items = append(items, item)
+	return s.SaveTrash(store)
+}
+
+// removeFromTrash drops the item with the given ID and returns it, or nil if
+// it was not in the trash.
+func (s *Storage) removeFromTrash(id string) (*TrashItem, error) {
+	store, err := s.loadTrash()
+	if err != nil {
+		return nil, err
+	}
+	for i := range store.Items {
+		if store.Items[i].ID() == id {
+			item := store.Items[i]
+			store.Items = append(store.Items[:i], store.Items[i+1:]...)
+			if err := s.SaveTrash(store); err != nil {
+				return nil, err
+			}
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// RestoreFromTrash puts a deleted task or habit (with its logs) back and
+// removes it from the trash.
+func (s *Storage) RestoreFromTrash(id string) (*TrashItem, error) {
+	var item *TrashItem
+	err := s.update(func() (err error) {
+		item, err = s.restoreFromTrash(id)
+		return err
+	})
+	return item, err
+}
+
+func (s *Storage) restoreFromTrash(id string) (*TrashItem, error) {
+	store, err := s.loadTrash()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range store.Items {
+		if item.ID() != id {
+			continue
+		}
+		// RestoreTask and RestoreHabit also clear the trash entry.
+		switch {
+		case item.Task != nil:
+			err = s.restoreTask(*item.Task)
+		case item.Habit != nil:
+			err = s.restoreHabit(*item.Habit, item.Logs)
+		default:
+			err = fmt.Errorf("trash item is empty: %s", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &item, nil
+	}
+	return nil, fmt.Errorf("trash item not found: %s", id)
+}
+
+// PurgeFromTrash permanently deletes one item from the trash.
+func (s *Storage) PurgeFromTrash(id string) error {
+	return s.update(func() error { return s.purgeFromTrash(id) })
+}
+
+func (s *Storage) purgeFromTrash(id string) error {
+	item, err := s.removeFromTrash(id)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return fmt.Errorf("trash item not found: %s", id)
+	}
+	// Notify with semantic context for git commit
+	s.notifySaveWithContext(SaveContext{
+		Filename:  trashFile,
+		Operation: "purge",
+		ItemType:  string(item.Kind),
+		ItemName:  truncateForCommit(item.Name(), 50),
+	})
+	return nil
+}
+
+// PurgeTrash permanently deletes items that have been in the trash for more
+// than retentionDays days. It returns how many items were purged.
+func (s *Storage) PurgeTrash(retentionDays int) (int, error) {
+	var count int
+	err := s.update(func() (err error) {
+		count, err = s.purgeTrash(retentionDays)
+		return err
+	})
+	return count, err
+}
+
+func (s *Storage) purgeTrash(retentionDays int) (int, error) {
+	if retentionDays < 0 {
+		return 0, fmt.Errorf("trash retention must be zero or more days")
+	}
+	cutoff := s.Now().AddDate(0, 0, -retentionDays)
+
+	store, err := s.loadTrash()
+	if err != nil {
+		return 0, err
+	}
+	keep := make([]TrashItem, 0, len(store.Items))
+	for _, item := range store.Items {
+		if item.DeletedAt.After(cutoff) {
+			keep = append(keep, item)
+		}
+	}
+	purged := len(store.Items) - len(keep)
+	if purged == 0 {
+		return 0, nil
+	}
+
+	store.Items = keep
+	if err := s.SaveTrash(store); err != nil {
+		return 0, err
+	}
+	// Notify with semantic context for git commit
+	s.notifySaveWithContext(SaveContext{
+		Filename:  trashFile,
+		Operation: "purge",
+		ItemType:  "trash",
+		ItemName:  fmt.Sprintf("%d expired", purged),
+	})
+	return purged, nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"os"
+	"time"
+)
+
+// watchedFiles are the data files ExternalChanges looks at.
+var watchedFiles = []string{"tasks.json", "habits.json", "timer.json", trashFile}
+
+// fileStamp identifies one version of a file on disk. Atomic writes replace
+// the file, so they are told apart by identity even within the resolution
+// of the modification time.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	info    os.FileInfo
+}
+
+func (f fileStamp) equal(other fileStamp) bool {
+	if f.info == nil || other.info == nil {
+		return f.info == nil && other.info == nil
+	}
+	return f.size == other.size && f.modTime.Equal(other.modTime) && os.SameFile(f.info, other.info)
+}
+
+func (s *Storage) statStamp(filename string) (fileStamp, bool) {
+	info, err := os.Stat(s.path(filename))
+	if err != nil {
+		return fileStamp{}, false
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size(), info: info}, true
+}
+
+// recordStamp remembers a file as this Storage last wrote it, so the write
+// is not reported by ExternalChanges.
+func (s *Storage) recordStamp(filename string) {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if s.stamps == nil {
+		return
+	}
+	if stamp, ok := s.statStamp(filename); ok {
+		s.stamps[filename] = stamp
+	}
+}
+
+// ExternalChanges returns the data files that changed on disk since the
+// previous call without going through this Storage, such as a git pull, an
+// import or another instance saving. The first call only records the current
+// state and returns nothing.
+func (s *Storage) ExternalChanges() []string {
+	// Holding the write lock means an own save is never caught between
+	// writing the file and recording its stamp.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	first := s.stamps == nil
+	if first {
+		s.stamps = make(map[string]fileStamp, len(watchedFiles))
+	}
+	var changed []string
+	for _, filename := range watchedFiles {
+		stamp, _ := s.statStamp(filename)
+		if prev := s.stamps[filename]; !first && !stamp.equal(prev) {
+			changed = append(changed, filename)
+		}
+		s.stamps[filename] = stamp
+	}
+	return changed
+}
@@ -64,6 +64,9 @@ type GitSync struct {
 	// Serializes git operations to avoid index/lock conflicts.
 	opMu gosync.Mutex
 
+	// ignoreChecked is set once .gitignore has every ignoredPatterns entry.
+	ignoreChecked bool
+
 	// Debounce duration (configurable for testing)
 	debounceDuration time.Duration
 }
@@ -111,20 +114,10 @@ func (g *GitSync) Init() error {
 		return fmt.Errorf("failed to initialize git repository: %w", err)
 	}
 
-	// Create .gitignore
-	gitignoreContent := `# today app - git sync ignore file
-backups/
-*.bak
-*.corrupt.*
-`
-	gitignorePath := filepath.Join(g.dataDir, ".gitignore")
-	if err := fsutil.WriteFileAtomic(gitignorePath, []byte(gitignoreContent), 0600); err != nil {
-		return fmt.Errorf("failed to create .gitignore: %w", err)
-	}
-
-	// Stage and commit .gitignore
-	if _, err := g.runGitTimeout(defaultGitTimeout, "add", ".gitignore"); err != nil {
-		return fmt.Errorf("failed to stage .gitignore: %w", err)
+	// Create .gitignore and stage it
+	g.ignoreChecked = false
+	if err := g.ensureIgnored(); err != nil {
+		return err
 	}
 
 	if _, err := g.runGitTimeout(commitGitTimeout, "-c", "commit.gpgsign=false", "commit", "-m", "Initialize today data repository"); err != nil {
@@ -136,6 +129,56 @@ backups/
 	return nil
 }
 
+// ignoredPatterns are kept out of the data repository: backups, recovery
+// copies, lock files and the local activity journal.
+var ignoredPatterns = []string{"backups/", "*.bak", "*.corrupt.*", "locks/", "activity.jsonl"}
+
+// ensureIgnored adds the ignoredPatterns missing from .gitignore, as in
+// repositories set up by older versions, and stages the change together
+// with untracking files the new patterns cover. Callers hold opMu.
+func (g *GitSync) ensureIgnored() error {
+	if g.ignoreChecked {
+		return nil
+	}
+	gitignorePath := filepath.Join(g.dataDir, ".gitignore")
+	content, err := os.ReadFile(gitignorePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, pattern := range ignoredPatterns {
+		if !present[pattern] {
+			missing = append(missing, pattern)
+		}
+	}
+
+	if len(missing) > 0 {
+		if len(content) == 0 {
+			content = []byte("# today app - git sync ignore file\n")
+		} else if !bytes.HasSuffix(content, []byte("\n")) {
+			content = append(content, '\n')
+		}
+		content = append(content, strings.Join(missing, "\n")+"\n"...)
+		if err := fsutil.WriteFileAtomic(gitignorePath, content, 0600); err != nil {
+			return fmt.Errorf("failed to write .gitignore: %w", err)
+		}
+		// Files committed before they were ignored would stay tracked.
+		args := append([]string{"rm", "-r", "--cached", "--ignore-unmatch", "--quiet", "--"}, missing...)
+		if _, err := g.runGitTimeout(defaultGitTimeout, args...); err != nil {
+			return fmt.Errorf("failed to untrack ignored files: %w", err)
+		}
+		if _, err := g.runGitTimeout(defaultGitTimeout, "add", ".gitignore"); err != nil {
+			return fmt.Errorf("failed to stage .gitignore: %w", err)
+		}
+	}
+	g.ignoreChecked = true
+	return nil
+}
+
 // Status returns the current git status.
 func (g *GitSync) Status() (*Status, error) {
 	g.opMu.Lock()
@@ -208,6 +251,9 @@ func (g *GitSync) Commit(files []string) error {
 	if !g.IsRepo() {
 		return fmt.Errorf("not a git repository - run 'today sync --init' first")
 	}
+	if err := g.ensureIgnored(); err != nil {
+		return err
+	}
 
 	if len(files) == 0 {
 		return nil
@@ -256,6 +302,9 @@ func (g *GitSync) CommitAll() error {
 	if !g.IsRepo() {
 		return fmt.Errorf("not a git repository - run 'today sync --init' first")
 	}
+	if err := g.ensureIgnored(); err != nil {
+		return err
+	}
 
 	// Stage all tracked files
 	if _, err := g.runGitTimeout(defaultGitTimeout, "add", "-A"); err != nil {
@@ -409,6 +458,9 @@ func (g *GitSync) OnFileSavedWithContext(ctx storage.SaveContext) {
 	defer g.mu.Unlock()
 
 	g.pendingFiles[ctx.Filename] = true
+	for _, filename := range ctx.Files {
+		g.pendingFiles[filepath.ToSlash(filename)] = true
+	}
 	g.pendingContexts = append(g.pendingContexts, ctx)
 
 	// Reset timer - commit after debounce duration of no changes
@@ -456,6 +508,9 @@ func (g *GitSync) commitWithContexts(files []string, contexts []storage.SaveCont
 	if !g.IsRepo() {
 		return fmt.Errorf("not a git repository - run 'today sync --init' first")
 	}
+	if err := g.ensureIgnored(); err != nil {
+		return err
+	}
 
 	if len(files) == 0 {
 		return nil
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"today/internal/storage"
 )
 
 // skipIfNoGit skips the test if git is not installed.
@@ -73,7 +75,7 @@ func TestGitSync_Init(t *testing.T) {
 		t.Fatalf("Failed to read .gitignore: %v", err)
 	}
 
-	expectedPatterns := []string{"backups/", "*.bak", "*.corrupt.*"}
+	expectedPatterns := []string{"backups/", "*.bak", "*.corrupt.*", "locks/"}
 	for _, pattern := range expectedPatterns {
 		if !contains(string(content), pattern) {
 			t.Errorf("Expected .gitignore to contain %q", pattern)
@@ -81,6 +83,71 @@ func TestGitSync_Init(t *testing.T) {
 	}
 }
 
+// TestGitSync_IgnoresInExistingRepo tests that a repository set up without
+// the current ignore patterns gets them, and stops tracking lock files, on
+// its next commit.
+func TestGitSync_IgnoresInExistingRepo(t *testing.T) {
+	skipIfNoGit(t)
+
+	dir := createTestDir(t)
+	cfg := &Config{Enabled: true, AutoCommit: true}
+	gs := New(dir, cfg)
+	if err := gs.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+
+	// An older version ignored less and committed the lock directory.
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("backups/\n*.bak"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "locks"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tasks.json", filepath.Join("locks", "write.lock"), "activity.jsonl"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := gs.runGit("add", "-A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.runGit("-c", "commit.gpgsign=false", "commit", "-m", "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := New(dir, cfg)
+	if err := os.WriteFile(filepath.Join(dir, "tasks.json"), []byte(`{"tasks":[]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Commit([]string{"tasks.json"}); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pattern := range []string{"backups/", "*.bak", "locks/", "activity.jsonl"} {
+		if !contains(string(content), pattern) {
+			t.Errorf("Expected .gitignore to contain %q, got %q", pattern, content)
+		}
+	}
+	tracked, err := reopened.runGit("ls-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contains(tracked, "locks/") || contains(tracked, "activity.jsonl") {
+		t.Errorf("Ignored files still tracked: %q", tracked)
+	}
+	status, err := reopened.Status()
+	if err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if status.HasChanges {
+		t.Error("Expected the .gitignore update to be committed")
+	}
+}
+
 // TestGitSync_IsRepo tests repository detection.
 func TestGitSync_IsRepo(t *testing.T) {
 	skipIfNoGit(t)
@@ -462,6 +529,47 @@ func TestGitSync_Flush(t *testing.T) {
 	}
 }
 
+// TestGitSync_CommitContextFiles tests that every file named by a save
+// context is committed, not just its main file.
+func TestGitSync_CommitContextFiles(t *testing.T) {
+	skipIfNoGit(t)
+
+	dir := createTestDir(t)
+	cfg := &Config{Enabled: true, AutoCommit: true}
+	gs := New(dir, cfg)
+	gs.debounceDuration = 10 * time.Second
+
+	if err := gs.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "archive"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tasks.json", filepath.Join("archive", "tasks-2025-01.json")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(`{}`), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	gs.OnFileSavedWithContext(storage.SaveContext{
+		Filename:  "tasks.json",
+		Files:     []string{filepath.Join("archive", "tasks-2025-01.json")},
+		Operation: "archive",
+		ItemType:  "tasks",
+		ItemName:  "1 completed",
+	})
+	gs.Flush()
+
+	status, err := gs.Status()
+	if err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if status.HasChanges {
+		t.Error("Expected the archive file to be committed with tasks.json")
+	}
+}
+
 // TestGenerateCommitMessage tests commit message generation.
 func TestGenerateCommitMessage(t *testing.T) {
 	cfg := &Config{CommitMessage: "auto"}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"today/internal/storage"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// activityDays is how far back the activity view reads the journal.
+const activityDays = 30
+
+// ActivityView lists recent changes from the activity journal, newest first
+type ActivityView struct {
+	width   int
+	height  int
+	styles  *Styles
+	keys    ActivityKeyMap
+	entries []storage.ActivityEntry
+	cursor  int
+}
+
+// NewActivityView creates a new activity view.
+func NewActivityView(styles *Styles, keys ActivityKeyMap) *ActivityView {
+	return &ActivityView{
+		styles: styles,
+		keys:   keys,
+	}
+}
+
+// SetSize sets the view dimensions
+func (v *ActivityView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// SetEntries replaces the listed entries (oldest first, as stored) and
+// selects the newest.
+func (v *ActivityView) SetEntries(entries []storage.ActivityEntry) {
+	v.entries = make([]storage.ActivityEntry, len(entries))
+	for i, entry := range entries {
+		v.entries[len(entries)-1-i] = entry
+	}
+	v.cursor = 0
+}
+
+// Update handles navigation keys.
+func (v *ActivityView) Update(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, v.keys.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(msg, v.keys.Down):
+		if v.cursor < len(v.entries)-1 {
+			v.cursor++
+		}
+	case key.Matches(msg, v.keys.Top):
+		v.cursor = 0
+	case key.Matches(msg, v.keys.Bottom):
+		v.cursor = max(0, len(v.entries)-1)
+	}
+}
+
+// View renders the activity list
+func (v *ActivityView) View() string {
+	overlayWidth := 70
+	if v.width > 0 {
+		overlayWidth = min(70, max(20, v.width-4))
+	}
+
+	overlayStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(v.styles.ColorPrimary).
+		Padding(1, 2).
+		Width(overlayWidth)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(v.styles.ColorPrimary).
+		MarginBottom(1)
+
+	dateStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorTextMuted).
+		Width(13)
+
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorAccent).
+		Width(18)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorTextMuted).
+		Italic(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Activity (%d)", len(v.entries))))
+	b.WriteString("\n\n")
+
+	if len(v.entries) == 0 {
+		b.WriteString(mutedStyle.Render("No changes recorded yet"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor visible when the list is taller than the screen.
+	visible := len(v.entries)
+	if v.height > 0 {
+		visible = min(visible, max(1, v.height-12))
+	}
+	start := 0
+	if v.cursor >= visible {
+		start = v.cursor - visible + 1
+	}
+
+	// Content width inside border and padding, minus cursor, date and summary.
+	textWidth := max(10, overlayWidth-6-2-13-18)
+	for i := start; i < start+visible && i < len(v.entries); i++ {
+		entry := v.entries[i]
+		cursor := "  "
+		textStyle := v.styles.TaskPendingStyle
+		if i == v.cursor {
+			cursor = "▸ "
+			textStyle = v.styles.TaskSelectedStyle
+		}
+		date := dateStyle.Render(entry.Time.Format("Jan 2 15:04"))
+		summary := summaryStyle.Render(entry.Summary())
+		name := textStyle.Render(runewidth.FillRight(truncateText(entry.ItemName, textWidth), textWidth))
+		b.WriteString(cursor + date + summary + name + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("Changes from the last %d days · today log for more", activityDays)))
+
+	content := overlayStyle.Render(b.String())
+	return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -42,34 +42,54 @@ type AppConfig struct {
 	ConfirmDeletions      bool
 	ShowOnboarding        bool
 	NarrowLayoutThreshold int
+	ManualSort            bool     // Order tasks manually instead of by priority/due date
+	TrashRetentionDays    int      // Days deleted items are kept (shown in the trash view)
+	Profile               string   // Active profile, "" for the default one
+	Profiles              []string // Profiles that can be switched to, default first
 }
 
 // App is the main application model that coordinates all panes.
 type App struct {
-	storage     *storage.Storage
-	styles      *Styles
-	config      *AppConfig
-	taskPane    *TaskPane
-	timerPane   *TimerPane
-	habitsPane  *HabitsPane
-	helpOverlay *HelpOverlay
-	undoManager *UndoManager
-	undoBusy    bool
-	confirmDel  *confirmDeleteState
-	activePane  PaneID
-	layoutMode  LayoutMode
-	showHelp    bool
-	showWelcome bool
-	width       int
-	height      int
-	status      string
-	statusErr   bool
-	statusUntil time.Time
-	quitting    bool
+	storage      *storage.Storage
+	styles       *Styles
+	config       *AppConfig
+	taskPane     *TaskPane
+	timerPane    *TimerPane
+	habitsPane   *HabitsPane
+	helpOverlay  *HelpOverlay
+	trashView    *TrashView
+	activity     *ActivityView
+	profiles     *ProfileView
+	inactive     *InactiveHabitsView
+	undoManager  *UndoManager
+	undoBusy     bool
+	confirmDel   *confirmDeleteState
+	activePane   PaneID
+	layoutMode   LayoutMode
+	showHelp     bool
+	showTrash    bool
+	showLog      bool
+	showProfile  bool
+	showInactive bool
+	showWelcome  bool
+	width        int
+	height       int
+	status       string
+	statusErr    bool
+	statusUntil  time.Time
+	quitting     bool
+
+	// Profile picked in the profile view; the program quits so the caller
+	// can reopen the app on its data
+	switchProfile string
 
 	// Key bindings
-	keys     GlobalKeyMap
-	helpKeys HelpKeyMap
+	keys         GlobalKeyMap
+	helpKeys     HelpKeyMap
+	trashKeys    TrashKeyMap
+	logKeys      ActivityKeyMap
+	profKeys     ProfileKeyMap
+	inactiveKeys InactiveHabitsKeyMap
 
 	// Pane positions for mouse click detection (x coordinates)
 	tasksPaneStart  int
@@ -83,6 +103,9 @@ type App struct {
 	// Git sync state
 	gitSync    *sync.GitSync  // nil if sync disabled
 	syncStatus *sync.Status   // cached sync status for UI display
+
+	// Other today processes with the same data open
+	otherInstances []storage.Instance
 }
 
 type confirmDeleteState struct {
@@ -101,6 +124,7 @@ func NewApp(store *storage.Storage, styles *Styles, cfg *AppConfig) *App {
 			ConfirmDeletions:      true,
 			ShowOnboarding:        true,
 			NarrowLayoutThreshold: 80,
+			TrashRetentionDays:    30,
 		}
 	}
 	if cfg.Keys == nil {
@@ -109,27 +133,41 @@ func NewApp(store *storage.Storage, styles *Styles, cfg *AppConfig) *App {
 
 	// Create panes with config-aware key bindings
 	taskPane := NewTaskPaneWithKeys(store, styles, cfg.Keys)
+	taskPane.SetManualSort(cfg.ManualSort)
 	timerPane := NewTimerPaneWithKeys(store, styles, cfg.Keys)
 	habitsPane := NewHabitsPaneWithKeys(store, styles, cfg.Keys)
 	helpOverlay := NewHelpOverlay(styles)
+	trashKeys := NewTrashKeyMap(cfg.Keys)
+	trashView := NewTrashView(styles, trashKeys, cfg.TrashRetentionDays)
+	logKeys := NewActivityKeyMap(cfg.Keys)
+	profKeys := NewProfileKeyMap(cfg.Keys)
+	inactiveKeys := NewInactiveHabitsKeyMap(cfg.Keys)
 
 	// Determine if we should show welcome screen
 	showWelcome := cfg.ShowOnboarding && isFirstRun(store)
 
 	app := &App{
-		storage:     store,
-		styles:      styles,
-		config:      cfg,
-		taskPane:    taskPane,
-		timerPane:   timerPane,
-		habitsPane:  habitsPane,
-		helpOverlay: helpOverlay,
-		undoManager: NewUndoManager(),
-		activePane:  PaneTasks,
-		showHelp:    false,
-		showWelcome: showWelcome,
-		keys:        NewGlobalKeyMap(cfg.Keys),
-		helpKeys:    DefaultHelpKeyMap(),
+		storage:      store,
+		styles:       styles,
+		config:       cfg,
+		taskPane:     taskPane,
+		timerPane:    timerPane,
+		habitsPane:   habitsPane,
+		helpOverlay:  helpOverlay,
+		trashView:    trashView,
+		activity:     NewActivityView(styles, logKeys),
+		profiles:     NewProfileView(styles, profKeys, cfg.Profiles, cfg.Profile),
+		inactive:     NewInactiveHabitsView(styles, inactiveKeys),
+		undoManager:  NewUndoManager(),
+		activePane:   PaneTasks,
+		showHelp:     false,
+		showWelcome:  showWelcome,
+		keys:         NewGlobalKeyMap(cfg.Keys),
+		helpKeys:     DefaultHelpKeyMap(),
+		trashKeys:    trashKeys,
+		logKeys:      logKeys,
+		profKeys:     profKeys,
+		inactiveKeys: inactiveKeys,
 	}
 
 	// Set initial focus
@@ -176,6 +214,10 @@ func isFirstRun(store *storage.Storage) bool {
 	return true
 }
 
+// dataPollInterval is how often the data files are checked for changes made
+// outside this window (git sync, imports, other instances).
+const dataPollInterval = 2 * time.Second
+
 // tickMsg is sent periodically for time updates.
 type tickMsg time.Time
 
@@ -193,6 +235,8 @@ func (a *App) Init() tea.Cmd {
 		a.taskPane.LoadTasksCmd(),
 		a.timerPane.LoadTimerCmd(),
 		a.habitsPane.LoadHabitsCmd(),
+		registerInstanceCmd(a.storage),
+		watchDataCmd(a.storage, 0),
 	}
 
 	// Trigger initial sync status refresh if GitSync is available
@@ -225,6 +269,26 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := a.taskPane.Update(msg)
 		return a, cmd
 
+	case taskUpdatedMsg:
+		if msg.err != nil {
+			a.SetStatus("Edit task: "+msg.err.Error(), true)
+		} else {
+			// Push undo action on successful edit
+			a.undoManager.Push(NewUpdateTaskAction(a.storage, msg.before, msg.after))
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
+	case taskSnoozedMsg:
+		if msg.err != nil {
+			a.SetStatus("Snooze task: "+msg.err.Error(), true)
+		} else {
+			a.undoManager.Push(NewSnoozeTaskAction(a.storage, msg.before, msg.after))
+			a.SetStatus("Snoozed until "+msg.after.StartDate.Format("Mon Jan 2"), false)
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
 	case taskCompletedMsg:
 		if msg.err != nil {
 			a.SetStatus("Complete task: "+msg.err.Error(), true)
@@ -255,10 +319,63 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := a.taskPane.Update(msg)
 		return a, cmd
 
+	case checklistItemAddedMsg:
+		if msg.err != nil {
+			a.SetStatus("Add item: "+msg.err.Error(), true)
+		} else if msg.item != nil {
+			a.undoManager.Push(NewAddChecklistItemAction(a.storage, msg.taskID, *msg.item))
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
+	case checklistItemToggledMsg:
+		if msg.err != nil {
+			a.SetStatus("Toggle item: "+msg.err.Error(), true)
+		} else {
+			a.undoManager.Push(NewToggleChecklistItemAction(a.storage, msg.taskID, msg.item, msg.done))
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
+	case blockerChangedMsg:
+		if msg.err != nil {
+			a.SetStatus("Block task: "+msg.err.Error(), true)
+		} else {
+			a.undoManager.Push(NewBlockerAction(a.storage, msg.taskID, msg.blockerID, msg.text, msg.added))
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
+	case tasksReorderedMsg:
+		if msg.err != nil {
+			a.SetStatus("Move task: "+msg.err.Error(), true)
+		} else {
+			a.undoManager.Push(NewReorderTasksAction(a.storage, msg.before, msg.after, msg.text))
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
+	case notesEditorMsg:
+		a.SetStatus("Notes: "+msg.err.Error(), true)
+		return a, nil
+
+	case checklistItemDeletedMsg:
+		if msg.err != nil {
+			a.SetStatus("Delete item: "+msg.err.Error(), true)
+		} else {
+			a.undoManager.Push(NewDeleteChecklistItemAction(a.storage, msg.taskID, msg.item, msg.index))
+		}
+		cmd := a.taskPane.Update(msg)
+		return a, cmd
+
 	case timerLoadedMsg:
 		if msg.err != nil {
 			a.SetStatus("Timer: "+msg.err.Error(), true)
 		}
+		if msg.store != nil {
+			// Tasks show the time tracked on them
+			a.taskPane.SetTimerStore(msg.store)
+		}
 		cmd := a.timerPane.Update(msg)
 		return a, cmd
 
@@ -279,6 +396,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case habitsLoadedMsg:
 		if msg.err != nil {
 			a.SetStatus("Habits: "+msg.err.Error(), true)
+		} else {
+			a.inactive.SetHabits(msg.store)
 		}
 		cmd := a.habitsPane.Update(msg)
 		return a, cmd
@@ -290,12 +409,54 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := a.habitsPane.Update(msg)
 		return a, cmd
 
+	case habitUpdatedMsg:
+		if msg.err != nil {
+			a.SetStatus("Edit habit: "+msg.err.Error(), true)
+		} else {
+			// Push undo action on successful edit
+			a.undoManager.Push(NewUpdateHabitAction(a.storage, msg.before, msg.after))
+		}
+		cmd := a.habitsPane.Update(msg)
+		return a, cmd
+
 	case habitToggledMsg:
 		if msg.err != nil {
 			a.SetStatus("Toggle habit: "+msg.err.Error(), true)
 		} else {
 			// Push undo action on successful toggle
-			a.undoManager.Push(NewToggleHabitAction(a.storage, msg.id, msg.name, msg.date, msg.wasCompleted))
+			a.undoManager.Push(NewToggleHabitAction(a.storage, msg.id, msg.name, msg.date, msg.wasCompleted, msg.wasValue))
+		}
+		cmd := a.habitsPane.Update(msg)
+		return a, cmd
+
+	case habitSkippedMsg:
+		if msg.err != nil {
+			a.SetStatus("Skip habit: "+msg.err.Error(), true)
+		} else {
+			// Push undo action on successful skip
+			a.undoManager.Push(NewSkipHabitAction(a.storage, msg.id, msg.name, msg.date, msg.skipped, msg.wasValue))
+		}
+		cmd := a.habitsPane.Update(msg)
+		return a, cmd
+
+	case habitLoggedMsg:
+		if msg.err != nil {
+			a.SetStatus("Log habit: "+msg.err.Error(), true)
+		} else if msg.after != msg.before {
+			// Push undo action when the amount changed
+			a.undoManager.Push(NewLogHabitAction(a.storage, msg.habit, msg.date, msg.before, msg.after))
+		}
+		cmd := a.habitsPane.Update(msg)
+		return a, cmd
+
+	case habitStateChangedMsg:
+		if msg.err != nil {
+			a.SetStatus("Habit: "+msg.err.Error(), true)
+		} else {
+			// Push undo action on successful pause, archive or resume
+			action := NewHabitStateAction(a.storage, msg.before, msg.after)
+			a.undoManager.Push(action)
+			a.SetStatus(action.Description, false)
 		}
 		cmd := a.habitsPane.Update(msg)
 		return a, cmd
@@ -310,12 +471,88 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd := a.habitsPane.Update(msg)
 		return a, cmd
 
+	case trashLoadedMsg:
+		if msg.err != nil {
+			a.SetStatus("Trash: "+msg.err.Error(), true)
+		}
+		a.trashView.SetItems(msg.items)
+		return a, nil
+
+	case activityLoadedMsg:
+		if msg.err != nil {
+			a.SetStatus("Activity: "+msg.err.Error(), true)
+		}
+		a.activity.SetEntries(msg.entries)
+		return a, nil
+
+	case trashRestoredMsg:
+		if msg.err != nil {
+			a.SetStatus("Restore: "+msg.err.Error(), true)
+			return a, loadTrashCmd(a.storage)
+		}
+		a.undoManager.Push(NewRestoreFromTrashAction(a.storage, *msg.item))
+		a.SetStatus(fmt.Sprintf("Restored %s: %s", msg.item.Kind, truncateText(msg.item.Name(), 30)), false)
+		return a, tea.Batch(
+			loadTrashCmd(a.storage),
+			a.taskPane.LoadTasksCmd(),
+			a.habitsPane.LoadHabitsCmd(),
+		)
+
+	case trashPurgedMsg:
+		if msg.err != nil {
+			a.SetStatus("Purge: "+msg.err.Error(), true)
+		} else {
+			a.SetStatus("Purged: "+truncateText(msg.item.Name(), 30), false)
+		}
+		return a, loadTrashCmd(a.storage)
+
+	case activityErrorMsg:
+		a.SetStatus("Activity journal: "+msg.err.Error(), true)
+		return a, nil
+
 	case syncStatusMsg:
 		// Update cached sync status (ignore errors - just don't update display)
 		if msg.err == nil && msg.status != nil {
 			a.syncStatus = msg.status
 		}
 		return a, nil
+
+	case instancesMsg:
+		// Only warn when another instance first appears; the title bar
+		// keeps showing it after the status message expires.
+		if msg.err == nil {
+			if len(msg.others) > 0 && len(a.otherInstances) == 0 {
+				a.SetStatus(fmt.Sprintf("Another today (pid %d) has this data open; edits are locked and merged", msg.others[0].PID), true)
+			}
+			a.otherInstances = msg.others
+		}
+		return a, instanceHeartbeatCmd()
+
+	case instanceTickMsg:
+		return a, registerInstanceCmd(a.storage)
+
+	case dataChangedMsg:
+		cmds := []tea.Cmd{watchDataCmd(a.storage, dataPollInterval)}
+		var names []string
+		for _, file := range msg.files {
+			switch file {
+			case "tasks.json":
+				cmds = append(cmds, reloadTasksCmd(a.storage))
+			case "habits.json":
+				cmds = append(cmds, reloadHabitsCmd(a.storage))
+			case "timer.json":
+				cmds = append(cmds, a.timerPane.LoadTimerCmd())
+			case "trash.json":
+				if a.showTrash {
+					cmds = append(cmds, loadTrashCmd(a.storage))
+				}
+			}
+			names = append(names, strings.TrimSuffix(file, ".json"))
+		}
+		if len(names) > 0 {
+			a.SetStatus("Reloaded "+strings.Join(names, ", ")+": changed outside this window", false)
+		}
+		return a, tea.Batch(cmds...)
 	}
 
 	switch msg := msg.(type) {
@@ -349,15 +586,50 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
+		if a.showTrash {
+			return a, a.handleTrashKey(msg)
+		}
+
+		if a.showLog {
+			switch {
+			case key.Matches(msg, a.logKeys.Close), key.Matches(msg, a.keys.Activity):
+				a.showLog = false
+			default:
+				a.activity.Update(msg)
+			}
+			return a, nil
+		}
+
+		if a.showInactive {
+			return a, a.handleInactiveHabitsKey(msg)
+		}
+
+		if a.showProfile {
+			switch {
+			case key.Matches(msg, a.profKeys.Close), key.Matches(msg, a.keys.Profiles):
+				a.showProfile = false
+			case key.Matches(msg, a.profKeys.Select):
+				a.showProfile = false
+				if next := a.profiles.Selected(); next != "" {
+					a.switchProfile = next
+					return a, tea.Quit
+				}
+			default:
+				a.profiles.Update(msg)
+			}
+			return a, nil
+		}
+
 		// Check if any pane is in input mode
-		inInputMode := a.taskPane.IsAdding() || a.timerPane.IsSwitching() || a.habitsPane.IsAdding()
+		inInputMode := a.taskPane.InInputMode() || a.timerPane.IsSwitching() || a.habitsPane.IsAdding()
 
 		if !inInputMode {
 			// Confirm deletions (tasks/habits) if enabled.
 			if a.config.ConfirmDeletions {
 				switch a.activePane {
 				case PaneTasks:
-					if key.Matches(msg, a.taskPane.keys.Delete) {
+					// Checklist items are deleted without confirmation (undo covers them).
+					if key.Matches(msg, a.taskPane.keys.Delete) && a.taskPane.itemCursor < 0 {
 						if len(a.taskPane.tasks) == 0 || a.taskPane.cursor < 0 || a.taskPane.cursor >= len(a.taskPane.tasks) {
 							a.SetStatus("No task selected", true)
 							return a, nil
@@ -372,11 +644,11 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				case PaneHabits:
 					if key.Matches(msg, a.habitsPane.keys.Delete) {
-						if len(a.habitsPane.habitStore.Habits) == 0 || a.habitsPane.cursor < 0 || a.habitsPane.cursor >= len(a.habitsPane.habitStore.Habits) {
+						if len(a.habitsPane.habits) == 0 || a.habitsPane.cursor < 0 || a.habitsPane.cursor >= len(a.habitsPane.habits) {
 							a.SetStatus("No habit selected", true)
 							return a, nil
 						}
-						habit := a.habitsPane.habitStore.Habits[a.habitsPane.cursor]
+						habit := a.habitsPane.habits[a.habitsPane.cursor]
 						a.confirmDel = &confirmDeleteState{
 							title: "Delete habit?",
 							body:  truncateText(habit.Name, 60),
@@ -387,6 +659,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+			// The paused and archived habits open from the habits pane
+			if a.activePane == PaneHabits && key.Matches(msg, a.habitsPane.keys.Inactive) {
+				a.inactive.SetHabits(a.habitsPane.habitStore)
+				a.showInactive = true
+				return a, nil
+			}
+
 			// Global keys only when not in input mode
 			switch {
 			case key.Matches(msg, a.keys.Quit):
@@ -397,6 +676,23 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.showHelp = true
 				return a, nil
 
+			case key.Matches(msg, a.keys.Trash):
+				a.showTrash = true
+				return a, loadTrashCmd(a.storage)
+
+			case key.Matches(msg, a.keys.Activity):
+				a.showLog = true
+				return a, loadActivityCmd(a.storage)
+
+			case key.Matches(msg, a.keys.Profiles):
+				if len(a.config.Profiles) <= 1 {
+					a.SetStatus("No other profiles configured", false)
+					return a, nil
+				}
+				a.profiles.Reset()
+				a.showProfile = true
+				return a, nil
+
 			case key.Matches(msg, a.keys.NextPane):
 				a.switchPane()
 				return a, nil
@@ -462,6 +758,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
+		// The trash, activity, profile and inactive habits views are
+		// keyboard-only; a click closes them like help
+		if a.showTrash || a.showLog || a.showProfile || a.showInactive {
+			if msg.Action == tea.MouseActionPress {
+				a.showTrash = false
+				a.showLog = false
+				a.showProfile = false
+				a.showInactive = false
+			}
+			return a, nil
+		}
+
 		// Handle mouse events
 		switch msg.Action {
 		case tea.MouseActionPress:
@@ -513,6 +821,15 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case tea.MouseActionRelease:
+			// A release ends a drag in the task list
+			if a.activePane == PaneTasks && msg.Y >= a.contentTop {
+				localMsg := msg
+				localMsg.Y = msg.Y - a.contentTop
+				cmd := a.taskPane.Update(localMsg)
+				return a, cmd
+			}
+
 		case tea.MouseActionMotion:
 			// Ignore motion events for now
 
@@ -653,6 +970,10 @@ func (a *App) updateLayout() {
 
 	// Update help overlay size
 	a.helpOverlay.SetSize(a.width, a.height)
+	a.trashView.SetSize(a.width, a.height-1)
+	a.activity.SetSize(a.width, a.height-1)
+	a.profiles.SetSize(a.width, a.height-1)
+	a.inactive.SetSize(a.width, a.height-1)
 
 	totalWidth := a.width - 4
 
@@ -724,6 +1045,9 @@ func (a *App) updateLayout() {
 
 // View renders the entire app.
 func (a *App) View() string {
+	if a.switchProfile != "" {
+		return ""
+	}
 	if a.quitting {
 		return a.renderGoodbye()
 	}
@@ -741,6 +1065,20 @@ func (a *App) View() string {
 		return a.helpOverlay.View()
 	}
 
+	// The trash view keeps the help bar for key hints and status messages
+	if a.showTrash {
+		return a.trashView.View() + "\n" + a.renderHelpBar()
+	}
+	if a.showLog {
+		return a.activity.View() + "\n" + a.renderHelpBar()
+	}
+	if a.showProfile {
+		return a.profiles.View() + "\n" + a.renderHelpBar()
+	}
+	if a.showInactive {
+		return a.inactive.View() + "\n" + a.renderHelpBar()
+	}
+
 	var b strings.Builder
 
 	// Title bar
@@ -834,6 +1172,64 @@ func (a *App) renderConfirmDelete() string {
 	return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, content)
 }
 
+// handleTrashKey handles a key press while the trash view is open.
+func (a *App) handleTrashKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, a.trashKeys.Close), key.Matches(msg, a.keys.Trash):
+		a.showTrash = false
+		return nil
+
+	case key.Matches(msg, a.trashKeys.Restore):
+		item, ok := a.trashView.Selected()
+		if !ok {
+			a.SetStatus("Trash is empty", false)
+			return nil
+		}
+		return restoreFromTrashCmd(a.storage, item.ID())
+
+	case key.Matches(msg, a.trashKeys.Purge):
+		item, ok := a.trashView.Selected()
+		if !ok {
+			a.SetStatus("Trash is empty", false)
+			return nil
+		}
+		cmd := purgeFromTrashCmd(a.storage, item)
+		if a.config.ConfirmDeletions {
+			a.confirmDel = &confirmDeleteState{
+				title: "Delete permanently?",
+				body:  truncateText(item.Name(), 60),
+				cmd:   cmd,
+			}
+			return nil
+		}
+		return cmd
+	}
+
+	a.trashView.Update(msg)
+	return nil
+}
+
+// handleInactiveHabitsKey handles a key press while the paused and archived
+// habits are listed.
+func (a *App) handleInactiveHabitsKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, a.inactiveKeys.Close), key.Matches(msg, a.habitsPane.keys.Inactive):
+		a.showInactive = false
+		return nil
+
+	case key.Matches(msg, a.inactiveKeys.Restore):
+		habit, ok := a.inactive.Selected()
+		if !ok {
+			a.SetStatus("No paused or archived habits", false)
+			return nil
+		}
+		return setHabitStateCmd(a.storage, habit, storage.HabitActive)
+	}
+
+	a.inactive.Update(msg)
+	return nil
+}
+
 // renderWideContent renders all three panes side by side.
 func (a *App) renderWideContent() string {
 	tasksView := a.taskPane.View()
@@ -934,7 +1330,12 @@ func (a *App) renderGoodbye() string {
 
 // renderTitleBar creates the top title bar with stats and timer status.
 func (a *App) renderTitleBar() string {
-	title := a.styles.TitleStyle.Render(" today ")
+	// Name the profile unless it is the default one
+	name := " today "
+	if a.config.Profile != "" {
+		name = fmt.Sprintf(" today · %s ", a.config.Profile)
+	}
+	title := a.styles.TitleStyle.Render(name)
 
 	// Stats summary
 	tasksDone, tasksTotal := a.taskPane.Stats()
@@ -963,6 +1364,9 @@ func (a *App) renderTitleBar() string {
 
 	// Sync status indicator
 	syncStatus := a.renderSyncStatus()
+	if instances := a.renderInstanceWarning(); instances != "" {
+		syncStatus = strings.TrimSpace(instances + " " + syncStatus)
+	}
 
 	// Current date/time
 	now := time.Now()
@@ -1010,6 +1414,19 @@ func (a *App) renderTitleBar() string {
 	return strings.Join(parts, "")
 }
 
+// renderInstanceWarning renders a warning for the title bar while other today
+// processes have the same data open.
+func (a *App) renderInstanceWarning() string {
+	switch len(a.otherInstances) {
+	case 0:
+		return ""
+	case 1:
+		return a.styles.ErrorStyle.Render(fmt.Sprintf("! also open (pid %d)", a.otherInstances[0].PID))
+	default:
+		return a.styles.ErrorStyle.Render(fmt.Sprintf("! also open ×%d", len(a.otherInstances)))
+	}
+}
+
 // renderSyncStatus renders the sync status indicator for the title bar.
 // Returns empty string if sync is disabled or status unknown.
 func (a *App) renderSyncStatus() string {
@@ -1066,6 +1483,38 @@ func (a *App) renderHelpBar() string {
 		return a.styles.StatusStyle.Render(a.status)
 	}
 
+	if a.showTrash {
+		return a.styles.RenderHelp(
+			"j/k", "select",
+			"r/enter", "restore",
+			"x", "purge",
+			"esc", "close",
+		)
+	}
+
+	if a.showLog {
+		return a.styles.RenderHelp(
+			"j/k", "scroll",
+			"esc", "close",
+		)
+	}
+
+	if a.showProfile {
+		return a.styles.RenderHelp(
+			"j/k", "select",
+			"enter", "switch",
+			"esc", "close",
+		)
+	}
+
+	if a.showInactive {
+		return a.styles.RenderHelp(
+			"j/k", "select",
+			"r/enter", "restore",
+			"esc", "close",
+		)
+	}
+
 	// Input mode help
 	if a.taskPane.IsAdding() {
 		return a.styles.RenderHelp(
@@ -1074,6 +1523,35 @@ func (a *App) renderHelpBar() string {
 		)
 	}
 
+	if a.taskPane.IsEditing() {
+		return a.styles.RenderHelp(
+			"enter", "next/save",
+			"esc", "cancel",
+		)
+	}
+
+	if a.taskPane.IsAddingItem() {
+		return a.styles.RenderHelp(
+			"enter", "add item",
+			"esc", "done",
+		)
+	}
+
+	if a.taskPane.IsFiltering() {
+		return a.styles.RenderHelp(
+			"enter", "filter (empty: all)",
+			"esc", "cancel",
+		)
+	}
+
+	if a.taskPane.IsLinking() {
+		return a.styles.RenderHelp(
+			"j/k", "choose blocker",
+			"enter", "block/unblock",
+			"esc", "cancel",
+		)
+	}
+
 	if a.timerPane.IsSwitching() {
 		return a.styles.RenderHelp(
 			"enter", "start",
@@ -1093,6 +1571,7 @@ func (a *App) renderHelpBar() string {
 	case PaneTasks:
 		return a.styles.RenderHelp(
 			"a", "add",
+			"e", "edit",
 			"d", "done",
 			"x", "del",
 			"j/k", "nav",
@@ -1117,7 +1596,11 @@ func (a *App) renderHelpBar() string {
 	case PaneHabits:
 		return a.styles.RenderHelp(
 			"a", "add",
+			"e", "edit",
 			"space", "toggle",
+			"+/-", "log",
+			"s", "skip",
+			"p", "pause",
 			"x", "del",
 			"j/k", "nav",
 			"tab", "pane",
@@ -1141,20 +1624,31 @@ func (a *App) SetStatus(msg string, isErr bool) {
 
 // Run starts the Bubble Tea program with the given storage backend, styles, and config.
 func Run(store *storage.Storage, styles *Styles, cfg *AppConfig) error {
-	return RunWithSync(store, styles, cfg, nil)
+	_, err := RunWithSync(store, styles, cfg, nil)
+	return err
 }
 
 // RunWithSync starts the Bubble Tea program with optional GitSync for status display.
 // Pass nil for gitSync to disable sync status indicator in the UI.
-func RunWithSync(store *storage.Storage, styles *Styles, cfg *AppConfig, gitSync *sync.GitSync) error {
+// It returns the profile picked in the app to switch to, or "" if the app
+// was quit.
+func RunWithSync(store *storage.Storage, styles *Styles, cfg *AppConfig, gitSync *sync.GitSync) (string, error) {
 	app := NewApp(store, styles, cfg)
 	if gitSync != nil {
 		app.SetGitSync(gitSync)
 	}
+	defer store.UnregisterInstance()
+
 	p := tea.NewProgram(app,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(), // Enable mouse support
 	)
-	_, err := p.Run()
-	return err
+	// Journal failures go to the status bar rather than over the screen.
+	// Send blocks until the program reads the message, and saves can run
+	// inside Update, so it is sent from its own goroutine.
+	store.SetOnActivityError(func(err error) { go p.Send(activityErrorMsg{err: err}) })
+	if _, err := p.Run(); err != nil {
+		return "", err
+	}
+	return app.switchProfile, nil
 }
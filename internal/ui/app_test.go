@@ -5,9 +5,11 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"today/internal/config"
+	"today/internal/storage"
 )
 
 // TestApp_LayoutModeTransitions verifies layout mode changes based on width.
@@ -218,3 +220,292 @@ func TestApp_LayoutModeAfterResize(t *testing.T) {
 		t.Error("Expected LayoutWide after resize back")
 	}
 }
+
+// TestApp_Trash verifies deleted items can be browsed and restored.
+func TestApp_Trash(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC) })
+	task, _ := store.AddTask("Write report", "", storage.PriorityNone, nil)
+	habit, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.DeleteTask(task.ID)
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 2, 18, 5, 0, 0, time.UTC) })
+	store.DeleteHabit(habit.ID)
+
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+		TrashRetentionDays:    30,
+	})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if !app.showTrash || cmd == nil {
+		t.Fatal("T should open the trash and load it")
+	}
+	app.Update(cmd())
+	assertGolden(t, "trash_view", app.View())
+
+	// The newest deletion (the habit) is selected first; move to the task and restore it
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if cmd == nil {
+		t.Fatal("r should restore the selected item")
+	}
+	app.Update(cmd())
+
+	tasks, _ := store.LoadTasks()
+	if len(tasks.Tasks) != 1 || tasks.Tasks[0].ID != task.ID {
+		t.Errorf("tasks after restore = %v, want the restored task", tasks.Tasks)
+	}
+	if !strings.Contains(app.status, "Restored task: Write report") {
+		t.Errorf("status = %q, want restore confirmation", app.status)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.showTrash {
+		t.Error("esc should close the trash")
+	}
+}
+
+// TestApp_PausedHabits verifies habits can be paused and archived from the
+// habits pane and restored from their list.
+func TestApp_PausedHabits(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC) })
+	run, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.AddHabit("Read", "📚", storage.FrequencyDaily)
+	store.AddHabit("Meditate", "🧘", storage.FrequencyDaily)
+
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+	})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	app.Update(app.habitsPane.LoadHabitsCmd()())
+	app.setActivePane(PaneHabits)
+
+	// Runs a command and the reload it triggers
+	apply := func(cmd tea.Cmd) {
+		t.Helper()
+		if cmd == nil {
+			t.Fatal("expected a command")
+		}
+		_, reload := app.Update(cmd())
+		if reload != nil {
+			app.Update(reload())
+		}
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	apply(cmd)
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	apply(cmd)
+	if app.status != "Archived habit: Meditate" {
+		t.Errorf("status = %q, want archive confirmation", app.status)
+	}
+	if len(app.habitsPane.habits) != 1 || app.habitsPane.habits[0].Name != "Read" {
+		t.Fatalf("listed habits = %v, want only Read", app.habitsPane.habits)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	if !app.showInactive {
+		t.Fatal("I should open the paused and archived habits")
+	}
+	assertGolden(t, "inactive_habits_view", app.View())
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	apply(cmd)
+	if app.status != "Resumed habit: Exercise" {
+		t.Errorf("status = %q, want resume confirmation", app.status)
+	}
+	habits, _ := store.LoadHabits()
+	for _, h := range habits.Habits {
+		if h.ID == run.ID && !h.IsActive() {
+			t.Error("r should resume the selected habit")
+		}
+	}
+	if len(app.habitsPane.habits) != 2 || len(app.inactive.habits) != 1 {
+		t.Errorf("habits = %d listed, %d inactive; want 2 and 1", len(app.habitsPane.habits), len(app.inactive.habits))
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.showInactive {
+		t.Error("esc should close the paused and archived habits")
+	}
+}
+
+// TestApp_OtherInstanceWarning verifies the warning shown while another
+// today process has the same data open.
+func TestApp_OtherInstanceWarning(t *testing.T) {
+	store := createTestStorage(t)
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+	})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	_, cmd := app.Update(instancesMsg{others: []storage.Instance{{PID: 4242, Host: "laptop"}}})
+	if cmd == nil {
+		t.Error("instancesMsg should schedule the next heartbeat")
+	}
+	if !app.statusErr || !strings.Contains(app.status, "pid 4242") {
+		t.Errorf("status = %q, want a warning naming the other process", app.status)
+	}
+	if !strings.Contains(app.renderTitleBar(), "also open (pid 4242)") {
+		t.Error("title bar should show the other instance")
+	}
+
+	// Later heartbeats keep the indicator without repeating the status.
+	app.SetStatus("", false)
+	app.Update(instancesMsg{others: []storage.Instance{{PID: 4242}, {PID: 4343}}})
+	if app.status != "" {
+		t.Errorf("status = %q, want no repeated warning", app.status)
+	}
+	if !strings.Contains(app.renderTitleBar(), "also open ×2") {
+		t.Error("title bar should count the other instances")
+	}
+
+	app.Update(instancesMsg{})
+	if strings.Contains(app.renderTitleBar(), "also open") {
+		t.Error("warning should clear once the other instances exit")
+	}
+}
+
+func TestApp_Activity(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC) })
+	task, _ := store.AddTask("Write report", "", storage.PriorityNone, nil)
+	store.SetNowFunc(func() time.Time { return time.Date(2025, 3, 1, 16, 45, 0, 0, time.UTC) })
+	store.CompleteTask(task.ID)
+	habit, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.ToggleHabitToday(habit.ID)
+
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+	})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if !app.showLog || cmd == nil {
+		t.Fatal("L should open the activity view and load it")
+	}
+	app.Update(cmd())
+	assertGolden(t, "activity_view", app.View())
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.showLog {
+		t.Error("esc should close the activity view")
+	}
+}
+
+func TestApp_ProfileSwitch(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+		Profile:               "work",
+		Profiles:              []string{"default", "personal", "work"},
+	})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	if !strings.Contains(app.View(), "today · work") {
+		t.Error("title bar should name the active profile")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if !app.showProfile {
+		t.Fatal("P should open the profile view")
+	}
+	assertGolden(t, "profile_view", app.View())
+
+	// Choosing the active profile just closes the view
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.showProfile || cmd != nil || app.switchProfile != "" {
+		t.Fatal("enter on the active profile should only close the view")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.switchProfile != "personal" || cmd == nil {
+		t.Fatalf("switchProfile = %q, want personal with a quit command", app.switchProfile)
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("switching profiles should quit the program")
+	}
+}
+
+func TestApp_ProfileSwitchWithoutProfiles(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+		Profiles:              []string{"default"},
+	})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if app.showProfile {
+		t.Error("P should not open the profile view without other profiles")
+	}
+	if !strings.Contains(app.status, "No other profiles") {
+		t.Errorf("status = %q, want a hint", app.status)
+	}
+}
+
+// TestApp_ExternalReload verifies that changes written by another process are
+// picked up with the selection kept on the same task.
+func TestApp_ExternalReload(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := storage.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.AddTask("First", "", storage.PriorityNone, nil)
+	second, _ := store.AddTask("Second", "", storage.PriorityNone, nil)
+
+	app := NewApp(store, createTestStyles(), &AppConfig{
+		Keys:                  &config.KeysConfig{},
+		NarrowLayoutThreshold: 80,
+	})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	app.Update(loadTasksCmd(store)())
+	app.Update(watchDataCmd(store, 0)())
+	for i, task := range app.taskPane.tasks {
+		if task.ID == second.ID {
+			app.taskPane.cursor = i
+		}
+	}
+
+	// A high-priority task sorts above the selected one.
+	other.AddTask("Urgent", "", storage.PriorityHigh, nil)
+	msg := watchDataCmd(store, 0)()
+	if changed := msg.(dataChangedMsg).files; len(changed) != 1 || changed[0] != "tasks.json" {
+		t.Fatalf("changed files = %v, want [tasks.json]", changed)
+	}
+	app.Update(msg)
+	if !strings.Contains(app.status, "Reloaded tasks") {
+		t.Errorf("status = %q, want a reload notice", app.status)
+	}
+
+	app.Update(reloadTasksCmd(store)())
+	if len(app.taskPane.tasks) != 3 {
+		t.Fatalf("expected 3 tasks after reload, got %d", len(app.taskPane.tasks))
+	}
+	if got := app.taskPane.tasks[app.taskPane.cursor].ID; got != second.ID {
+		t.Errorf("cursor moved to %s, want it to stay on the selected task", got)
+	}
+}
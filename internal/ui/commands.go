@@ -6,6 +6,10 @@
 package ui
 
 import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
 	"time"
 
 	"today/internal/storage"
@@ -30,13 +34,34 @@ func loadTasksCmd(store *storage.Storage) tea.Cmd {
 }
 
 // addTaskCmd returns a command that creates a new task.
-func addTaskCmd(store *storage.Storage, text, project string, priority storage.Priority, dueDate *time.Time) tea.Cmd {
+func addTaskCmd(store *storage.Storage, text, project string, priority storage.Priority, dueDate *time.Time, tags ...string) tea.Cmd {
 	return func() tea.Msg {
-		task, err := store.AddTask(text, project, priority, dueDate)
+		task, err := store.AddTask(text, project, priority, dueDate, tags...)
 		return taskAddedMsg{task: task, err: err}
 	}
 }
 
+// updateTaskCmd returns a command that saves edits to an existing task.
+// Both snapshots are carried in the result so the edit can be undone.
+func updateTaskCmd(store *storage.Storage, before, after storage.Task) tea.Cmd {
+	return func() tea.Msg {
+		err := store.UpdateTask(after)
+		return taskUpdatedMsg{before: before, after: after, err: err}
+	}
+}
+
+// snoozeTaskCmd returns a command that defers a task by one day, moving its
+// start date to the day after today (or after its current start date).
+func snoozeTaskCmd(store *storage.Storage, task storage.Task) tea.Cmd {
+	return func() tea.Msg {
+		after := task
+		start := storage.SnoozeStart(task, store.Now(), 1)
+		after.StartDate = &start
+		err := store.UpdateTask(after)
+		return taskSnoozedMsg{before: task, after: after, err: err}
+	}
+}
+
 // completeTaskCmd returns a command that marks a task as done.
 // Captures task text before completing for undo description.
 func completeTaskCmd(store *storage.Storage, id string) tea.Cmd {
@@ -98,6 +123,118 @@ func deleteTaskCmd(store *storage.Storage, id string) tea.Cmd {
 	}
 }
 
+// addChecklistItemCmd returns a command that appends a checklist item to a task.
+func addChecklistItemCmd(store *storage.Storage, taskID, text string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := store.AddChecklistItem(taskID, text)
+		return checklistItemAddedMsg{taskID: taskID, item: item, err: err}
+	}
+}
+
+// toggleChecklistItemCmd returns a command that flips a checklist item's done state.
+func toggleChecklistItemCmd(store *storage.Storage, taskID string, item storage.ChecklistItem) tea.Cmd {
+	return func() tea.Msg {
+		err := store.SetChecklistItemDone(taskID, item.ID, !item.Done)
+		return checklistItemToggledMsg{taskID: taskID, item: item, done: !item.Done, err: err}
+	}
+}
+
+// deleteChecklistItemCmd returns a command that removes a checklist item.
+// The item and its position are carried in the result for undo restoration.
+func deleteChecklistItemCmd(store *storage.Storage, taskID string, item storage.ChecklistItem, index int) tea.Cmd {
+	return func() tea.Msg {
+		err := store.DeleteChecklistItem(taskID, item.ID)
+		return checklistItemDeletedMsg{taskID: taskID, item: item, index: index, err: err}
+	}
+}
+
+// setBlockerCmd returns a command that adds (or removes) blocker as a task
+// that must be done before task can start.
+func setBlockerCmd(store *storage.Storage, task, blocker storage.Task, add bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if add {
+			err = store.AddBlocker(task.ID, blocker.ID)
+		} else {
+			err = store.RemoveBlocker(task.ID, blocker.ID)
+		}
+		return blockerChangedMsg{taskID: task.ID, blockerID: blocker.ID, text: task.Text, added: add, err: err}
+	}
+}
+
+// reorderTasksCmd returns a command that saves a new manual task order.
+func reorderTasksCmd(store *storage.Storage, before, after []string, text string) tea.Cmd {
+	return func() tea.Msg {
+		err := store.SetTaskOrder(after)
+		return tasksReorderedMsg{before: before, after: after, text: text, err: err}
+	}
+}
+
+// editNotesCmd returns a command that opens the task's notes in the user's
+// editor. The TUI is suspended while the editor runs; changed notes are saved
+// with SetTaskNotes, which leaves any other change made to the task meanwhile
+// alone, and reported as a taskUpdatedMsg so the edit can be undone. The
+// working copy is removed however the edit ends; with encrypted data it is
+// kept in the store's private scratch directory rather than the shared temp
+// directory.
+func editNotesCmd(store *storage.Storage, task storage.Task) tea.Cmd {
+	dir, err := store.ScratchDir()
+	if err != nil {
+		return func() tea.Msg { return notesEditorMsg{err: err} }
+	}
+	f, err := os.CreateTemp(dir, "today-notes-*.md")
+	if err != nil {
+		return func() tea.Msg { return notesEditorMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(task.Notes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return notesEditorMsg{err: err} }
+	}
+
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return notesEditorMsg{err: err}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return notesEditorMsg{err: err}
+		}
+
+		notes := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), " \t\n")
+		if notes == task.Notes {
+			return nil
+		}
+		before, after, err := store.SetTaskNotes(task.ID, notes)
+		if err != nil {
+			return taskUpdatedMsg{before: task, after: task, err: err}
+		}
+		return taskUpdatedMsg{before: *before, after: *after}
+	})
+}
+
+// editorCommand builds the command that edits path, honoring $VISUAL and
+// then $EDITOR (which may carry arguments, e.g. "code --wait").
+func editorCommand(path string) *exec.Cmd {
+	editor := strings.TrimSpace(os.Getenv("VISUAL"))
+	if editor == "" {
+		editor = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	args := strings.Fields(editor)
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
 // =============================================================================
 // Timer Commands
 // =============================================================================
@@ -119,6 +256,15 @@ func startTimerCmd(store *storage.Storage, project string) tea.Cmd {
 	}
 }
 
+// startTaskTimerCmd returns a command that starts a timer tracking a task.
+// A timer already running (for another task or project) is stopped first.
+func startTaskTimerCmd(store *storage.Storage, task storage.Task) tea.Cmd {
+	return func() tea.Msg {
+		err := store.StartTaskTimer(task.ID)
+		return timerStartedMsg{project: task.Project, err: err}
+	}
+}
+
 // stopTimerCmd returns a command that stops the current timer.
 func stopTimerCmd(store *storage.Storage) tea.Cmd {
 	return func() tea.Msg {
@@ -139,14 +285,35 @@ func loadHabitsCmd(store *storage.Storage) tea.Cmd {
 	}
 }
 
-// addHabitCmd returns a command that creates a new habit.
-func addHabitCmd(store *storage.Storage, name, icon string) tea.Cmd {
+// addHabitCmd returns a command that creates a new habit from the fields
+// entered in the form.
+func addHabitCmd(store *storage.Storage, fields storage.Habit) tea.Cmd {
 	return func() tea.Msg {
-		habit, err := store.AddHabit(name, icon)
+		habit, err := store.CreateHabit(fields)
 		return habitAddedMsg{habit: habit, err: err}
 	}
 }
 
+// updateHabitCmd returns a command that saves edits to an existing habit.
+func updateHabitCmd(store *storage.Storage, before, after storage.Habit) tea.Cmd {
+	return func() tea.Msg {
+		err := store.UpdateHabit(after)
+		return habitUpdatedMsg{before: before, after: after, err: err}
+	}
+}
+
+// setHabitStateCmd returns a command that pauses, archives or resumes a
+// habit. before is kept for undo.
+func setHabitStateCmd(store *storage.Storage, before storage.Habit, state storage.HabitState) tea.Cmd {
+	return func() tea.Msg {
+		after, err := store.SetHabitState(before.ID, state)
+		if err != nil {
+			return habitStateChangedMsg{before: before, err: err}
+		}
+		return habitStateChangedMsg{before: before, after: *after}
+	}
+}
+
 // toggleHabitCmd returns a command that toggles a habit's completion for today.
 // Captures habit name and previous state for undo.
 func toggleHabitCmd(store *storage.Storage, id string) tea.Cmd {
@@ -154,6 +321,7 @@ func toggleHabitCmd(store *storage.Storage, id string) tea.Cmd {
 		// Capture habit name and current completion state before toggle
 		var habitName string
 		var wasCompleted bool
+		var wasValue int
 		today := time.Now().Format("2006-01-02")
 		if habits, err := store.LoadHabits(); err == nil {
 			// Find habit name
@@ -163,17 +331,57 @@ func toggleHabitCmd(store *storage.Storage, id string) tea.Cmd {
 					break
 				}
 			}
-			// Check if completed today
-			for _, log := range habits.Logs {
-				if log.HabitID == id && log.Date == today {
-					wasCompleted = true
+			// Check if completed today, and how much was logged
+			wasCompleted = store.IsHabitDoneOnDate(habits, id, today)
+			wasValue = store.HabitValueOnDate(habits, id, today)
+		}
+
+		isDone, err := store.ToggleHabitToday(id)
+		return habitToggledMsg{id: id, name: habitName, date: today, isDone: isDone, wasCompleted: wasCompleted, wasValue: wasValue, err: err}
+	}
+}
+
+// logHabitCmd returns a command that adds delta to the amount logged for a
+// habit today. Captures habit name and previous amount for undo.
+func logHabitCmd(store *storage.Storage, id string, delta int) tea.Cmd {
+	return func() tea.Msg {
+		var habit storage.Habit
+		var before int
+		today := time.Now().Format("2006-01-02")
+		if habits, err := store.LoadHabits(); err == nil {
+			for _, h := range habits.Habits {
+				if h.ID == id {
+					habit = h
 					break
 				}
 			}
+			before = store.HabitValueOnDate(habits, id, today)
 		}
 
-		isDone, err := store.ToggleHabitToday(id)
-		return habitToggledMsg{id: id, name: habitName, date: today, isDone: isDone, wasCompleted: wasCompleted, err: err}
+		after, err := store.AdjustHabitToday(id, delta)
+		return habitLoggedMsg{habit: habit, date: today, before: before, after: after, err: err}
+	}
+}
+
+// skipHabitCmd returns a command that skips a habit for today, or removes
+// today's skip mark. Captures habit name and what was logged for undo.
+func skipHabitCmd(store *storage.Storage, id string) tea.Cmd {
+	return func() tea.Msg {
+		var habitName string
+		var wasValue int
+		today := time.Now().Format("2006-01-02")
+		if habits, err := store.LoadHabits(); err == nil {
+			for _, h := range habits.Habits {
+				if h.ID == id {
+					habitName = h.Name
+					break
+				}
+			}
+			wasValue = store.HabitValueOnDate(habits, id, today)
+		}
+
+		skipped, err := store.ToggleHabitSkipToday(id)
+		return habitSkippedMsg{id: id, name: habitName, date: today, skipped: skipped, wasValue: wasValue, err: err}
 	}
 }
 
@@ -207,6 +415,46 @@ func deleteHabitCmd(store *storage.Storage, id string) tea.Cmd {
 	}
 }
 
+// =============================================================================
+// Trash Commands
+// =============================================================================
+
+// loadTrashCmd returns a command that loads the deleted items.
+func loadTrashCmd(store *storage.Storage) tea.Cmd {
+	return func() tea.Msg {
+		trash, err := store.LoadTrash()
+		if trash == nil {
+			return trashLoadedMsg{err: err}
+		}
+		return trashLoadedMsg{items: trash.Items, err: err}
+	}
+}
+
+// loadActivityCmd returns a command that loads recent journal entries.
+func loadActivityCmd(store *storage.Storage) tea.Cmd {
+	return func() tea.Msg {
+		since := store.Now().AddDate(0, 0, -activityDays)
+		entries, err := store.LoadActivity(since)
+		return activityLoadedMsg{entries: entries, err: err}
+	}
+}
+
+// restoreFromTrashCmd returns a command that puts a deleted item back.
+func restoreFromTrashCmd(store *storage.Storage, id string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := store.RestoreFromTrash(id)
+		return trashRestoredMsg{item: item, err: err}
+	}
+}
+
+// purgeFromTrashCmd returns a command that permanently deletes a trash item.
+func purgeFromTrashCmd(store *storage.Storage, item storage.TrashItem) tea.Cmd {
+	return func() tea.Msg {
+		err := store.PurgeFromTrash(item.ID())
+		return trashPurgedMsg{item: item, err: err}
+	}
+}
+
 // =============================================================================
 // Undo/Redo Commands
 // =============================================================================
@@ -240,3 +488,53 @@ func refreshSyncStatusCmd(gs *sync.GitSync) tea.Cmd {
 		return syncStatusMsg{status: status, err: err}
 	}
 }
+
+// registerInstanceCmd records this process in the data directory and reports
+// any other instances using it.
+func registerInstanceCmd(store *storage.Storage) tea.Cmd {
+	return func() tea.Msg {
+		others, err := store.RegisterInstance()
+		return instancesMsg{others: others, err: err}
+	}
+}
+
+// instanceHeartbeatCmd schedules the next registerInstanceCmd.
+func instanceHeartbeatCmd() tea.Cmd {
+	return tea.Tick(storage.InstanceHeartbeat, func(time.Time) tea.Msg {
+		return instanceTickMsg{}
+	})
+}
+
+// watchDataCmd checks the data files for changes made outside this window,
+// after waiting interval (immediately for the first check).
+func watchDataCmd(store *storage.Storage, interval time.Duration) tea.Cmd {
+	check := func() tea.Msg {
+		return dataChangedMsg{files: store.ExternalChanges()}
+	}
+	if interval <= 0 {
+		return check
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return check() })
+}
+
+// reloadTasksCmd loads tasks changed outside this window, keeping the
+// selection on the same task.
+func reloadTasksCmd(store *storage.Storage) tea.Cmd {
+	load := loadTasksCmd(store)
+	return func() tea.Msg {
+		msg := load().(tasksLoadedMsg)
+		msg.keepSelection = true
+		return msg
+	}
+}
+
+// reloadHabitsCmd loads habits changed outside this window, keeping the
+// selection on the same habit.
+func reloadHabitsCmd(store *storage.Storage) tea.Cmd {
+	load := loadHabitsCmd(store)
+	return func() tea.Msg {
+		msg := load().(habitsLoadedMsg)
+		msg.keepSelection = true
+		return msg
+	}
+}
@@ -4,6 +4,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"today/internal/config"
 	"today/internal/storage"
@@ -16,14 +17,18 @@ import (
 // HabitsPane handles habit tracking display and interactions.
 type HabitsPane struct {
 	habitStore *storage.HabitStore
+	habits     []storage.Habit // Active habits, as listed
 	cursor     int
 	focused    bool
 	width      int
 	height     int
-	adding     bool
-	addStep    int // 0 = name, 1 = icon
+	adding     bool // The add or edit form is open
+	editing    bool // The form edits habitOrig rather than adding a habit
+	addStep    int  // One of the habitStep constants
 	input      textinput.Model
-	newName    string
+	draft      storage.Habit // Values entered so far
+	habitOrig  storage.Habit // Habit being edited
+	formErr    string        // Validation error for the current step
 	storage    *storage.Storage
 	styles     *Styles
 
@@ -32,6 +37,15 @@ type HabitsPane struct {
 	inputKeys InputKeyMap
 }
 
+// Habit form steps, in the order they are presented.
+const (
+	habitStepName = iota
+	habitStepIcon
+	habitStepFrequency
+	habitStepTarget
+	habitStepCount
+)
+
 // NewHabitsPane creates a new habits pane.
 func NewHabitsPane(store *storage.Storage, styles *Styles) *HabitsPane {
 	return NewHabitsPaneWithKeys(store, styles, &config.KeysConfig{})
@@ -64,11 +78,34 @@ func (p *HabitsPane) LoadHabitsCmd() tea.Cmd {
 	return loadHabitsCmd(p.storage)
 }
 
-// setHabitStore updates the habit store and adjusts cursor bounds.
+// setHabitStore updates the habit store and adjusts cursor bounds. Paused
+// and archived habits are left out of the list.
 func (p *HabitsPane) setHabitStore(store *storage.HabitStore) {
 	p.habitStore = store
-	if p.cursor >= len(p.habitStore.Habits) {
-		p.cursor = max(0, len(p.habitStore.Habits)-1)
+	p.habits = nil
+	for _, habit := range store.Habits {
+		if habit.IsActive() {
+			p.habits = append(p.habits, habit)
+		}
+	}
+	if p.cursor >= len(p.habits) {
+		p.cursor = max(0, len(p.habits)-1)
+	}
+}
+
+// reloadHabitStore replaces the habits like setHabitStore, but keeps the
+// cursor on the selected habit if it still exists.
+func (p *HabitsPane) reloadHabitStore(store *storage.HabitStore) {
+	var selected string
+	if p.cursor >= 0 && p.cursor < len(p.habits) {
+		selected = p.habits[p.cursor].ID
+	}
+	p.setHabitStore(store)
+	for i, habit := range p.habits {
+		if habit.ID == selected {
+			p.cursor = i
+			break
+		}
 	}
 }
 
@@ -89,11 +126,16 @@ func (p *HabitsPane) IsFocused() bool {
 	return p.focused
 }
 
-// IsAdding returns whether we're in add mode.
+// IsAdding returns whether the add or edit form is open.
 func (p *HabitsPane) IsAdding() bool {
 	return p.adding
 }
 
+// IsEditing returns whether the form is editing an existing habit.
+func (p *HabitsPane) IsEditing() bool {
+	return p.adding && p.editing
+}
+
 // Update handles messages for the habits pane.
 func (p *HabitsPane) Update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
@@ -101,7 +143,9 @@ func (p *HabitsPane) Update(msg tea.Msg) tea.Cmd {
 	// Handle async messages first
 	switch msg := msg.(type) {
 	case habitsLoadedMsg:
-		if msg.store != nil {
+		if msg.store != nil && msg.keepSelection {
+			p.reloadHabitStore(msg.store)
+		} else if msg.store != nil {
 			p.setHabitStore(msg.store)
 		}
 		return nil
@@ -117,9 +161,25 @@ func (p *HabitsPane) Update(msg tea.Msg) tea.Cmd {
 		// Reload to refresh state
 		return p.LoadHabitsCmd()
 
+	case habitLoggedMsg:
+		// Reload to refresh progress
+		return p.LoadHabitsCmd()
+
+	case habitSkippedMsg:
+		// Reload to refresh state
+		return p.LoadHabitsCmd()
+
+	case habitUpdatedMsg:
+		// Reload to refresh the edited habit
+		return p.LoadHabitsCmd()
+
 	case habitDeletedMsg:
 		// Reload to refresh list
 		return p.LoadHabitsCmd()
+
+	case habitStateChangedMsg:
+		// Reload to move the habit between the list and the inactive ones
+		return p.LoadHabitsCmd()
 	}
 
 	// If we're adding a habit, handle input
@@ -128,26 +188,26 @@ func (p *HabitsPane) Update(msg tea.Msg) tea.Cmd {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, p.inputKeys.Confirm):
-				if p.addStep == 0 {
-					// Got name, now get icon
-					p.newName = strings.TrimSpace(p.input.Value())
-					if p.newName != "" {
-						p.addStep = 1
-						p.input.Reset()
-						p.input.Placeholder = "Icon (emoji, e.g., 🏃)"
-						p.input.CharLimit = 4
-					}
-				} else {
-					// Got icon, create habit asynchronously
-					icon := strings.TrimSpace(p.input.Value())
-					if icon == "" {
-						icon = "✓" // Default icon
-					}
-					name := p.newName
-					p.resetAddMode()
-					return addHabitCmd(p.storage, name, icon)
+				if err := p.applyFormStep(); err != nil {
+					p.formErr = err.Error()
+					return nil
 				}
-				return nil
+				p.formErr = ""
+				p.addStep++
+				if p.addStep < habitStepCount {
+					p.loadFormStep()
+					return nil
+				}
+				// All steps done, save asynchronously
+				editing, before, after := p.editing, p.habitOrig, p.draft
+				p.resetAddMode()
+				if !editing {
+					return addHabitCmd(p.storage, after)
+				}
+				if habitFieldsEqual(before, after) {
+					return nil
+				}
+				return updateHabitCmd(p.storage, before, after)
 
 			case key.Matches(msg, p.inputKeys.Cancel):
 				p.resetAddMode()
@@ -171,34 +231,61 @@ func (p *HabitsPane) Update(msg tea.Msg) tea.Cmd {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, p.keys.Down):
-			if len(p.habitStore.Habits) > 0 {
-				p.cursor = min(p.cursor+1, len(p.habitStore.Habits)-1)
+			if len(p.habits) > 0 {
+				p.cursor = min(p.cursor+1, len(p.habits)-1)
 			}
 
 		case key.Matches(msg, p.keys.Up):
-			if len(p.habitStore.Habits) > 0 {
+			if len(p.habits) > 0 {
 				p.cursor = max(p.cursor-1, 0)
 			}
 
 		case key.Matches(msg, p.keys.Add):
-			p.adding = true
-			p.addStep = 0
-			p.input.Placeholder = "Habit name (e.g., Exercise)"
-			p.input.CharLimit = 30
-			p.input.Focus()
+			p.startForm(storage.Habit{}, false)
 			return textinput.Blink
 
+		case key.Matches(msg, p.keys.Edit):
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				p.startForm(p.habits[p.cursor], true)
+				return textinput.Blink
+			}
+
 		case key.Matches(msg, p.keys.Toggle):
 			// Toggle habit for today asynchronously
-			if len(p.habitStore.Habits) > 0 && p.cursor < len(p.habitStore.Habits) {
-				habit := p.habitStore.Habits[p.cursor]
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				habit := p.habits[p.cursor]
 				return toggleHabitCmd(p.storage, habit.ID)
 			}
 
+		case key.Matches(msg, p.keys.Increment):
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				return logHabitCmd(p.storage, p.habits[p.cursor].ID, 1)
+			}
+
+		case key.Matches(msg, p.keys.Decrement):
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				return logHabitCmd(p.storage, p.habits[p.cursor].ID, -1)
+			}
+
+		case key.Matches(msg, p.keys.Skip):
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				return skipHabitCmd(p.storage, p.habits[p.cursor].ID)
+			}
+
+		case key.Matches(msg, p.keys.Pause):
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				return setHabitStateCmd(p.storage, p.habits[p.cursor], storage.HabitPaused)
+			}
+
+		case key.Matches(msg, p.keys.Archive):
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				return setHabitStateCmd(p.storage, p.habits[p.cursor], storage.HabitArchived)
+			}
+
 		case key.Matches(msg, p.keys.Delete):
 			// Delete habit asynchronously
-			if len(p.habitStore.Habits) > 0 && p.cursor < len(p.habitStore.Habits) {
-				habit := p.habitStore.Habits[p.cursor]
+			if len(p.habits) > 0 && p.cursor < len(p.habits) {
+				habit := p.habits[p.cursor]
 				return deleteHabitCmd(p.storage, habit.ID)
 			}
 		}
@@ -207,11 +294,93 @@ func (p *HabitsPane) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
-// resetAddMode resets the add habit state.
+// startForm opens the habit form, prefilled with habit when editing it.
+func (p *HabitsPane) startForm(habit storage.Habit, editing bool) {
+	p.adding = true
+	p.editing = editing
+	p.addStep = habitStepName
+	p.habitOrig = habit
+	p.draft = habit
+	p.formErr = ""
+	p.loadFormStep()
+	p.input.Focus()
+}
+
+// loadFormStep prefills the input with the draft value for the current step.
+func (p *HabitsPane) loadFormStep() {
+	var value string
+	switch p.addStep {
+	case habitStepName:
+		value = p.draft.Name
+		p.input.Placeholder = "Habit name (e.g., Exercise)"
+		p.input.CharLimit = 30
+	case habitStepIcon:
+		value = p.draft.Icon
+		p.input.Placeholder = "Icon (emoji, e.g., 🏃)"
+		p.input.CharLimit = 4
+	case habitStepFrequency:
+		if p.editing {
+			value = p.draft.ScheduleString()
+		}
+		p.input.Placeholder = "daily, weekdays, weekly or mon,wed,fri (blank for daily)"
+		p.input.CharLimit = 40
+	case habitStepTarget:
+		value = p.draft.TargetString()
+		p.input.Placeholder = "Amount per day, e.g. 8 glasses (blank for yes/no)"
+		p.input.CharLimit = 30
+	}
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+}
+
+// applyFormStep parses the input for the current step into the draft.
+func (p *HabitsPane) applyFormStep() error {
+	value := strings.TrimSpace(p.input.Value())
+	switch p.addStep {
+	case habitStepName:
+		if value == "" {
+			return fmt.Errorf("habit name is required")
+		}
+		p.draft.Name = value
+	case habitStepIcon:
+		if value == "" {
+			value = "✓" // Default icon
+		}
+		p.draft.Icon = value
+	case habitStepFrequency:
+		frequency, days, err := storage.ParseHabitSchedule(value)
+		if err != nil {
+			return err
+		}
+		if frequency == storage.FrequencyCustom && len(days) == 0 {
+			return fmt.Errorf("pick at least one day")
+		}
+		p.draft.Frequency, p.draft.CustomDays = frequency, days
+	case habitStepTarget:
+		target, unit, err := storage.ParseHabitTarget(value)
+		if err != nil {
+			return err
+		}
+		p.draft.Target, p.draft.Unit = target, unit
+	}
+	return nil
+}
+
+// habitFieldsEqual reports whether an edit changed nothing.
+func habitFieldsEqual(a, b storage.Habit) bool {
+	return a.Name == b.Name && a.Icon == b.Icon &&
+		a.EffectiveFrequency() == b.EffectiveFrequency() && a.ScheduleString() == b.ScheduleString() &&
+		a.TargetString() == b.TargetString()
+}
+
+// resetAddMode closes the add/edit form.
 func (p *HabitsPane) resetAddMode() {
 	p.adding = false
-	p.addStep = 0
-	p.newName = ""
+	p.editing = false
+	p.addStep = habitStepName
+	p.draft = storage.Habit{}
+	p.habitOrig = storage.Habit{}
+	p.formErr = ""
 	p.input.Reset()
 	p.input.Placeholder = "Habit name (e.g., Exercise)"
 	p.input.CharLimit = 30
@@ -219,7 +388,7 @@ func (p *HabitsPane) resetAddMode() {
 
 // handleMouse processes mouse events for the habits pane.
 func (p *HabitsPane) handleMouse(msg tea.MouseMsg) tea.Cmd {
-	if len(p.habitStore.Habits) == 0 {
+	if len(p.habits) == 0 {
 		return nil
 	}
 
@@ -234,7 +403,7 @@ func (p *HabitsPane) handleMouse(msg tea.MouseMsg) tea.Cmd {
 		return nil
 
 	case tea.MouseButtonWheelDown:
-		p.cursor = min(p.cursor+1, len(p.habitStore.Habits)-1)
+		p.cursor = min(p.cursor+1, len(p.habits)-1)
 		return nil
 
 	case tea.MouseButtonLeft:
@@ -244,7 +413,7 @@ func (p *HabitsPane) handleMouse(msg tea.MouseMsg) tea.Cmd {
 
 		// Calculate which habit was clicked
 		habitRow := msg.Y - headerRows
-		if habitRow < 0 || habitRow >= len(p.habitStore.Habits) {
+		if habitRow < 0 || habitRow >= len(p.habits) {
 			return nil
 		}
 
@@ -255,7 +424,7 @@ func (p *HabitsPane) handleMouse(msg tea.MouseMsg) tea.Cmd {
 		// Habit format: "🏃 Exercise  ●○○" - icon is at start
 		if msg.X < 4 {
 			// Toggle the clicked habit
-			habit := p.habitStore.Habits[p.cursor]
+			habit := p.habits[p.cursor]
 			return toggleHabitCmd(p.storage, habit.ID)
 		}
 	}
@@ -281,7 +450,13 @@ func (p *HabitsPane) View() string {
 	b.WriteString("\n")
 
 	// Habits list
-	if len(p.habitStore.Habits) == 0 && !p.adding {
+	if len(p.habits) == 0 && !p.adding && len(p.habitStore.Habits) > 0 {
+		b.WriteString("\n")
+		b.WriteString(p.styleMutedText("  All habits are paused or archived."))
+		b.WriteString("\n")
+		b.WriteString(p.styleMutedText(fmt.Sprintf("  Press '%s' to restore one.", bindingKey(p.keys.Inactive))))
+		b.WriteString("\n")
+	} else if len(p.habits) == 0 && !p.adding {
 		b.WriteString("\n")
 		b.WriteString(p.styleMutedText("  No habits yet."))
 		b.WriteString("\n")
@@ -290,16 +465,21 @@ func (p *HabitsPane) View() string {
 	} else {
 		b.WriteString("\n")
 
-		// Calculate max streak for display
-		maxStreak := 0
-		for _, habit := range p.habitStore.Habits {
+		// Calculate max streak for display; a week of a weekly habit
+		// weighs as much as seven days
+		maxStreak, maxDays, maxUnit := 0, 0, "days"
+		for _, habit := range p.habits {
 			streak := p.storage.GetHabitStreak(p.habitStore, habit.ID)
-			if streak > maxStreak {
-				maxStreak = streak
+			days := streak
+			if habit.StreakUnit() == "weeks" {
+				days *= 7
+			}
+			if days > maxDays {
+				maxStreak, maxDays, maxUnit = streak, days, habit.StreakUnit()
 			}
 		}
 
-		for i, habit := range p.habitStore.Habits {
+		for i, habit := range p.habits {
 			// Selection indicator
 			prefix := "  "
 			if i == p.cursor && p.focused && !p.adding {
@@ -311,22 +491,27 @@ func (p *HabitsPane) View() string {
 
 			// Week view (last 7 days)
 			week := p.storage.GetHabitWeek(p.habitStore, habit.ID)
-			weekView := p.renderWeekView(week)
+			weekView := p.renderWeekView(habit, week)
 			line += weekView
 
-			// Count for this week
-			weekCount := 0
-			for _, done := range week {
-				if done {
-					weekCount++
-				}
+			// Count for this week, out of the days the habit is due
+			done, due := p.storage.GetHabitWeekProgress(p.habitStore, habit.ID)
+			line += fmt.Sprintf("  %d/%d", done, due)
+
+			// Today's amount for habits with a target
+			if habit.HasTarget() {
+				value := p.storage.HabitValueOnDate(p.habitStore, habit.ID, p.storage.Now().Format("2006-01-02"))
+				line += "  " + habit.ProgressString(value)
 			}
-			line += fmt.Sprintf("  %d/7", weekCount)
 
-			// Streak (if > 1)
+			// Streak (if > 1); weekly habits count weeks
 			streak := p.storage.GetHabitStreak(p.habitStore, habit.ID)
 			if streak > 1 {
-				line += " " + p.styles.HabitStreakStyle.Render(fmt.Sprintf("🔥%d", streak))
+				unit := ""
+				if habit.StreakUnit() == "weeks" {
+					unit = "w"
+				}
+				line += " " + p.styles.HabitStreakStyle.Render(fmt.Sprintf("🔥%d%s", streak, unit))
 			}
 
 			// Highlight if selected
@@ -340,28 +525,42 @@ func (p *HabitsPane) View() string {
 
 		// Overall streak
 		if maxStreak > 0 {
+			if maxStreak == 1 {
+				maxUnit = strings.TrimSuffix(maxUnit, "s")
+			}
 			b.WriteString("\n")
-			b.WriteString("  " + p.styles.StatLabelStyle.Render("Best streak: ") + p.styles.HabitStreakStyle.Render(fmt.Sprintf("%d days 🔥", maxStreak)))
+			b.WriteString("  " + p.styles.StatLabelStyle.Render("Best streak: ") + p.styles.HabitStreakStyle.Render(fmt.Sprintf("%d %s 🔥", maxStreak, maxUnit)))
 			b.WriteString("\n")
 		}
 	}
 
+	// Vacation, while it lasts
+	if vacation := p.habitStore.Vacation; vacation.Contains(p.storage.Now().Format("2006-01-02")) {
+		until := vacation.End
+		if end, err := time.Parse("2006-01-02", vacation.End); err == nil {
+			until = end.Format("Jan 2")
+		}
+		b.WriteString("\n")
+		b.WriteString("  " + p.styles.HabitSkipIcon + " " + p.styleMutedText("On vacation until "+until+", streaks paused"))
+		b.WriteString("\n")
+	}
+
 	// Day labels
 	b.WriteString("\n")
 	b.WriteString("  " + p.styleMutedText(p.getDayLabels()))
 	b.WriteString("\n")
 
-	// Input field when adding
+	// Input field when adding or editing
 	if p.adding {
 		b.WriteString("\n")
-		var prompt string
-		if p.addStep == 0 {
-			prompt = p.styles.InputPromptStyle.Render("Name: ")
-		} else {
-			prompt = p.styles.InputPromptStyle.Render("Icon: ")
-		}
+		labels := [habitStepCount]string{"Name: ", "Icon: ", "Repeat: ", "Target: "}
+		prompt := p.styles.InputPromptStyle.Render(labels[p.addStep])
 		b.WriteString("  " + prompt + p.input.View())
 		b.WriteString("\n")
+		if p.formErr != "" {
+			b.WriteString("  " + p.styles.ErrorStyle.Render(p.formErr))
+			b.WriteString("\n")
+		}
 	}
 
 	// Apply pane style
@@ -374,13 +573,26 @@ func (p *HabitsPane) View() string {
 	return style.Width(p.width).Height(p.height).Render(content)
 }
 
-// renderWeekView creates the visual week representation.
-func (p *HabitsPane) renderWeekView(week []bool) string {
+// renderWeekView creates the visual week representation. week holds the
+// last 7 days, oldest first; days the habit is not due on are dimmed unless
+// it was done anyway, days with some progress towards the habit's target
+// are marked as partial, and days it was skipped or on vacation are marked
+// as such.
+func (p *HabitsPane) renderWeekView(habit storage.Habit, week []bool) string {
+	today := p.storage.Now()
 	var result string
-	for _, done := range week {
-		if done {
+	for i, done := range week {
+		day := today.AddDate(0, 0, -(len(week) - 1 - i))
+		switch {
+		case done:
 			result += p.styles.HabitDoneIcon + " "
-		} else {
+		case habit.HasTarget() && p.storage.HabitValueOnDate(p.habitStore, habit.ID, day.Format("2006-01-02")) > 0:
+			result += p.styles.HabitPartialIcon + " "
+		case p.storage.IsHabitSkippedOnDate(p.habitStore, habit.ID, day.Format("2006-01-02")):
+			result += p.styles.HabitSkipIcon + " "
+		case !habit.IsScheduledOn(day.Weekday()):
+			result += p.styles.HabitOffIcon + " "
+		default:
 			result += p.styles.HabitUndoneIcon + " "
 		}
 	}
@@ -410,12 +622,21 @@ func (p *HabitsPane) getDayLabels() string {
 	return strings.TrimSuffix(result, " ")
 }
 
+// bindingKey returns the first key bound to b, so hints follow remapped
+// keys.
+func bindingKey(b key.Binding) string {
+	if keys := b.Keys(); len(keys) > 0 {
+		return keys[0]
+	}
+	return b.Help().Key
+}
+
 // GetTodayCompletionRate returns how many habits were completed today.
 func (p *HabitsPane) GetTodayCompletionRate() (done, total int) {
 	today := p.storage.Now().Format("2006-01-02")
-	total = len(p.habitStore.Habits)
+	total = len(p.habits)
 
-	for _, habit := range p.habitStore.Habits {
+	for _, habit := range p.habits {
 		if p.storage.IsHabitDoneOnDate(p.habitStore, habit.ID, today) {
 			done++
 		}
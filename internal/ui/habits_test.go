@@ -1,10 +1,14 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"today/internal/config"
 	"today/internal/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func freezeHabitsNow(t *testing.T, store *storage.Storage) {
@@ -38,9 +42,9 @@ func TestHabitsPaneView_WithHabits(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add some habits
-	store.AddHabit("Exercise", "🏃")
-	store.AddHabit("Reading", "📚")
-	store.AddHabit("Meditation", "🧘")
+	store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.AddHabit("Reading", "📚", storage.FrequencyDaily)
+	store.AddHabit("Meditation", "🧘", storage.FrequencyDaily)
 
 	pane := NewHabitsPane(store, createTestStyles())
 	pane.SetSize(40, 20)
@@ -60,8 +64,8 @@ func TestHabitsPaneView_WithCompletion(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add habits
-	habit1, _ := store.AddHabit("Exercise", "🏃")
-	_, _ = store.AddHabit("Reading", "📚")
+	habit1, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	_, _ = store.AddHabit("Reading", "📚", storage.FrequencyDaily)
 
 	// Complete one for today
 	store.ToggleHabitToday(habit1.ID)
@@ -83,7 +87,7 @@ func TestHabitsPaneView_WithStreak(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add a habit
-	habit, _ := store.AddHabit("Exercise", "🏃")
+	habit, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
 
 	// Create a multi-day streak
 	habitStore, _ := store.LoadHabits()
@@ -114,7 +118,7 @@ func TestHabitsPaneView_Unfocused(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add a habit
-	store.AddHabit("Exercise", "🏃")
+	store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
 
 	pane := NewHabitsPane(store, createTestStyles())
 	pane.SetSize(40, 20)
@@ -133,8 +137,8 @@ func TestHabitsPaneView_Narrow(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add habits
-	store.AddHabit("Exercise", "🏃")
-	store.AddHabit("Read", "📚")
+	store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.AddHabit("Read", "📚", storage.FrequencyDaily)
 
 	pane := NewHabitsPane(store, createTestStyles())
 	pane.SetSize(25, 15) // Narrow terminal
@@ -153,9 +157,9 @@ func TestHabitsPane_Navigation(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add habits
-	store.AddHabit("Habit 1", "1️⃣")
-	store.AddHabit("Habit 2", "2️⃣")
-	store.AddHabit("Habit 3", "3️⃣")
+	store.AddHabit("Habit 1", "1️⃣", storage.FrequencyDaily)
+	store.AddHabit("Habit 2", "2️⃣", storage.FrequencyDaily)
+	store.AddHabit("Habit 3", "3️⃣", storage.FrequencyDaily)
 
 	pane := NewHabitsPane(store, createTestStyles())
 	pane.SetSize(40, 20)
@@ -203,9 +207,9 @@ func TestHabitsPane_GetTodayCompletionRate(t *testing.T) {
 	}
 
 	// Add habits
-	habit1, _ := store.AddHabit("Habit 1", "1️⃣")
-	habit2, _ := store.AddHabit("Habit 2", "2️⃣")
-	habit3, _ := store.AddHabit("Habit 3", "3️⃣")
+	habit1, _ := store.AddHabit("Habit 1", "1️⃣", storage.FrequencyDaily)
+	habit2, _ := store.AddHabit("Habit 2", "2️⃣", storage.FrequencyDaily)
+	habit3, _ := store.AddHabit("Habit 3", "3️⃣", storage.FrequencyDaily)
 
 	// Complete 2 out of 3
 	store.ToggleHabitToday(habit1.ID)
@@ -236,7 +240,7 @@ func TestHabitsPane_WeekVisualization(t *testing.T) {
 	freezeHabitsNow(t, store)
 
 	// Add a habit
-	habit, _ := store.AddHabit("Test Habit", "✓")
+	habit, _ := store.AddHabit("Test Habit", "✓", storage.FrequencyDaily)
 
 	// Complete for specific days this week
 	habitStore, _ := store.LoadHabits()
@@ -299,7 +303,268 @@ func TestHabitsPane_AddMode(t *testing.T) {
 	if pane.addStep != 0 {
 		t.Error("addStep should be 0 after reset")
 	}
-	if pane.newName != "" {
-		t.Error("newName should be empty after reset")
+	if pane.draft.Name != "" {
+		t.Error("draft should be empty after reset")
+	}
+}
+
+func TestHabitsPaneView_Schedule(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	freezeHabitsNow(t, store)
+
+	store.AddHabit("Standup", "🗣", storage.FrequencyWeekdays)
+	store.AddHabit("Long run", "🏃", storage.FrequencyCustom, 0, 3)
+	store.AddHabit("Review", "📝", storage.FrequencyWeekly)
+
+	// Standup done every weekday and once on Saturday; the review on Friday
+	habitStore, _ := store.LoadHabits()
+	for _, date := range []string{"2025-12-09", "2025-12-10", "2025-12-11", "2025-12-12", "2025-12-13", "2025-12-15"} {
+		store.SetHabitDoneOnDate(habitStore.Habits[0].ID, date, true)
+	}
+	store.SetHabitDoneOnDate(habitStore.Habits[2].ID, "2025-12-12", true)
+
+	pane := NewHabitsPane(store, createTestStyles())
+	pane.SetSize(40, 20)
+	pane.SetFocused(true)
+
+	habitStore, _ = store.LoadHabits()
+	pane.setHabitStore(habitStore)
+
+	output := pane.View()
+	assertGolden(t, "habits_pane_schedule", output)
+}
+
+func TestHabitsPaneView_Target(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	freezeHabitsNow(t, store)
+
+	habit, _ := store.CreateHabit(storage.Habit{Name: "Water", Icon: "🥤", Target: 8, Unit: "glasses"})
+
+	// Done twice, partial progress on two other days and today
+	for date, value := range map[string]int{"2025-12-10": 8, "2025-12-11": 3, "2025-12-13": 10, "2025-12-14": 1, "2025-12-15": 5} {
+		store.SetHabitValueOnDate(habit.ID, date, value)
+	}
+
+	pane := NewHabitsPane(store, createTestStyles())
+	pane.SetSize(50, 20)
+	pane.SetFocused(true)
+
+	habitStore, _ := store.LoadHabits()
+	pane.setHabitStore(habitStore)
+
+	output := pane.View()
+	assertGolden(t, "habits_pane_target", output)
+}
+
+func TestHabitsPaneView_Skipped(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	freezeHabitsNow(t, store)
+
+	habit, _ := store.AddHabit("Run", "🏃", storage.FrequencyDaily)
+
+	// Done Tuesday and Wednesday, skipped Thursday, on vacation since Saturday
+	store.SetHabitDoneOnDate(habit.ID, "2025-12-09", true)
+	store.SetHabitDoneOnDate(habit.ID, "2025-12-10", true)
+	store.SetHabitSkippedOnDate(habit.ID, "2025-12-11", true)
+	store.SetHabitDoneOnDate(habit.ID, "2025-12-12", true)
+	store.SetVacation(&storage.DateRange{Start: "2025-12-13", End: "2025-12-21"})
+
+	pane := NewHabitsPane(store, createTestStyles())
+	pane.SetSize(50, 20)
+	pane.SetFocused(true)
+
+	habitStore, _ := store.LoadHabits()
+	pane.setHabitStore(habitStore)
+
+	output := pane.View()
+	assertGolden(t, "habits_pane_skipped", output)
+}
+
+func TestHabitsPane_AddWithFrequency(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	pane := NewHabitsPane(store, createTestStyles())
+	pane.SetFocused(true)
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	// Name, icon, frequency
+	pane.input.SetValue("Swim")
+	pane.Update(enter)
+	pane.input.SetValue("🏊")
+	pane.Update(enter)
+	pane.input.SetValue("mon,funday")
+	pane.Update(enter)
+	if pane.formErr == "" || pane.addStep != habitStepFrequency {
+		t.Fatal("invalid frequency should keep the form on the frequency step")
+	}
+	pane.input.SetValue("thu, mon")
+	pane.Update(enter)
+	// No target
+	cmd := pane.Update(enter)
+
+	if pane.IsAdding() || cmd == nil {
+		t.Fatal("finishing the form should close it and add the habit")
+	}
+	if msg, ok := cmd().(habitAddedMsg); !ok || msg.err != nil {
+		t.Fatalf("command returned %+v, want habitAddedMsg without error", msg)
+	}
+
+	habits, _ := store.LoadHabits()
+	got := habits.Habits[0]
+	if got.Name != "Swim" || got.Frequency != storage.FrequencyCustom || got.ScheduleString() != "mon,thu" {
+		t.Errorf("persisted habit = %+v, want Swim on mon,thu", got)
+	}
+}
+
+func TestHabitsPane_EditHabit(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.ToggleHabitToday(mustFirstHabit(t, store).ID)
+
+	pane := NewHabitsPane(store, createTestStyles())
+	pane.SetFocused(true)
+	habitStore, _ := store.LoadHabits()
+	pane.setHabitStore(habitStore)
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if !pane.IsEditing() {
+		t.Fatal("IsEditing() = false, want true after pressing 'e'")
+	}
+	if got := pane.input.Value(); got != "Exercise" {
+		t.Errorf("input prefilled with %q, want habit name", got)
+	}
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	pane.input.SetValue("Workout")
+	pane.Update(enter)
+	if got := pane.input.Value(); got != "🏃" {
+		t.Errorf("icon prefilled with %q, want habit icon", got)
+	}
+	pane.Update(enter)
+	if got := pane.input.Value(); got != "daily" {
+		t.Errorf("frequency prefilled with %q, want daily", got)
+	}
+	pane.input.SetValue("weekdays")
+	pane.Update(enter)
+	if got := pane.input.Value(); got != "" {
+		t.Errorf("target prefilled with %q, want none", got)
+	}
+	cmd := pane.Update(enter)
+	if cmd == nil {
+		t.Fatal("expected an update command after finishing the form")
+	}
+	msg, ok := cmd().(habitUpdatedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("command returned %+v, want habitUpdatedMsg without error", msg)
+	}
+	if msg.before.Name != "Exercise" {
+		t.Errorf("before.Name = %q, want original name", msg.before.Name)
+	}
+
+	habits, _ := store.LoadHabits()
+	got := habits.Habits[0]
+	if got.Name != "Workout" || got.Frequency != storage.FrequencyWeekdays {
+		t.Errorf("persisted habit = %+v, want Workout on weekdays", got)
+	}
+	if len(habits.Logs) != 1 {
+		t.Errorf("logs = %v, want the completion kept", habits.Logs)
+	}
+
+	// An edit that changes nothing saves nothing
+	pane.setHabitStore(habits)
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	pane.Update(enter)
+	pane.Update(enter)
+	pane.Update(enter)
+	if cmd := pane.Update(enter); cmd != nil {
+		t.Error("unchanged edit should not return a command")
+	}
+}
+
+func TestHabitsPane_LogTarget(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	pane := NewHabitsPane(store, createTestStyles())
+	pane.SetFocused(true)
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	pane.input.SetValue("Water")
+	pane.Update(enter)
+	pane.input.SetValue("🥤")
+	pane.Update(enter)
+	pane.Update(enter)
+	pane.input.SetValue("lots")
+	pane.Update(enter)
+	if pane.formErr == "" || pane.addStep != habitStepTarget {
+		t.Fatal("invalid target should keep the form on the target step")
+	}
+	pane.input.SetValue("3 glasses")
+	if msg, ok := pane.Update(enter)().(habitAddedMsg); !ok || msg.err != nil {
+		t.Fatalf("command returned %+v, want habitAddedMsg without error", msg)
+	}
+
+	habit := mustFirstHabit(t, store)
+	if habit.Target != 3 || habit.Unit != "glasses" {
+		t.Fatalf("persisted habit = %+v, want a target of 3 glasses", habit)
+	}
+	habitStore, _ := store.LoadHabits()
+	pane.setHabitStore(habitStore)
+
+	// Two glasses are partial progress; the third completes the habit
+	plus := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}}
+	for i, want := range []int{1, 2, 3} {
+		msg, ok := pane.Update(plus)().(habitLoggedMsg)
+		if !ok || msg.err != nil || msg.before != i || msg.after != want {
+			t.Fatalf("'+' returned %+v, want %d → %d", msg, i, want)
+		}
+		habitStore, _ = store.LoadHabits()
+		today := store.Now().Format("2006-01-02")
+		if done := store.IsHabitDoneOnDate(habitStore, habit.ID, today); done != (want == 3) {
+			t.Errorf("done after logging %d = %v", want, done)
+		}
+	}
+	pane.setHabitStore(habitStore)
+	if got := pane.View(); !strings.Contains(got, "3/3 glasses") {
+		t.Errorf("view should show today's progress, got:\n%s", got)
+	}
+
+	minus := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}}
+	if msg := pane.Update(minus)().(habitLoggedMsg); msg.after != 2 {
+		t.Errorf("'-' logged %d, want 2", msg.after)
+	}
+}
+
+func TestHabitsPaneView_AllInactive(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	habit, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.SetHabitState(habit.ID, storage.HabitPaused)
+
+	pane := NewHabitsPaneWithKeys(store, createTestStyles(), &config.KeysConfig{InactiveHabits: "v"})
+	pane.SetSize(50, 20)
+	habitStore, _ := store.LoadHabits()
+	pane.setHabitStore(habitStore)
+
+	if got := pane.View(); !strings.Contains(got, "Press 'v' to restore one.") {
+		t.Errorf("hint should name the remapped key, got:\n%s", got)
+	}
+}
+
+func mustFirstHabit(t *testing.T, store *storage.Storage) storage.Habit {
+	t.Helper()
+	habits, err := store.LoadHabits()
+	if err != nil || len(habits.Habits) == 0 {
+		t.Fatalf("LoadHabits() = %v, %v; want a habit", habits, err)
 	}
+	return habits.Habits[0]
 }
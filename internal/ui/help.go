@@ -73,6 +73,9 @@ func (h *HelpOverlay) View() string {
 	b.WriteString(keyStyle.Render("Tab") + descStyle.Render("Switch pane") + "\n")
 	b.WriteString(keyStyle.Render("1 / 2 / 3") + descStyle.Render("Jump to pane") + "\n")
 	b.WriteString(keyStyle.Render("?") + descStyle.Render("Toggle help") + "\n")
+	b.WriteString(keyStyle.Render("T") + descStyle.Render("Trash (restore deleted)") + "\n")
+	b.WriteString(keyStyle.Render("L") + descStyle.Render("Recent activity") + "\n")
+	b.WriteString(keyStyle.Render("P") + descStyle.Render("Switch profile") + "\n")
 	b.WriteString(keyStyle.Render("q") + descStyle.Render("Quit") + "\n")
 
 	// Tasks
@@ -80,8 +83,18 @@ func (h *HelpOverlay) View() string {
 	b.WriteString(sectionStyle.Render("Tasks"))
 	b.WriteString("\n")
 	b.WriteString(keyStyle.Render("a") + descStyle.Render("Add task") + "\n")
+	b.WriteString(keyStyle.Render("e") + descStyle.Render("Edit task") + "\n")
 	b.WriteString(keyStyle.Render("d / Space") + descStyle.Render("Toggle done") + "\n")
 	b.WriteString(keyStyle.Render("x") + descStyle.Render("Delete task") + "\n")
+	b.WriteString(keyStyle.Render("o") + descStyle.Render("Show/hide checklist") + "\n")
+	b.WriteString(keyStyle.Render("A") + descStyle.Render("Add checklist item") + "\n")
+	b.WriteString(keyStyle.Render("#") + descStyle.Render("Filter by tag") + "\n")
+	b.WriteString(keyStyle.Render("n") + descStyle.Render("Edit notes ($EDITOR)") + "\n")
+	b.WriteString(keyStyle.Render("b") + descStyle.Render("Blocked by...") + "\n")
+	b.WriteString(keyStyle.Render("K / J") + descStyle.Render("Move task up/down") + "\n")
+	b.WriteString(keyStyle.Render("t") + descStyle.Render("Start/stop timer on task") + "\n")
+	b.WriteString(keyStyle.Render("z") + descStyle.Render("Snooze until tomorrow") + "\n")
+	b.WriteString(keyStyle.Render("H") + descStyle.Render("Show/hide later tasks") + "\n")
 	b.WriteString(keyStyle.Render("j / k") + descStyle.Render("Navigate up/down") + "\n")
 	b.WriteString(keyStyle.Render("g / G") + descStyle.Render("Go to top/bottom") + "\n")
 
@@ -97,7 +110,12 @@ func (h *HelpOverlay) View() string {
 	b.WriteString(sectionStyle.Render("Habits"))
 	b.WriteString("\n")
 	b.WriteString(keyStyle.Render("a") + descStyle.Render("Add habit") + "\n")
+	b.WriteString(keyStyle.Render("e") + descStyle.Render("Edit habit / frequency / target") + "\n")
 	b.WriteString(keyStyle.Render("Space / d") + descStyle.Render("Toggle today") + "\n")
+	b.WriteString(keyStyle.Render("+ / -") + descStyle.Render("Log one more / less") + "\n")
+	b.WriteString(keyStyle.Render("s") + descStyle.Render("Skip today") + "\n")
+	b.WriteString(keyStyle.Render("p / A") + descStyle.Render("Pause / archive habit") + "\n")
+	b.WriteString(keyStyle.Render("I") + descStyle.Render("Paused & archived habits") + "\n")
 	b.WriteString(keyStyle.Render("x") + descStyle.Render("Delete habit") + "\n")
 	b.WriteString(keyStyle.Render("j / k") + descStyle.Render("Navigate up/down") + "\n")
 
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"today/internal/storage"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// InactiveHabitsView lists paused and archived habits so they can be
+// restored to the habits pane
+type InactiveHabitsView struct {
+	width  int
+	height int
+	styles *Styles
+	keys   InactiveHabitsKeyMap
+	habits []storage.Habit
+	cursor int
+}
+
+// NewInactiveHabitsView creates a new list of paused and archived habits.
+func NewInactiveHabitsView(styles *Styles, keys InactiveHabitsKeyMap) *InactiveHabitsView {
+	return &InactiveHabitsView{
+		styles: styles,
+		keys:   keys,
+	}
+}
+
+// SetSize sets the view dimensions
+func (v *InactiveHabitsView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// SetHabits lists the paused and archived habits of store, keeping the
+// cursor in range.
+func (v *InactiveHabitsView) SetHabits(store *storage.HabitStore) {
+	v.habits = nil
+	if store != nil {
+		for _, habit := range store.Habits {
+			if !habit.IsActive() {
+				v.habits = append(v.habits, habit)
+			}
+		}
+	}
+	if v.cursor >= len(v.habits) {
+		v.cursor = len(v.habits) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// Selected returns the habit under the cursor.
+func (v *InactiveHabitsView) Selected() (storage.Habit, bool) {
+	if v.cursor < 0 || v.cursor >= len(v.habits) {
+		return storage.Habit{}, false
+	}
+	return v.habits[v.cursor], true
+}
+
+// Update handles navigation keys.
+func (v *InactiveHabitsView) Update(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, v.keys.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(msg, v.keys.Down):
+		if v.cursor < len(v.habits)-1 {
+			v.cursor++
+		}
+	case key.Matches(msg, v.keys.Top):
+		v.cursor = 0
+	case key.Matches(msg, v.keys.Bottom):
+		v.cursor = max(0, len(v.habits)-1)
+	}
+}
+
+// View renders the paused and archived habits
+func (v *InactiveHabitsView) View() string {
+	overlayWidth := 60
+	if v.width > 0 {
+		overlayWidth = min(60, max(20, v.width-4))
+	}
+
+	overlayStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(v.styles.ColorPrimary).
+		Padding(1, 2).
+		Width(overlayWidth)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(v.styles.ColorPrimary).
+		MarginBottom(1)
+
+	stateStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorAccent).
+		Width(9)
+
+	dateStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorTextMuted)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorTextMuted).
+		Italic(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Paused & archived habits (%d)", len(v.habits))))
+	b.WriteString("\n\n")
+
+	if len(v.habits) == 0 {
+		b.WriteString(mutedStyle.Render("No paused or archived habits"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor visible when the list is taller than the screen.
+	visible := len(v.habits)
+	if v.height > 0 {
+		visible = min(visible, max(1, v.height-12))
+	}
+	start := 0
+	if v.cursor >= visible {
+		start = v.cursor - visible + 1
+	}
+
+	// Content width inside border and padding, minus cursor, state and date.
+	textWidth := max(10, overlayWidth-6-2-9-13)
+	for i := start; i < start+visible && i < len(v.habits); i++ {
+		habit := v.habits[i]
+		cursor := "  "
+		textStyle := v.styles.TaskPendingStyle
+		if i == v.cursor {
+			cursor = "▸ "
+			textStyle = v.styles.TaskSelectedStyle
+		}
+		name := textStyle.Render(runewidth.FillRight(truncateText(habit.Icon+" "+habit.Name, textWidth), textWidth))
+		since := ""
+		if t, err := time.Parse("2006-01-02", habit.StateSince); err == nil {
+			since = " since " + t.Format("Jan 2")
+		}
+		b.WriteString(cursor + stateStyle.Render(string(habit.State)) + name + dateStyle.Render(since) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Restored habits keep their streaks"))
+
+	content := overlayStyle.Render(b.String())
+	return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -46,6 +46,9 @@ type GlobalKeyMap struct {
 	Pane3    key.Binding
 	Undo     key.Binding
 	Redo     key.Binding
+	Trash    key.Binding
+	Activity key.Binding
+	Profiles key.Binding
 }
 
 // DefaultGlobalKeyMap returns the default global key bindings.
@@ -91,6 +94,18 @@ func NewGlobalKeyMap(cfg *config.KeysConfig) GlobalKeyMap {
 			key.WithKeys(parseKeys(cfg.Redo, "ctrl+y")...),
 			key.WithHelp("ctrl+y", "redo"),
 		),
+		Trash: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Trash, "T")...),
+			key.WithHelp("T", "trash"),
+		),
+		Activity: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Activity, "L")...),
+			key.WithHelp("L", "activity"),
+		),
+		Profiles: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.Profiles, "P")...),
+			key.WithHelp("P", "profiles"),
+		),
 	}
 }
 
@@ -174,9 +189,20 @@ func NewInputKeyMap(cfg *config.KeysConfig) InputKeyMap {
 
 // TaskKeyMap defines keys for the task pane.
 type TaskKeyMap struct {
-	Add    key.Binding
-	Toggle key.Binding
-	Delete key.Binding
+	Add      key.Binding
+	Edit     key.Binding
+	Toggle   key.Binding
+	Delete   key.Binding
+	Expand   key.Binding // Show/hide the selected task's checklist
+	AddItem  key.Binding // Add a checklist item to the selected task
+	Filter   key.Binding // Filter the list by tag
+	Notes    key.Binding // Edit the selected task's notes in $EDITOR
+	Block    key.Binding // Choose a task that blocks the selected one
+	MoveUp   key.Binding // Move the selected task up (manual sort)
+	MoveDown key.Binding // Move the selected task down (manual sort)
+	Track    key.Binding // Start/stop the timer on the selected task
+	Snooze   key.Binding // Push the selected task's start date back a day
+	Deferred key.Binding // Show/hide tasks that have not started yet
 	NavigationKeyMap
 }
 
@@ -195,6 +221,10 @@ func NewTaskKeyMap(cfg *config.KeysConfig) TaskKeyMap {
 			key.WithKeys(parseKeys(cfg.AddTask, "a")...),
 			key.WithHelp("a", "add task"),
 		),
+		Edit: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.EditTask, "e")...),
+			key.WithHelp("e", "edit task"),
+		),
 		Toggle: key.NewBinding(
 			key.WithKeys(parseKeys(cfg.ToggleTask, "d", "enter", " ")...),
 			key.WithHelp("d/space", "toggle done"),
@@ -203,19 +233,61 @@ func NewTaskKeyMap(cfg *config.KeysConfig) TaskKeyMap {
 			key.WithKeys(parseKeys(cfg.DeleteTask, "x")...),
 			key.WithHelp("x", "delete"),
 		),
+		Expand: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.ExpandChecklist, "o")...),
+			key.WithHelp("o", "show checklist"),
+		),
+		AddItem: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.AddChecklistItem, "A")...),
+			key.WithHelp("A", "add checklist item"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.FilterTag, "#")...),
+			key.WithHelp("#", "filter by tag"),
+		),
+		Notes: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.EditNotes, "n")...),
+			key.WithHelp("n", "edit notes"),
+		),
+		Block: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.BlockTask, "b")...),
+			key.WithHelp("b", "blocked by"),
+		),
+		MoveUp: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.MoveTaskUp, "K", "shift+up")...),
+			key.WithHelp("K", "move up"),
+		),
+		MoveDown: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.MoveTaskDown, "J", "shift+down")...),
+			key.WithHelp("J", "move down"),
+		),
+		Track: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.TrackTask, "t")...),
+			key.WithHelp("t", "track time"),
+		),
+		Snooze: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.SnoozeTask, "z")...),
+			key.WithHelp("z", "snooze a day"),
+		),
+		Deferred: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.ToggleDeferred, "H")...),
+			key.WithHelp("H", "show later tasks"),
+		),
 		NavigationKeyMap: NewNavigationKeyMap(cfg),
 	}
 }
 
 // ShortHelp returns the short help for the task pane (implements help.KeyMap).
 func (k TaskKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Add, k.Toggle, k.Delete, k.Down}
+	return []key.Binding{k.Add, k.Edit, k.Toggle, k.Delete, k.Down}
 }
 
 // FullHelp returns the full help for the task pane (implements help.KeyMap).
 func (k TaskKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Add, k.Toggle, k.Delete},
+		{k.Add, k.Edit, k.Toggle, k.Delete},
+		{k.Expand, k.AddItem, k.Filter, k.Notes, k.Block},
+		{k.MoveUp, k.MoveDown, k.Track, k.Snooze, k.Deferred},
 		{k.Up, k.Down, k.Top, k.Bottom},
 	}
 }
@@ -275,9 +347,16 @@ func (k TimerKeyMap) FullHelp() [][]key.Binding {
 
 // HabitKeyMap defines keys for the habits pane.
 type HabitKeyMap struct {
-	Add    key.Binding
-	Toggle key.Binding
-	Delete key.Binding
+	Add       key.Binding
+	Edit      key.Binding
+	Toggle    key.Binding
+	Delete    key.Binding
+	Increment key.Binding
+	Decrement key.Binding
+	Skip      key.Binding
+	Pause     key.Binding
+	Archive   key.Binding
+	Inactive  key.Binding
 	NavigationKeyMap
 }
 
@@ -296,6 +375,10 @@ func NewHabitKeyMap(cfg *config.KeysConfig) HabitKeyMap {
 			key.WithKeys(parseKeys(cfg.AddHabit, "a")...),
 			key.WithHelp("a", "add habit"),
 		),
+		Edit: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.EditHabit, "e")...),
+			key.WithHelp("e", "edit habit"),
+		),
 		Toggle: key.NewBinding(
 			key.WithKeys(parseKeys(cfg.ToggleHabit, " ", "enter", "d")...),
 			key.WithHelp("space", "toggle"),
@@ -304,19 +387,45 @@ func NewHabitKeyMap(cfg *config.KeysConfig) HabitKeyMap {
 			key.WithKeys(parseKeys(cfg.DeleteHabit, "x")...),
 			key.WithHelp("x", "delete"),
 		),
+		Increment: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.IncrementHabit, "+", "=")...),
+			key.WithHelp("+", "log one more"),
+		),
+		Decrement: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.DecrementHabit, "-")...),
+			key.WithHelp("-", "log one less"),
+		),
+		Skip: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.SkipHabit, "s")...),
+			key.WithHelp("s", "skip today"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.PauseHabit, "p")...),
+			key.WithHelp("p", "pause"),
+		),
+		Archive: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.ArchiveHabit, "A")...),
+			key.WithHelp("A", "archive"),
+		),
+		Inactive: key.NewBinding(
+			key.WithKeys(parseKeys(cfg.InactiveHabits, "I")...),
+			key.WithHelp("I", "paused/archived"),
+		),
 		NavigationKeyMap: NewNavigationKeyMap(cfg),
 	}
 }
 
 // ShortHelp returns the short help for the habit pane (implements help.KeyMap).
 func (k HabitKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Add, k.Toggle, k.Delete, k.Down}
+	return []key.Binding{k.Add, k.Edit, k.Toggle, k.Delete, k.Down}
 }
 
 // FullHelp returns the full help for the habit pane (implements help.KeyMap).
 func (k HabitKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Add, k.Toggle, k.Delete},
+		{k.Add, k.Edit, k.Toggle, k.Delete},
+		{k.Increment, k.Decrement, k.Skip},
+		{k.Pause, k.Archive, k.Inactive},
 		{k.Up, k.Down, k.Top, k.Bottom},
 	}
 }
@@ -339,3 +448,143 @@ func DefaultHelpKeyMap() HelpKeyMap {
 		),
 	}
 }
+
+// =============================================================================
+// Trash View Keys
+// =============================================================================
+
+// TrashKeyMap defines keys for the trash view.
+type TrashKeyMap struct {
+	Restore key.Binding
+	Purge   key.Binding
+	Close   key.Binding
+	NavigationKeyMap
+}
+
+// DefaultTrashKeyMap returns the default trash view key bindings.
+func DefaultTrashKeyMap() TrashKeyMap {
+	return NewTrashKeyMap(&config.KeysConfig{})
+}
+
+// NewTrashKeyMap creates trash view key bindings from config.
+// Only navigation is configurable; the view is modal so its keys never clash.
+func NewTrashKeyMap(cfg *config.KeysConfig) TrashKeyMap {
+	if cfg == nil {
+		cfg = &config.KeysConfig{}
+	}
+	return TrashKeyMap{
+		Restore: key.NewBinding(
+			key.WithKeys("r", "enter"),
+			key.WithHelp("r/enter", "restore"),
+		),
+		Purge: key.NewBinding(
+			key.WithKeys("x", "delete"),
+			key.WithHelp("x", "purge"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "close"),
+		),
+		NavigationKeyMap: NewNavigationKeyMap(cfg),
+	}
+}
+
+// =============================================================================
+// Activity View Keys
+// =============================================================================
+
+// ActivityKeyMap defines keys for the activity view.
+type ActivityKeyMap struct {
+	Close key.Binding
+	NavigationKeyMap
+}
+
+// DefaultActivityKeyMap returns the default activity view key bindings.
+func DefaultActivityKeyMap() ActivityKeyMap {
+	return NewActivityKeyMap(&config.KeysConfig{})
+}
+
+// NewActivityKeyMap creates activity view key bindings from config.
+// Only navigation is configurable; the view is modal so its keys never clash.
+func NewActivityKeyMap(cfg *config.KeysConfig) ActivityKeyMap {
+	if cfg == nil {
+		cfg = &config.KeysConfig{}
+	}
+	return ActivityKeyMap{
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "close"),
+		),
+		NavigationKeyMap: NewNavigationKeyMap(cfg),
+	}
+}
+
+// =============================================================================
+// Profile View Keys
+// =============================================================================
+
+// ProfileKeyMap defines keys for the profile picker.
+type ProfileKeyMap struct {
+	Select key.Binding
+	Close  key.Binding
+	NavigationKeyMap
+}
+
+// DefaultProfileKeyMap returns the default profile picker key bindings.
+func DefaultProfileKeyMap() ProfileKeyMap {
+	return NewProfileKeyMap(&config.KeysConfig{})
+}
+
+// NewProfileKeyMap creates profile picker key bindings from config.
+// Only navigation is configurable; the view is modal so its keys never clash.
+func NewProfileKeyMap(cfg *config.KeysConfig) ProfileKeyMap {
+	if cfg == nil {
+		cfg = &config.KeysConfig{}
+	}
+	return ProfileKeyMap{
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "switch"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "close"),
+		),
+		NavigationKeyMap: NewNavigationKeyMap(cfg),
+	}
+}
+
+// =============================================================================
+// Inactive Habits View Keys
+// =============================================================================
+
+// InactiveHabitsKeyMap defines keys for the paused and archived habits list.
+type InactiveHabitsKeyMap struct {
+	Restore key.Binding
+	Close   key.Binding
+	NavigationKeyMap
+}
+
+// DefaultInactiveHabitsKeyMap returns the default inactive habits key bindings.
+func DefaultInactiveHabitsKeyMap() InactiveHabitsKeyMap {
+	return NewInactiveHabitsKeyMap(&config.KeysConfig{})
+}
+
+// NewInactiveHabitsKeyMap creates inactive habits key bindings from config.
+// Only navigation is configurable; the view is modal so its keys never clash.
+func NewInactiveHabitsKeyMap(cfg *config.KeysConfig) InactiveHabitsKeyMap {
+	if cfg == nil {
+		cfg = &config.KeysConfig{}
+	}
+	return InactiveHabitsKeyMap{
+		Restore: key.NewBinding(
+			key.WithKeys("r", "enter"),
+			key.WithHelp("r/enter", "restore"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "close"),
+		),
+		NavigationKeyMap: NewNavigationKeyMap(cfg),
+	}
+}
@@ -31,8 +31,9 @@ type redoResultMsg struct {
 
 // tasksLoadedMsg is sent when tasks are loaded from storage.
 type tasksLoadedMsg struct {
-	tasks []storage.Task
-	err   error
+	tasks         []storage.Task
+	err           error
+	keepSelection bool // Keep the cursor on the selected task (external reloads)
 }
 
 // taskAddedMsg is sent when a new task is created.
@@ -41,6 +42,20 @@ type taskAddedMsg struct {
 	err  error
 }
 
+// taskUpdatedMsg is sent when a task's fields are edited.
+type taskUpdatedMsg struct {
+	before storage.Task // Task as it was before the edit (for undo)
+	after  storage.Task // Task as submitted by the edit form (for redo)
+	err    error
+}
+
+// taskSnoozedMsg is sent when a task's start date is pushed forward.
+type taskSnoozedMsg struct {
+	before storage.Task // Task before snoozing (for undo)
+	after  storage.Task // Task with the new start date (for redo)
+	err    error
+}
+
 // taskCompletedMsg is sent when a task is marked as done.
 type taskCompletedMsg struct {
 	id   string
@@ -62,6 +77,52 @@ type taskDeletedMsg struct {
 	err  error
 }
 
+// checklistItemAddedMsg is sent when a checklist item is added to a task.
+type checklistItemAddedMsg struct {
+	taskID string
+	item   *storage.ChecklistItem
+	err    error
+}
+
+// checklistItemToggledMsg is sent when a checklist item is checked or unchecked.
+type checklistItemToggledMsg struct {
+	taskID string
+	item   storage.ChecklistItem // Item as it was before toggling
+	done   bool                  // New done state
+	err    error
+}
+
+// checklistItemDeletedMsg is sent when a checklist item is removed.
+type checklistItemDeletedMsg struct {
+	taskID string
+	item   storage.ChecklistItem // Removed item for restoration on undo
+	index  int                   // Position the item was removed from
+	err    error
+}
+
+// blockerChangedMsg is sent when a "blocked by" relation is added or removed.
+type blockerChangedMsg struct {
+	taskID    string
+	blockerID string
+	text      string // Blocked task's text for undo description
+	added     bool
+	err       error
+}
+
+// tasksReorderedMsg is sent when a task is moved in the manual order.
+type tasksReorderedMsg struct {
+	before []string // Task IDs in their previous order (for undo)
+	after  []string // Task IDs in their new order (for redo)
+	text   string   // Moved task's text for undo description
+	err    error
+}
+
+// notesEditorMsg is sent when the notes editor could not be run or its
+// output could not be read back. Successful edits arrive as taskUpdatedMsg.
+type notesEditorMsg struct {
+	err error
+}
+
 // =============================================================================
 // Timer Messages
 // =============================================================================
@@ -89,8 +150,9 @@ type timerStoppedMsg struct {
 
 // habitsLoadedMsg is sent when habits are loaded from storage.
 type habitsLoadedMsg struct {
-	store *storage.HabitStore
-	err   error
+	store         *storage.HabitStore
+	err           error
+	keepSelection bool // Keep the cursor on the selected habit (external reloads)
 }
 
 // habitAddedMsg is sent when a new habit is created.
@@ -99,6 +161,20 @@ type habitAddedMsg struct {
 	err   error
 }
 
+// habitUpdatedMsg is sent when a habit's name, icon or frequency is edited.
+type habitUpdatedMsg struct {
+	before storage.Habit
+	after  storage.Habit
+	err    error
+}
+
+// habitStateChangedMsg is sent when a habit is paused, archived or resumed.
+type habitStateChangedMsg struct {
+	before storage.Habit
+	after  storage.Habit
+	err    error
+}
+
 // habitToggledMsg is sent when a habit's completion status is toggled for today.
 type habitToggledMsg struct {
 	id           string
@@ -106,9 +182,30 @@ type habitToggledMsg struct {
 	date         string // YYYY-MM-DD date toggled (for correct undo after midnight)
 	isDone       bool
 	wasCompleted bool // Previous state for undo
+	wasValue     int  // Amount logged before the toggle, for undo
 	err          error
 }
 
+// habitLoggedMsg is sent when the amount logged for a habit today changes.
+type habitLoggedMsg struct {
+	habit  storage.Habit
+	date   string // YYYY-MM-DD date logged (for correct undo after midnight)
+	before int
+	after  int
+	err    error
+}
+
+// habitSkippedMsg is sent when a habit is skipped for today, or its skip
+// mark removed.
+type habitSkippedMsg struct {
+	id       string
+	name     string // Habit name for undo description
+	date     string // YYYY-MM-DD date skipped (for correct undo after midnight)
+	skipped  bool
+	wasValue int // Amount logged before skipping, for undo
+	err      error
+}
+
 // habitDeletedMsg is sent when a habit is removed.
 type habitDeletedMsg struct {
 	id    string
@@ -117,6 +214,40 @@ type habitDeletedMsg struct {
 	err   error
 }
 
+// =============================================================================
+// Trash Messages
+// =============================================================================
+
+// trashLoadedMsg is sent when the trash is loaded from storage.
+type trashLoadedMsg struct {
+	items []storage.TrashItem
+	err   error
+}
+
+// activityLoadedMsg is sent when recent journal entries are loaded.
+type activityLoadedMsg struct {
+	entries []storage.ActivityEntry
+	err     error
+}
+
+// activityErrorMsg is sent when a saved change could not be recorded in the
+// activity journal.
+type activityErrorMsg struct {
+	err error
+}
+
+// trashRestoredMsg is sent when a deleted task or habit is restored.
+type trashRestoredMsg struct {
+	item *storage.TrashItem
+	err  error
+}
+
+// trashPurgedMsg is sent when an item is permanently deleted from the trash.
+type trashPurgedMsg struct {
+	item storage.TrashItem
+	err  error
+}
+
 // =============================================================================
 // Sync Messages
 // =============================================================================
@@ -126,3 +257,19 @@ type syncStatusMsg struct {
 	status *sync.Status
 	err    error
 }
+
+// instancesMsg is sent after this process re-registers itself, listing the
+// other today processes that have the same data open.
+type instancesMsg struct {
+	others []storage.Instance
+	err    error
+}
+
+// dataChangedMsg is sent after polling the data files, listing the ones
+// changed outside this window.
+type dataChangedMsg struct {
+	files []string
+}
+
+// instanceTickMsg triggers the next instance heartbeat.
+type instanceTickMsg struct{}
@@ -191,9 +191,9 @@ func TestHabitsPane_MouseSelection(t *testing.T) {
 	pane := NewHabitsPane(store, styles)
 
 	// Add some habits
-	store.AddHabit("Exercise", "🏃")
-	store.AddHabit("Read", "📚")
-	store.AddHabit("Meditate", "🧘")
+	store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.AddHabit("Read", "📚", storage.FrequencyDaily)
+	store.AddHabit("Meditate", "🧘", storage.FrequencyDaily)
 
 	// Load habits
 	habits, _ := store.LoadHabits()
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// ProfileView lists the configured profiles so the app can switch to
+// another one without restarting
+type ProfileView struct {
+	width    int
+	height   int
+	styles   *Styles
+	keys     ProfileKeyMap
+	profiles []string
+	current  string
+	cursor   int
+}
+
+// NewProfileView creates a new profile picker. current is the active
+// profile, "" for the default one.
+func NewProfileView(styles *Styles, keys ProfileKeyMap, profiles []string, current string) *ProfileView {
+	if current == "" && len(profiles) > 0 {
+		current = profiles[0]
+	}
+	return &ProfileView{
+		styles:   styles,
+		keys:     keys,
+		profiles: profiles,
+		current:  current,
+	}
+}
+
+// SetSize sets the view dimensions
+func (v *ProfileView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// Reset selects the active profile.
+func (v *ProfileView) Reset() {
+	v.cursor = 0
+	for i, name := range v.profiles {
+		if name == v.current {
+			v.cursor = i
+		}
+	}
+}
+
+// Selected returns the profile under the cursor, or "" if the active one
+// is selected.
+func (v *ProfileView) Selected() string {
+	if v.cursor < 0 || v.cursor >= len(v.profiles) || v.profiles[v.cursor] == v.current {
+		return ""
+	}
+	return v.profiles[v.cursor]
+}
+
+// Update handles navigation keys.
+func (v *ProfileView) Update(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, v.keys.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(msg, v.keys.Down):
+		if v.cursor < len(v.profiles)-1 {
+			v.cursor++
+		}
+	case key.Matches(msg, v.keys.Top):
+		v.cursor = 0
+	case key.Matches(msg, v.keys.Bottom):
+		v.cursor = max(0, len(v.profiles)-1)
+	}
+}
+
+// View renders the profile list
+func (v *ProfileView) View() string {
+	overlayWidth := 50
+	if v.width > 0 {
+		overlayWidth = min(50, max(20, v.width-4))
+	}
+
+	overlayStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(v.styles.ColorPrimary).
+		Padding(1, 2).
+		Width(overlayWidth)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(v.styles.ColorPrimary).
+		MarginBottom(1)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(v.styles.ColorTextMuted).
+		Italic(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Profiles"))
+	b.WriteString("\n\n")
+
+	// Content width inside border and padding, minus cursor and marker.
+	textWidth := max(10, overlayWidth-6-2-10)
+	for i, name := range v.profiles {
+		cursor := "  "
+		textStyle := v.styles.TaskPendingStyle
+		if i == v.cursor {
+			cursor = "▸ "
+			textStyle = v.styles.TaskSelectedStyle
+		}
+		line := cursor + textStyle.Render(runewidth.FillRight(truncateText(name, textWidth), textWidth))
+		if name == v.current {
+			line += mutedStyle.Render(" (active)")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(v.profiles) <= 1 {
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("Add profiles under profiles: in config.yaml"))
+	}
+
+	content := overlayStyle.Render(strings.TrimSuffix(b.String(), "\n"))
+	return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -44,8 +44,23 @@ type Styles struct {
 	DueDateTodayStyle   lipgloss.Style
 	DueDateFutureStyle  lipgloss.Style
 
+	// Recurring task marker
+	RecurringStyle lipgloss.Style
+
+	// Task tags ("#work")
+	TagStyle lipgloss.Style
+
+	// Notes preview beneath the selected task
+	NotesStyle lipgloss.Style
+
+	// Tasks waiting on an unfinished blocker
+	BlockedStyle lipgloss.Style
+
 	HabitDoneIcon   string
 	HabitUndoneIcon string
+	HabitOffIcon    string // Days a habit is not due on
+	HabitPartialIcon string // Days with progress short of the habit's target
+	HabitSkipIcon    string // Days a habit was skipped or on vacation
 	HabitStreakStyle lipgloss.Style
 
 	TimerRunningStyle lipgloss.Style
@@ -181,9 +196,26 @@ func (s *Styles) initComponentStyles() {
 	s.DueDateFutureStyle = lipgloss.NewStyle().
 		Foreground(s.ColorTextMuted)
 
+	s.RecurringStyle = lipgloss.NewStyle().
+		Foreground(s.ColorAccent)
+
+	s.TagStyle = lipgloss.NewStyle().
+		Foreground(s.ColorSecondary)
+
+	s.NotesStyle = lipgloss.NewStyle().
+		Foreground(s.ColorTextMuted).
+		Italic(true)
+
+	s.BlockedStyle = lipgloss.NewStyle().
+		Foreground(s.ColorTextMuted).
+		Faint(true)
+
 	// Habit styles
 	s.HabitDoneIcon = lipgloss.NewStyle().Foreground(s.ColorSuccess).Render("●")
 	s.HabitUndoneIcon = lipgloss.NewStyle().Foreground(s.ColorMuted).Render("○")
+	s.HabitOffIcon = lipgloss.NewStyle().Foreground(s.ColorMuted).Render("·")
+	s.HabitPartialIcon = lipgloss.NewStyle().Foreground(s.ColorSuccess).Render("◐")
+	s.HabitSkipIcon = lipgloss.NewStyle().Foreground(s.ColorWarning).Render("⊘")
 
 	s.HabitStreakStyle = lipgloss.NewStyle().
 		Foreground(s.ColorWarning).
@@ -3,6 +3,7 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,7 +19,8 @@ import (
 
 // TaskPane handles the task list display and interactions.
 type TaskPane struct {
-	tasks   []storage.Task
+	tasks   []storage.Task // Visible tasks (after the tag filter)
+	all     []storage.Task // All tasks, sorted
 	cursor  int
 	focused bool
 	width   int
@@ -28,11 +30,60 @@ type TaskPane struct {
 	storage *storage.Storage
 	styles  *Styles
 
+	// Edit mode state: the form walks through one field per step.
+	editing   bool
+	editStep  int
+	editOrig  storage.Task // Snapshot before editing (for undo)
+	editDraft storage.Task // Accumulates the edited fields
+	editErr   string       // Validation error for the current step
+
+	// Checklist state: which tasks show their items, and which item of the
+	// selected task is selected (-1 when the task line itself is selected).
+	expanded   map[string]bool
+	itemCursor int
+	addingItem bool
+	itemTaskID string // Task receiving new checklist items while addingItem
+
+	// Tag filter: only tasks carrying tagFilter are shown when it is set.
+	tagFilter string
+	filtering bool // Typing a tag to filter by
+
+	// Deferred tasks (start date after today) are hidden unless showDeferred
+	// is set; hiddenDeferred counts the ones left out of the list.
+	showDeferred   bool
+	hiddenDeferred int
+
+	// Dependency linking: choosing the task that blocks linkTaskID.
+	linking    bool
+	linkTaskID string
+
+	// Manual ordering: tasks keep the user's order instead of being sorted
+	// by priority and due date, and can be dragged with the mouse.
+	manualSort bool
+	dragTaskID string // Task pressed with the mouse, moved on release
+
+	// Time tracking: finished time per task ID plus the running timer, shown
+	// next to each task.
+	taskTime map[string]time.Duration
+	timer    *storage.CurrentTimer
+
 	// Key bindings
 	keys      TaskKeyMap
 	inputKeys InputKeyMap
 }
 
+// Edit form steps, in the order they are presented.
+const (
+	editStepText = iota
+	editStepProject
+	editStepTags
+	editStepPriority
+	editStepDue
+	editStepStart
+	editStepRepeat
+	editStepCount
+)
+
 // NewTaskPane creates a new task pane.
 func NewTaskPane(store *storage.Storage, styles *Styles) *TaskPane {
 	return NewTaskPaneWithKeys(store, styles, &config.KeysConfig{})
@@ -49,14 +100,16 @@ func NewTaskPaneWithKeys(store *storage.Storage, styles *Styles, keyCfg *config.
 	ti.Width = 40
 
 	return &TaskPane{
-		tasks:     []storage.Task{},
-		cursor:    0,
-		focused:   true,
-		input:     ti,
-		storage:   store,
-		styles:    styles,
-		keys:      NewTaskKeyMap(keyCfg),
-		inputKeys: NewInputKeyMap(keyCfg),
+		tasks:      []storage.Task{},
+		cursor:     0,
+		focused:    true,
+		input:      ti,
+		storage:    store,
+		styles:     styles,
+		expanded:   make(map[string]bool),
+		itemCursor: -1,
+		keys:       NewTaskKeyMap(keyCfg),
+		inputKeys:  NewInputKeyMap(keyCfg),
 	}
 }
 
@@ -65,13 +118,92 @@ func (p *TaskPane) LoadTasksCmd() tea.Cmd {
 	return loadTasksCmd(p.storage)
 }
 
+// SetManualSort switches between the user's manual order and automatic
+// sorting by priority and due date.
+func (p *TaskPane) SetManualSort(manual bool) {
+	p.manualSort = manual
+	if p.all != nil {
+		p.setTasks(p.all)
+	}
+}
+
+// SetTimerStore updates the time tracked on each task.
+func (p *TaskPane) SetTimerStore(store *storage.TimerStore) {
+	p.taskTime = storage.TaskTotals(store.Entries)
+	p.timer = store.Current
+}
+
+// trackedTime returns the time tracked on a task, including the running
+// timer, and whether that timer is tracking the task.
+func (p *TaskPane) trackedTime(taskID string) (time.Duration, bool) {
+	total := p.taskTime[taskID]
+	running := p.timer != nil && p.timer.TaskID == taskID
+	if running {
+		total += p.storage.Now().Sub(p.timer.StartedAt)
+	}
+	return total, running
+}
+
 // setTasks updates the task list, sorts it, and adjusts cursor bounds.
 func (p *TaskPane) setTasks(tasks []storage.Task) {
-	// Sort tasks by priority and due date
-	p.tasks = p.storage.SortTasks(tasks)
+	if p.manualSort {
+		p.all = p.storage.SortTasksManual(tasks)
+	} else {
+		// Sort tasks by priority and due date
+		p.all = p.storage.SortTasks(tasks)
+	}
+	p.applyFilter()
+}
+
+// reloadTasks replaces the tasks like setTasks, but keeps the cursor on the
+// selected task if it still exists (it may have moved in the list).
+func (p *TaskPane) reloadTasks(tasks []storage.Task) {
+	var selected string
+	if p.cursor >= 0 && p.cursor < len(p.tasks) {
+		selected = p.tasks[p.cursor].ID
+	}
+	p.setTasks(tasks)
+	for i, task := range p.tasks {
+		if task.ID == selected {
+			p.cursor = i
+			break
+		}
+	}
+	p.clampItemCursor()
+}
+
+// applyFilter rebuilds the visible list from all tasks, the tag filter and
+// whether deferred tasks are shown.
+func (p *TaskPane) applyFilter() {
+	now := p.storage.Now()
+	p.tasks = make([]storage.Task, 0, len(p.all))
+	p.hiddenDeferred = 0
+	for _, task := range p.all {
+		if p.tagFilter != "" && !task.HasTag(p.tagFilter) {
+			continue
+		}
+		if !p.showDeferred && task.IsDeferred(now) {
+			p.hiddenDeferred++
+			continue
+		}
+		p.tasks = append(p.tasks, task)
+	}
 	if p.cursor >= len(p.tasks) {
 		p.cursor = max(0, len(p.tasks)-1)
 	}
+	p.clampItemCursor()
+}
+
+// clampItemCursor keeps the checklist selection valid after the list changes.
+func (p *TaskPane) clampItemCursor() {
+	if p.itemCursor < 0 {
+		return
+	}
+	if p.cursor >= len(p.tasks) || !p.expanded[p.tasks[p.cursor].ID] {
+		p.itemCursor = -1
+		return
+	}
+	p.itemCursor = min(p.itemCursor, len(p.tasks[p.cursor].Checklist)-1)
 }
 
 // SetSize sets the pane dimensions.
@@ -96,6 +228,37 @@ func (p *TaskPane) IsAdding() bool {
 	return p.adding
 }
 
+// IsEditing returns whether we're in edit mode.
+func (p *TaskPane) IsEditing() bool {
+	return p.editing
+}
+
+// IsAddingItem returns whether we're adding checklist items.
+func (p *TaskPane) IsAddingItem() bool {
+	return p.addingItem
+}
+
+// IsFiltering returns whether we're typing a tag filter.
+func (p *TaskPane) IsFiltering() bool {
+	return p.filtering
+}
+
+// IsLinking returns whether we're choosing a blocker for a task.
+func (p *TaskPane) IsLinking() bool {
+	return p.linking
+}
+
+// InInputMode returns whether the pane is capturing keys (adding, editing,
+// adding checklist items, typing a tag filter or choosing a blocker).
+func (p *TaskPane) InInputMode() bool {
+	return p.adding || p.editing || p.addingItem || p.filtering || p.linking
+}
+
+// TagFilter returns the active tag filter ("" when showing all tasks).
+func (p *TaskPane) TagFilter() string {
+	return p.tagFilter
+}
+
 // Update handles messages for the task pane.
 func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
@@ -103,7 +266,9 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 	// Handle async messages first
 	switch msg := msg.(type) {
 	case tasksLoadedMsg:
-		if msg.tasks != nil {
+		if msg.tasks != nil && msg.keepSelection {
+			p.reloadTasks(msg.tasks)
+		} else if msg.tasks != nil {
 			p.setTasks(msg.tasks)
 		}
 		return nil
@@ -119,9 +284,25 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 		// Reload to refresh task state
 		return p.LoadTasksCmd()
 
+	case taskUpdatedMsg, taskSnoozedMsg:
+		// Reload to pick up edited fields (and any re-sorting)
+		return p.LoadTasksCmd()
+
 	case taskDeletedMsg:
 		// Reload to refresh list
 		return p.LoadTasksCmd()
+
+	case checklistItemAddedMsg, checklistItemToggledMsg, checklistItemDeletedMsg:
+		// Reload to refresh checklist progress
+		return p.LoadTasksCmd()
+
+	case blockerChangedMsg:
+		// Reload to refresh blocked state and ordering
+		return p.LoadTasksCmd()
+
+	case tasksReorderedMsg:
+		// Reload to pick up saved positions (or revert a failed move)
+		return p.LoadTasksCmd()
 	}
 
 	// If we're adding a task, handle input
@@ -134,8 +315,13 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 				if text != "" {
 					p.adding = false
 					p.input.Reset()
+					// "#tag" tokens become tags, unless the text is nothing but tags
+					clean, tags := storage.ExtractTags(text)
+					if clean == "" {
+						clean, tags = text, nil
+					}
 					// Return command to add task asynchronously (default priority, no due date)
-					return addTaskCmd(p.storage, text, "", storage.PriorityNone, nil)
+					return addTaskCmd(p.storage, clean, "", storage.PriorityNone, nil, tags...)
 				}
 				p.adding = false
 				p.input.Reset()
@@ -152,6 +338,117 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 		return cmd
 	}
 
+	// If we're adding checklist items, keep the input open until Esc or an
+	// empty entry so several items can be added in a row
+	if p.addingItem {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, p.inputKeys.Confirm):
+				text := strings.TrimSpace(p.input.Value())
+				p.input.Reset()
+				if text == "" {
+					p.resetAddItemMode()
+					return nil
+				}
+				return addChecklistItemCmd(p.storage, p.itemTaskID, text)
+
+			case key.Matches(msg, p.inputKeys.Cancel):
+				p.resetAddItemMode()
+				return nil
+			}
+		}
+
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+
+	// If we're typing a tag filter, Enter applies it (empty clears) and Esc
+	// leaves the current filter untouched
+	if p.filtering {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, p.inputKeys.Confirm):
+				tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(p.input.Value()), "#"))
+				p.resetFilterMode()
+				p.tagFilter = tag
+				p.cursor, p.itemCursor = 0, -1
+				p.applyFilter()
+				return nil
+
+			case key.Matches(msg, p.inputKeys.Cancel):
+				p.resetFilterMode()
+				return nil
+			}
+		}
+
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+
+	// If we're choosing a blocker, navigation moves the selection and Enter
+	// links the selected task as a blocker (or unlinks an existing one)
+	if p.linking {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			p.itemCursor = -1
+			switch {
+			case key.Matches(msg, p.inputKeys.Confirm):
+				return p.finishLinking()
+
+			case key.Matches(msg, p.inputKeys.Cancel):
+				p.linking = false
+				p.linkTaskID = ""
+
+			case key.Matches(msg, p.keys.Down):
+				p.cursor = min(p.cursor+1, max(0, len(p.tasks)-1))
+
+			case key.Matches(msg, p.keys.Up):
+				p.cursor = max(p.cursor-1, 0)
+
+			case key.Matches(msg, p.keys.Top):
+				p.cursor = 0
+
+			case key.Matches(msg, p.keys.Bottom):
+				p.cursor = max(0, len(p.tasks)-1)
+			}
+		}
+		return nil
+	}
+
+	// If we're editing a task, handle the edit form
+	if p.editing {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, p.inputKeys.Confirm):
+				if err := p.applyEditStep(); err != nil {
+					p.editErr = err.Error()
+					return nil
+				}
+				p.editErr = ""
+				p.editStep++
+				if p.editStep < editStepCount {
+					p.loadEditStep()
+					return nil
+				}
+				before, after := p.editOrig, p.editDraft
+				p.resetEditMode()
+				if taskFieldsEqual(before, after) {
+					return nil
+				}
+				return updateTaskCmd(p.storage, before, after)
+
+			case key.Matches(msg, p.inputKeys.Cancel):
+				p.resetEditMode()
+				return nil
+			}
+		}
+
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+
 	// Normal mode
 	if !p.focused {
 		return nil
@@ -164,29 +461,109 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, p.keys.Down):
-			if len(p.tasks) > 0 {
-				p.cursor = min(p.cursor+1, len(p.tasks)-1)
-			}
+			rows := p.rows()
+			p.selectRow(rows, p.selectedRow(rows)+1)
 
 		case key.Matches(msg, p.keys.Up):
-			if len(p.tasks) > 0 {
-				p.cursor = max(p.cursor-1, 0)
-			}
+			rows := p.rows()
+			p.selectRow(rows, p.selectedRow(rows)-1)
 
 		case key.Matches(msg, p.keys.Top):
-			p.cursor = 0
+			p.selectRow(p.rows(), 0)
 
 		case key.Matches(msg, p.keys.Bottom):
-			if len(p.tasks) > 0 {
-				p.cursor = len(p.tasks) - 1
-			}
+			rows := p.rows()
+			p.selectRow(rows, len(rows)-1)
 
 		case key.Matches(msg, p.keys.Add):
 			p.adding = true
 			p.input.Focus()
 			return textinput.Blink
 
+		case key.Matches(msg, p.keys.Edit):
+			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
+				p.startEditMode(p.tasks[p.cursor])
+				return textinput.Blink
+			}
+
+		case key.Matches(msg, p.keys.Filter):
+			p.filtering = true
+			p.input.Placeholder = "Tag to show (empty for all)"
+			if tags := p.knownTags(); len(tags) > 0 {
+				p.input.Placeholder = "#" + strings.Join(tags, " #")
+			}
+			p.input.CharLimit = 31
+			p.input.SetValue(p.tagFilter)
+			p.input.CursorEnd()
+			p.input.Focus()
+			return textinput.Blink
+
+		case key.Matches(msg, p.keys.Notes):
+			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
+				return editNotesCmd(p.storage, p.tasks[p.cursor])
+			}
+
+		case key.Matches(msg, p.keys.Track):
+			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
+				task := p.tasks[p.cursor]
+				if _, running := p.trackedTime(task.ID); running {
+					return stopTimerCmd(p.storage)
+				}
+				return startTaskTimerCmd(p.storage, task)
+			}
+
+		case key.Matches(msg, p.keys.Snooze):
+			if len(p.tasks) > 0 && p.cursor < len(p.tasks) && !p.tasks[p.cursor].Done {
+				return snoozeTaskCmd(p.storage, p.tasks[p.cursor])
+			}
+
+		case key.Matches(msg, p.keys.Deferred):
+			p.showDeferred = !p.showDeferred
+			p.itemCursor = -1
+			p.applyFilter()
+
+		case key.Matches(msg, p.keys.MoveUp):
+			if p.cursor < len(p.tasks) && p.cursor > 0 {
+				return p.moveTask(p.tasks[p.cursor], p.tasks[p.cursor-1])
+			}
+
+		case key.Matches(msg, p.keys.MoveDown):
+			if p.cursor+1 < len(p.tasks) {
+				return p.moveTask(p.tasks[p.cursor], p.tasks[p.cursor+1])
+			}
+
+		case key.Matches(msg, p.keys.Block):
+			if len(p.tasks) > 1 && p.cursor < len(p.tasks) {
+				p.linking = true
+				p.linkTaskID = p.tasks[p.cursor].ID
+				p.itemCursor = -1
+			}
+
+		case key.Matches(msg, p.keys.Expand):
+			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
+				id := p.tasks[p.cursor].ID
+				p.expanded[id] = !p.expanded[id]
+				if !p.expanded[id] {
+					delete(p.expanded, id)
+					p.itemCursor = -1
+				}
+			}
+
+		case key.Matches(msg, p.keys.AddItem):
+			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
+				p.addingItem = true
+				p.itemTaskID = p.tasks[p.cursor].ID
+				p.expanded[p.itemTaskID] = true
+				p.input.Placeholder = "Checklist item (empty to finish)"
+				p.input.CharLimit = 200
+				p.input.Focus()
+				return textinput.Blink
+			}
+
 		case key.Matches(msg, p.keys.Toggle):
+			if item := p.selectedItem(); item != nil {
+				return toggleChecklistItemCmd(p.storage, p.tasks[p.cursor].ID, *item)
+			}
 			// Toggle done asynchronously
 			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
 				task := p.tasks[p.cursor]
@@ -197,6 +574,9 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 			}
 
 		case key.Matches(msg, p.keys.Delete):
+			if item := p.selectedItem(); item != nil {
+				return deleteChecklistItemCmd(p.storage, p.tasks[p.cursor].ID, *item, p.itemCursor)
+			}
 			// Delete task asynchronously
 			if len(p.tasks) > 0 && p.cursor < len(p.tasks) {
 				task := p.tasks[p.cursor]
@@ -208,32 +588,474 @@ func (p *TaskPane) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// finishLinking leaves blocker selection and returns the command that links
+// (or, if already linked, unlinks) the selected task as a blocker.
+func (p *TaskPane) finishLinking() tea.Cmd {
+	taskID := p.linkTaskID
+	p.linking = false
+	p.linkTaskID = ""
+
+	task, ok := p.findTask(taskID)
+	if !ok || p.cursor >= len(p.tasks) {
+		return nil
+	}
+	blocker := p.tasks[p.cursor]
+	if blocker.ID == task.ID {
+		return nil
+	}
+	for _, id := range task.BlockedBy {
+		if id == blocker.ID {
+			return setBlockerCmd(p.storage, task, blocker, false)
+		}
+	}
+	return setBlockerCmd(p.storage, task, blocker, true)
+}
+
+// moveTask moves task to target's place in the manual order and returns the
+// command that saves it. The list is reordered right away so repeated moves
+// build on each other; completed tasks stay below pending ones.
+func (p *TaskPane) moveTask(task, target storage.Task) tea.Cmd {
+	if !p.manualSort {
+		return func() tea.Msg {
+			return tasksReorderedMsg{err: fmt.Errorf("manual sorting is off (set ux.task_sort: manual)")}
+		}
+	}
+	if task.ID == target.ID || task.Done != target.Done {
+		return nil
+	}
+
+	before := make([]string, 0, len(p.all))
+	from, to := -1, -1
+	for i, t := range p.all {
+		before = append(before, t.ID)
+		switch t.ID {
+		case task.ID:
+			from = i
+		case target.ID:
+			to = i
+		}
+	}
+	if from < 0 || to < 0 {
+		return nil
+	}
+
+	// Take the task out and put it where the target is: moving down lands
+	// after the target, moving up lands before it.
+	reordered := make([]storage.Task, 0, len(p.all))
+	reordered = append(reordered, p.all[:from]...)
+	reordered = append(reordered, p.all[from+1:]...)
+	reordered = append(reordered[:to], append([]storage.Task{p.all[from]}, reordered[to:]...)...)
+
+	after := make([]string, len(reordered))
+	for i := range reordered {
+		reordered[i].Position = i + 1
+		after[i] = reordered[i].ID
+	}
+	p.all = reordered
+	p.applyFilter()
+	for i, t := range p.tasks {
+		if t.ID == task.ID {
+			p.cursor, p.itemCursor = i, -1
+		}
+	}
+
+	return reorderTasksCmd(p.storage, before, after, task.Text)
+}
+
+// findTask looks up a task by ID, ignoring the tag filter.
+func (p *TaskPane) findTask(id string) (storage.Task, bool) {
+	for _, task := range p.all {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return storage.Task{}, false
+}
+
+// resetFilterMode leaves tag filter entry and restores the add-task input defaults.
+func (p *TaskPane) resetFilterMode() {
+	p.filtering = false
+	p.input.Reset()
+	p.input.Placeholder = "What needs to be done?"
+	p.input.CharLimit = 100
+}
+
+// knownTags returns the distinct tags used by all tasks, sorted.
+func (p *TaskPane) knownTags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, task := range p.all {
+		for _, tag := range task.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// resetAddItemMode leaves checklist entry and restores the add-task input defaults.
+func (p *TaskPane) resetAddItemMode() {
+	p.addingItem = false
+	p.itemTaskID = ""
+	p.input.Reset()
+	p.input.Placeholder = "What needs to be done?"
+	p.input.CharLimit = 100
+}
+
+// taskRow is one line of the task list: a task, or one of its checklist items.
+type taskRow struct {
+	task int // Index into p.tasks
+	item int // Index into the task's checklist, or -1 for the task line
+}
+
+// rows flattens the task list into display lines, including the checklist
+// items of expanded tasks.
+func (p *TaskPane) rows() []taskRow {
+	rows := make([]taskRow, 0, len(p.tasks))
+	for i, task := range p.tasks {
+		rows = append(rows, taskRow{task: i, item: -1})
+		if p.expanded[task.ID] {
+			for j := range task.Checklist {
+				rows = append(rows, taskRow{task: i, item: j})
+			}
+		}
+	}
+	return rows
+}
+
+// selectedRow returns the index in rows of the current selection.
+func (p *TaskPane) selectedRow(rows []taskRow) int {
+	for i, row := range rows {
+		if row.task == p.cursor && row.item == p.itemCursor {
+			return i
+		}
+	}
+	return 0
+}
+
+// selectRow moves the selection to rows[idx], clamped to the list.
+func (p *TaskPane) selectRow(rows []taskRow, idx int) {
+	if len(rows) == 0 {
+		return
+	}
+	idx = max(0, min(idx, len(rows)-1))
+	p.cursor = rows[idx].task
+	p.itemCursor = rows[idx].item
+}
+
+// selectedItem returns the selected checklist item, or nil when a task line
+// is selected.
+func (p *TaskPane) selectedItem() *storage.ChecklistItem {
+	if p.itemCursor < 0 || p.cursor >= len(p.tasks) {
+		return nil
+	}
+	checklist := p.tasks[p.cursor].Checklist
+	if p.itemCursor >= len(checklist) {
+		return nil
+	}
+	return &checklist[p.itemCursor]
+}
+
+// windowRows returns the first visible row and how many rows fit, keeping
+// the selected row (and the notes preview beneath it) in view.
+func (p *TaskPane) windowRows(rows []taskRow) (start, maxRows int) {
+	maxRows = p.height - 6 // Account for title, separator, input, stats
+	if maxRows < 3 {
+		maxRows = 5
+	}
+	maxRows = max(1, maxRows-len(p.notesPreview()))
+	if sel := p.selectedRow(rows); sel >= maxRows {
+		start = sel - maxRows + 1
+	}
+	return start, maxRows
+}
+
+// maxNotesPreviewLines caps how much of a task's notes is shown in the list.
+const maxNotesPreviewLines = 3
+
+// notesPreview returns the lines of the selected task's notes shown beneath
+// it, or nil when nothing should be shown. Long notes end with a hint line.
+func (p *TaskPane) notesPreview() []string {
+	if !p.focused || p.adding || p.itemCursor >= 0 || p.cursor >= len(p.tasks) {
+		return nil
+	}
+	notes := strings.TrimSpace(p.tasks[p.cursor].Notes)
+	if notes == "" {
+		return nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(notes, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > maxNotesPreviewLines {
+		more := len(lines) - (maxNotesPreviewLines - 1)
+		lines = append(lines[:maxNotesPreviewLines-1], fmt.Sprintf("… %d more lines", more))
+	}
+	return lines
+}
+
+// startEditMode opens the edit form for a task, prefilled with its text.
+func (p *TaskPane) startEditMode(task storage.Task) {
+	p.editing = true
+	p.editStep = editStepText
+	p.editOrig = task
+	p.editDraft = task
+	p.editErr = ""
+	p.loadEditStep()
+	p.input.Focus()
+}
+
+// loadEditStep prefills the input with the draft value for the current step.
+func (p *TaskPane) loadEditStep() {
+	var value string
+	switch p.editStep {
+	case editStepText:
+		value = p.editDraft.Text
+		p.input.Placeholder = "What needs to be done?"
+		p.input.CharLimit = 200 // storage limit for task text
+	case editStepProject:
+		value = p.editDraft.Project
+		p.input.Placeholder = "Project (optional)"
+		p.input.CharLimit = 60
+	case editStepTags:
+		value = strings.Join(p.editDraft.Tags, " ")
+		p.input.Placeholder = "Tags, space separated (blank for none)"
+		p.input.CharLimit = 200
+	case editStepPriority:
+		value = string(p.editDraft.Priority)
+		p.input.Placeholder = "high, medium, low (blank for none)"
+		p.input.CharLimit = 6
+	case editStepDue:
+		if p.editDraft.DueDate != nil {
+			value = p.editDraft.DueDate.Format("2006-01-02")
+			if p.editDraft.DueTimed {
+				value = p.editDraft.DueDate.In(p.editDraft.DueLocation()).Format("2006-01-02 15:04")
+			}
+		}
+		p.input.Placeholder = "YYYY-MM-DD [HH:MM], today, tomorrow 9:00 (blank for none)"
+		p.input.CharLimit = 16
+	case editStepStart:
+		if p.editDraft.StartDate != nil {
+			value = p.editDraft.StartDate.Format("2006-01-02")
+		}
+		p.input.Placeholder = "Hide until YYYY-MM-DD, tomorrow (blank for none)"
+		p.input.CharLimit = 10
+	case editStepRepeat:
+		if p.editDraft.Recurrence != nil {
+			value = p.editDraft.Recurrence.String()
+		}
+		p.input.Placeholder = "daily, every N days, weekly mon,thu, monthly 15, after N days"
+		p.input.CharLimit = 40
+	}
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+}
+
+// applyEditStep parses the input for the current step into the draft.
+func (p *TaskPane) applyEditStep() error {
+	value := strings.TrimSpace(p.input.Value())
+	switch p.editStep {
+	case editStepText:
+		if value == "" {
+			return fmt.Errorf("task text is required")
+		}
+		p.editDraft.Text = value
+	case editStepProject:
+		p.editDraft.Project = value
+	case editStepTags:
+		tags, err := storage.ParseTagList(value)
+		if err != nil {
+			return err
+		}
+		p.editDraft.Tags = tags
+	case editStepPriority:
+		priority, err := parsePriorityInput(value)
+		if err != nil {
+			return err
+		}
+		p.editDraft.Priority = priority
+	case editStepDue:
+		// A deadline is shown in the zone it was set in, so the time typed
+		// back is read in that zone too and the zone is kept.
+		wasTimed := p.editDraft.DueTimed && p.editDraft.DueDate != nil
+		loc := time.Local
+		if wasTimed {
+			loc = p.editDraft.DueLocation()
+		}
+		due, timed, err := parseDueInput(value, time.Now().In(loc))
+		if err != nil {
+			return err
+		}
+		if timed && !wasTimed {
+			p.editDraft.DueZone = storage.LocalZoneName()
+		}
+		p.editDraft.DueDate = due
+		p.editDraft.DueTimed = timed
+	case editStepStart:
+		start, err := parseDueDateInput(value, time.Now())
+		if err != nil {
+			return fmt.Errorf("start date must be YYYY-MM-DD, today, tomorrow or blank")
+		}
+		p.editDraft.StartDate = start
+	case editStepRepeat:
+		recurrence, err := storage.ParseRecurrence(value)
+		if err != nil {
+			return err
+		}
+		p.editDraft.Recurrence = recurrence
+	}
+	return nil
+}
+
+// resetEditMode leaves edit mode and restores the add-task input defaults.
+func (p *TaskPane) resetEditMode() {
+	p.editing = false
+	p.editStep = editStepText
+	p.editOrig = storage.Task{}
+	p.editDraft = storage.Task{}
+	p.editErr = ""
+	p.input.Reset()
+	p.input.Placeholder = "What needs to be done?"
+	p.input.CharLimit = 100
+}
+
+// taskFieldsEqual reports whether two snapshots have the same editable fields.
+func taskFieldsEqual(a, b storage.Task) bool {
+	if a.Text != b.Text || a.Project != b.Project || a.Priority != b.Priority {
+		return false
+	}
+	if strings.Join(a.Tags, " ") != strings.Join(b.Tags, " ") {
+		return false
+	}
+	if (a.DueDate == nil) != (b.DueDate == nil) {
+		return false
+	}
+	if a.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+		return false
+	}
+	if a.DueTimed != b.DueTimed {
+		return false
+	}
+	if (a.StartDate == nil) != (b.StartDate == nil) {
+		return false
+	}
+	if a.StartDate != nil && !a.StartDate.Equal(*b.StartDate) {
+		return false
+	}
+	return recurrenceString(a.Recurrence) == recurrenceString(b.Recurrence)
+}
+
+// recurrenceString renders an optional recurrence rule ("" when unset).
+func recurrenceString(r *storage.Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// parsePriorityInput converts user input into a priority level.
+// Accepts full names or their first letter; blank or "none" clears it.
+func parsePriorityInput(s string) (storage.Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "-":
+		return storage.PriorityNone, nil
+	case "h", "high":
+		return storage.PriorityHigh, nil
+	case "m", "medium", "med":
+		return storage.PriorityMedium, nil
+	case "l", "low":
+		return storage.PriorityLow, nil
+	default:
+		return storage.PriorityNone, fmt.Errorf("priority must be high, medium, low or blank")
+	}
+}
+
+// parseDueDateInput converts user input into a due date relative to now.
+// Accepts YYYY-MM-DD, "today" and "tomorrow"; blank or "none" clears it.
+func parseDueDateInput(s string, now time.Time) (*time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "-":
+		return nil, nil
+	case "today":
+		return &today, nil
+	case "tomorrow":
+		tomorrow := today.AddDate(0, 0, 1)
+		return &tomorrow, nil
+	}
+	due, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(s), now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("due date must be YYYY-MM-DD, today, tomorrow or blank")
+	}
+	return &due, nil
+}
+
+// parseDueInput converts user input into a due date, optionally with a time
+// of day that makes it an exact deadline: "2025-03-14 17:00", "tomorrow 9:30"
+// or just "14:00" for today. Anything parseDueDateInput accepts on its own is
+// an all-day due date.
+func parseDueInput(s string, now time.Time) (due *time.Time, timed bool, err error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, false, nil
+	}
+	clock, err := time.Parse("15:04", fields[len(fields)-1])
+	if err != nil {
+		due, err := parseDueDateInput(s, now)
+		if err != nil {
+			return nil, false, fmt.Errorf("due date must be YYYY-MM-DD [HH:MM], today, tomorrow, HH:MM or blank")
+		}
+		return due, false, nil
+	}
+	day := now
+	if len(fields) > 1 {
+		d, err := parseDueDateInput(strings.Join(fields[:len(fields)-1], " "), now)
+		if err != nil || d == nil {
+			return nil, false, fmt.Errorf("due date must be YYYY-MM-DD [HH:MM], today, tomorrow, HH:MM or blank")
+		}
+		day = *d
+	}
+	deadline := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	return &deadline, true, nil
+}
+
 // handleMouse processes mouse events for the task pane.
 func (p *TaskPane) handleMouse(msg tea.MouseMsg) tea.Cmd {
 	if len(p.tasks) == 0 {
 		return nil
 	}
 
-	// Content starts after title (1) + separator (1) = row 2
-	const headerRows = 2
+	rows := p.rows()
 
-	// Mirror the view windowing logic so clicks map to the visible slice.
-	maxTasks := p.height - 6 // Account for title, separator, input, stats
-	if maxTasks < 3 {
-		maxTasks = 5
-	}
-	startIdx := 0
-	if p.cursor >= maxTasks {
-		startIdx = p.cursor - maxTasks + 1
+	// Releasing over another task after pressing on one drags it there.
+	if msg.Action == tea.MouseActionRelease {
+		dragged := p.dragTaskID
+		p.dragTaskID = ""
+		rowIdx, ok := p.rowAt(rows, msg.Y)
+		if dragged == "" || !ok {
+			return nil
+		}
+		task, found := p.findTask(dragged)
+		target := p.tasks[rows[rowIdx].task]
+		if !found || target.ID == task.ID {
+			return nil
+		}
+		return p.moveTask(task, target)
 	}
 
 	switch msg.Button {
 	case tea.MouseButtonWheelUp:
-		p.cursor = max(p.cursor-1, 0)
+		p.selectRow(rows, p.selectedRow(rows)-1)
 		return nil
 
 	case tea.MouseButtonWheelDown:
-		p.cursor = min(p.cursor+1, len(p.tasks)-1)
+		p.selectRow(rows, p.selectedRow(rows)+1)
 		return nil
 
 	case tea.MouseButtonLeft:
@@ -241,41 +1063,75 @@ func (p *TaskPane) handleMouse(msg tea.MouseMsg) tea.Cmd {
 			return nil
 		}
 
-		// Calculate which task was clicked
-		taskRow := msg.Y - headerRows
-		if taskRow < 0 || taskRow >= maxTasks {
+		rowIdx, ok := p.rowAt(rows, msg.Y)
+		if !ok {
 			return nil
 		}
 
-		taskIdx := startIdx + taskRow
-		if taskIdx < 0 || taskIdx >= len(p.tasks) {
+		// Move cursor to clicked line
+		p.selectRow(rows, rowIdx)
+		task := p.tasks[p.cursor]
+
+		// Checklist items are indented by 6 columns: toggle on their checkbox.
+		if item := p.selectedItem(); item != nil {
+			if msg.X < 10 {
+				return toggleChecklistItemCmd(p.storage, task.ID, *item)
+			}
 			return nil
 		}
 
-		// Move cursor to clicked task
-		p.cursor = taskIdx
-
 		// Check if click was on the checkbox area (first few chars)
 		// Checkbox format: "![ ] " or "~[x] " - about 5 chars
 		if msg.X < 5 {
 			// Toggle the clicked task
-			task := p.tasks[taskIdx]
 			if task.Done {
 				return uncompleteTaskCmd(p.storage, task.ID)
 			}
 			return completeTaskCmd(p.storage, task.ID)
 		}
+
+		// Pressing on the text starts a drag, finished on release
+		p.dragTaskID = task.ID
 	}
 
 	return nil
 }
 
+// rowAt maps a pane-relative y coordinate to an index in rows, mirroring the
+// view's windowing and skipping the notes preview beneath the selected row.
+func (p *TaskPane) rowAt(rows []taskRow, y int) (int, bool) {
+	// Content starts after title (1) + separator (1) = row 2
+	const headerRows = 2
+
+	startIdx, maxRows := p.windowRows(rows)
+	rowOffset := y - headerRows
+	notesLines := len(p.notesPreview())
+	if rowOffset < 0 || rowOffset >= maxRows+notesLines {
+		return 0, false
+	}
+	if selOffset := p.selectedRow(rows) - startIdx; rowOffset > selOffset {
+		if rowOffset <= selOffset+notesLines {
+			return 0, false
+		}
+		rowOffset -= notesLines
+	}
+
+	rowIdx := startIdx + rowOffset
+	if rowIdx < 0 || rowIdx >= len(rows) {
+		return 0, false
+	}
+	return rowIdx, true
+}
+
 // View renders the task pane.
 func (p *TaskPane) View() string {
 	var b strings.Builder
 
-	// Title
+	// Title (with the active tag filter, if any)
 	title := p.styles.PaneTitleStyle.Render("✅ TASKS")
+	if p.tagFilter != "" {
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title, p.styles.TagStyle.Render(" #"+p.tagFilter))
+	}
 	b.WriteString(title)
 	b.WriteString("\n")
 
@@ -289,102 +1145,53 @@ func (p *TaskPane) View() string {
 
 	// Tasks list
 	if len(p.tasks) == 0 && !p.adding {
-		b.WriteString(lipgloss.NewStyle().Foreground(p.styles.ColorTextMuted).Italic(true).Render("  No tasks yet. Press 'a' to add one."))
+		empty := "  No tasks yet. Press 'a' to add one."
+		if p.tagFilter != "" {
+			empty = fmt.Sprintf("  No tasks tagged #%s.", p.tagFilter)
+		} else if p.hiddenDeferred > 0 {
+			empty = fmt.Sprintf("  Nothing to do yet. %d later (H to show).", p.hiddenDeferred)
+		}
+		b.WriteString(lipgloss.NewStyle().Foreground(p.styles.ColorTextMuted).Italic(true).Render(empty))
 		b.WriteString("\n")
 	} else {
-		// Calculate how many tasks we can show
-		maxTasks := p.height - 6 // Account for title, separator, input, stats
-		if maxTasks < 3 {
-			maxTasks = 5
-		}
-
-		// Show tasks
-		startIdx := 0
-		if p.cursor >= maxTasks {
-			startIdx = p.cursor - maxTasks + 1
-		}
-
-		doneCount := 0
-
-		for i, task := range p.tasks {
-			if task.Done {
-				doneCount++
-			}
+		// Calculate which lines we can show
+		rows := p.rows()
+		startIdx, maxRows := p.windowRows(rows)
 
-			if i < startIdx || i >= startIdx+maxTasks {
+		for i, row := range rows {
+			if i < startIdx || i >= startIdx+maxRows {
 				continue
 			}
 
-			// Priority badge (1 char: "!", "~", or " ")
-			priorityBadge := p.formatPriorityBadge(task.Priority)
-
-			// Checkbox
-			var checkbox string
-			if task.Done {
-				checkbox = p.styles.TaskCheckboxDone
+			selected := row.task == p.cursor && row.item == p.itemCursor && p.focused && !p.adding
+			task := p.tasks[row.task]
+			if row.item >= 0 {
+				b.WriteString(p.renderChecklistItem(task.Checklist[row.item], selected))
 			} else {
-				checkbox = p.styles.TaskCheckboxPending
-			}
-
-			// Due date indicator
-			dueIndicator := p.formatDueDate(task.DueDate)
-			dueWidth := lipgloss.Width(dueIndicator)
-
-			// Calculate available width for task text
-			// Layout: [space][priority][checkbox][space][text][space?][due]
-			// Fixed parts: 1 (leading space) + 1 (priority) + 3 (checkbox) + 1 (space after checkbox)
-			fixedWidth := 6
-			if dueWidth > 0 {
-				fixedWidth += dueWidth + 1 // due indicator + space before it
-			}
-			availableTextWidth := p.width - 4 - fixedWidth // 4 for pane padding/borders
-			if availableTextWidth < 5 {
-				availableTextWidth = 5
+				b.WriteString(p.renderTask(task, selected))
 			}
-
-			taskText := runewidth.Truncate(task.Text, availableTextWidth, "..")
-			taskTextWidth := runewidth.StringWidth(taskText)
-
-			// Build the line
-			var line string
-			if i == p.cursor && p.focused && !p.adding {
-				// Selected: highlight entire line
-				textPart := fmt.Sprintf("%s%s %s", priorityBadge, checkbox, taskText)
-				if dueWidth > 0 {
-					padding := availableTextWidth - taskTextWidth
-					if padding < 1 {
-						padding = 1
-					}
-					textPart += strings.Repeat(" ", padding) + dueIndicator
-				}
-				line = p.styles.TaskSelectedStyle.Render(" " + textPart + " ")
-			} else {
-				// Normal: assemble with styles
-				var styledText string
-				if task.Done {
-					styledText = p.styles.TaskDoneStyle.Render(taskText)
-				} else {
-					styledText = p.styles.TaskPendingStyle.Render(taskText)
-				}
-
-				textPart := fmt.Sprintf(" %s%s %s", priorityBadge, checkbox, styledText)
-				if dueWidth > 0 {
-					padding := availableTextWidth - taskTextWidth
-					if padding < 1 {
-						padding = 1
-					}
-					textPart += strings.Repeat(" ", padding) + dueIndicator
+			b.WriteString("\n")
+			if selected && row.item < 0 {
+				for _, line := range p.notesPreview() {
+					b.WriteString(p.renderNotesLine(line))
+					b.WriteString("\n")
 				}
-				line = textPart
 			}
-
-			b.WriteString(line)
-			b.WriteString("\n")
 		}
 
-		// Stats
+		// Stats (for the visible tasks)
+		doneCount := 0
+		for _, task := range p.tasks {
+			if task.Done {
+				doneCount++
+			}
+		}
 		b.WriteString("\n")
-		stats := p.styles.StatLabelStyle.Render(fmt.Sprintf("%d/%d complete", doneCount, len(p.tasks)))
+		statsText := fmt.Sprintf("%d/%d complete", doneCount, len(p.tasks))
+		if p.hiddenDeferred > 0 {
+			statsText += fmt.Sprintf(" · %d later", p.hiddenDeferred)
+		}
+		stats := p.styles.StatLabelStyle.Render(statsText)
 		b.WriteString("  " + stats)
 		b.WriteString("\n")
 	}
@@ -397,6 +1204,46 @@ func (p *TaskPane) View() string {
 		b.WriteString("\n")
 	}
 
+	// Input field when typing a tag filter
+	if p.filtering {
+		b.WriteString("\n")
+		prompt := p.styles.InputPromptStyle.Render("Filter #")
+		b.WriteString(prompt + p.input.View())
+		b.WriteString("\n")
+	}
+
+	// Prompt while choosing a blocker
+	if p.linking {
+		if task, ok := p.findTask(p.linkTaskID); ok {
+			b.WriteString("\n")
+			prompt := p.styles.InputPromptStyle.Render("Blocked by: ")
+			text := runewidth.Truncate(task.Text, max(5, p.width-18), "..")
+			b.WriteString(prompt + p.styles.StatLabelStyle.Render(text+" waits on the selected task"))
+			b.WriteString("\n")
+		}
+	}
+
+	// Input field when adding checklist items
+	if p.addingItem {
+		b.WriteString("\n")
+		prompt := p.styles.InputPromptStyle.Render("Item: ")
+		b.WriteString(prompt + p.input.View())
+		b.WriteString("\n")
+	}
+
+	// Edit form for the selected task
+	if p.editing {
+		b.WriteString("\n")
+		labels := [editStepCount]string{"Text: ", "Project: ", "Tags: ", "Priority: ", "Due: ", "Start: ", "Repeat: "}
+		prompt := p.styles.InputPromptStyle.Render(labels[p.editStep])
+		b.WriteString(prompt + p.input.View())
+		b.WriteString("\n")
+		if p.editErr != "" {
+			b.WriteString(p.styles.ErrorStyle.Render(p.editErr))
+			b.WriteString("\n")
+		}
+	}
+
 	// Apply pane style
 	content := b.String()
 	style := p.styles.PaneStyle
@@ -407,14 +1254,168 @@ func (p *TaskPane) View() string {
 	return style.Width(p.width).Height(p.height).Render(content)
 }
 
-// Stats returns task statistics.
+// renderTask renders a single task line with its badges and indicators.
+func (p *TaskPane) renderTask(task storage.Task, selected bool) string {
+	// Priority badge (1 char: "!", "~", or " ")
+	priorityBadge := p.formatPriorityBadge(task.Priority)
+
+	// Checkbox
+	var checkbox string
+	if task.Done {
+		checkbox = p.styles.TaskCheckboxDone
+	} else {
+		checkbox = p.styles.TaskCheckboxPending
+	}
+
+	// Right-aligned indicators: checklist progress, recurrence, due date
+	indicators := p.formatIndicators(task)
+	indicatorWidth := lipgloss.Width(indicators)
+
+	// Calculate available width for task text
+	// Layout: [space][priority][checkbox][space][text][space?][indicators]
+	// Fixed parts: 1 (leading space) + 1 (priority) + 3 (checkbox) + 1 (space after checkbox)
+	fixedWidth := 6
+	if indicatorWidth > 0 {
+		fixedWidth += indicatorWidth + 1 // indicators + space before them
+	}
+	availableTextWidth := p.width - 4 - fixedWidth // 4 for pane padding/borders
+	if availableTextWidth < 5 {
+		availableTextWidth = 5
+	}
+
+	// Tags follow the text and are the first thing cut when space runs out
+	var tagSuffix string
+	for _, tag := range task.Tags {
+		tagSuffix += " #" + tag
+	}
+	taskText := runewidth.Truncate(task.Text+tagSuffix, availableTextWidth, "..")
+	taskTextWidth := runewidth.StringWidth(taskText)
+	var tagText string
+	if len(taskText) > len(task.Text) && strings.HasPrefix(taskText, task.Text) {
+		taskText, tagText = taskText[:len(task.Text)], taskText[len(task.Text):]
+	}
+
+	padIndicators := func(textPart string) string {
+		if indicatorWidth == 0 {
+			return textPart
+		}
+		padding := availableTextWidth - taskTextWidth
+		if padding < 1 {
+			padding = 1
+		}
+		return textPart + strings.Repeat(" ", padding) + indicators
+	}
+
+	if selected {
+		// Selected: highlight entire line
+		textPart := fmt.Sprintf("%s%s %s%s", priorityBadge, checkbox, taskText, tagText)
+		return p.styles.TaskSelectedStyle.Render(" " + padIndicators(textPart) + " ")
+	}
+
+	// Normal: assemble with styles
+	var styledText string
+	if task.Done {
+		styledText = p.styles.TaskDoneStyle.Render(taskText)
+	} else if len(storage.PendingBlockers(p.all, task)) > 0 {
+		styledText = p.styles.BlockedStyle.Render(taskText)
+	} else {
+		styledText = p.styles.TaskPendingStyle.Render(taskText)
+	}
+	if tagText != "" {
+		styledText += p.styles.TagStyle.Render(tagText)
+	}
+	return padIndicators(fmt.Sprintf(" %s%s %s", priorityBadge, checkbox, styledText))
+}
+
+// renderChecklistItem renders a checklist item line, indented under its task.
+func (p *TaskPane) renderChecklistItem(item storage.ChecklistItem, selected bool) string {
+	const indent = "      " // Aligns the item checkbox with the task text
+
+	checkbox := p.styles.TaskCheckboxPending
+	if item.Done {
+		checkbox = p.styles.TaskCheckboxDone
+	}
+
+	availableTextWidth := p.width - 4 - len(indent) - 4 // checkbox + space
+	if availableTextWidth < 5 {
+		availableTextWidth = 5
+	}
+	text := runewidth.Truncate(item.Text, availableTextWidth, "..")
+
+	if selected {
+		return p.styles.TaskSelectedStyle.Render(indent + checkbox + " " + text + " ")
+	}
+	if item.Done {
+		text = p.styles.TaskDoneStyle.Render(text)
+	} else {
+		text = p.styles.TaskPendingStyle.Render(text)
+	}
+	return indent + checkbox + " " + text
+}
+
+// renderNotesLine renders one line of the notes preview, indented under the
+// task text.
+func (p *TaskPane) renderNotesLine(line string) string {
+	const indent = "      " // Aligns the notes with the task text
+
+	availableWidth := p.width - 4 - len(indent)
+	if availableWidth < 5 {
+		availableWidth = 5
+	}
+	return indent + p.styles.NotesStyle.Render(runewidth.Truncate(line, availableWidth, ".."))
+}
+
+// formatIndicators returns the space-separated indicators shown at the end
+// of a task line: the first unfinished blocker ("⊘ Blocker +1"), a notes
+// marker, checklist progress ("3/5"), a recurrence marker, tracked time
+// ("◷ 1h 20m", or "▶ 12m" while the timer runs), the start date of a
+// deferred task ("↦ Jan 5") and the due date.
+func (p *TaskPane) formatIndicators(task storage.Task) string {
+	var parts []string
+	if blockers := storage.PendingBlockers(p.all, task); len(blockers) > 0 && !task.Done {
+		blocked := "⊘ " + runewidth.Truncate(blockers[0].Text, 12, "..")
+		if len(blockers) > 1 {
+			blocked += fmt.Sprintf(" +%d", len(blockers)-1)
+		}
+		parts = append(parts, p.styles.BlockedStyle.Render(blocked))
+	}
+	if strings.TrimSpace(task.Notes) != "" {
+		parts = append(parts, p.styles.NotesStyle.Render("✎"))
+	}
+	if done, total := task.ChecklistProgress(); total > 0 {
+		parts = append(parts, p.styles.StatLabelStyle.Render(fmt.Sprintf("%d/%d", done, total)))
+	}
+	if task.Recurrence != nil {
+		parts = append(parts, p.styles.RecurringStyle.Render("↻"))
+	}
+	if tracked, running := p.trackedTime(task.ID); running {
+		parts = append(parts, p.styles.TimerRunningStyle.Render("▶ "+formatDurationShort(tracked)))
+	} else if tracked >= time.Minute {
+		parts = append(parts, p.styles.StatLabelStyle.Render("◷ "+formatDurationShort(tracked)))
+	}
+	if task.IsDeferred(p.storage.Now()) {
+		parts = append(parts, p.styles.StatLabelStyle.Render("↦ "+task.StartDate.Format("Jan 2")))
+	}
+	if due := p.formatDueDate(task); due != "" {
+		parts = append(parts, due)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Stats returns task statistics (across all tasks, ignoring the tag filter).
+// Deferred tasks are left out since they are not for today.
 func (p *TaskPane) Stats() (done, total int) {
-	for _, task := range p.tasks {
+	now := p.storage.Now()
+	for _, task := range p.all {
+		if task.IsDeferred(now) {
+			continue
+		}
+		total++
 		if task.Done {
 			done++
 		}
 	}
-	return done, len(p.tasks)
+	return done, total
 }
 
 func min(a, b int) int {
@@ -446,13 +1447,26 @@ func (p *TaskPane) formatPriorityBadge(priority storage.Priority) string {
 
 // formatDueDate returns a compact, styled due date indicator.
 // Returns empty string if no due date, otherwise: "!" (overdue), "T" (today),
-// "+1" (tomorrow), "3d" (days), "2w" (weeks), ">1m" (over a month).
-func (p *TaskPane) formatDueDate(dueDate *time.Time) string {
-	if dueDate == nil {
+// "+1" (tomorrow), "3d" (days), "2w" (weeks), ">1m" (over a month). Timed
+// deadlines within a day show the time left or past: "in 2h", "overdue 30m".
+func (p *TaskPane) formatDueDate(task storage.Task) string {
+	if task.DueDate == nil {
 		return ""
 	}
 
-	now := time.Now()
+	now := p.storage.Now()
+	dueDate := *task.DueDate
+	if task.DueTimed {
+		left := dueDate.Sub(now)
+		switch {
+		case left < 0:
+			return p.styles.DueDateOverdueStyle.Render("overdue " + formatDueOffset(-left))
+		case left < 24*time.Hour:
+			return p.styles.DueDateTodayStyle.Render("in " + formatDueOffset(left))
+		}
+		// Further out, count days on the local calendar.
+		dueDate = dueDate.In(now.Location())
+	}
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, dueDate.Location())
 
@@ -474,3 +1488,16 @@ func (p *TaskPane) formatDueDate(dueDate *time.Time) string {
 		return p.styles.DueDateFutureStyle.Render(">1m")
 	}
 }
+
+// formatDueOffset renders the time to or past a deadline in its largest
+// unit: "45m", "2h", "3d".
+func formatDueOffset(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(1, int(d.Minutes())))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"today/internal/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestTaskPaneView_Empty(t *testing.T) {
@@ -196,6 +199,31 @@ func TestTaskPaneView_WithDueDate(t *testing.T) {
 	assertGolden(t, "task_pane_with_due_dates", output)
 }
 
+func TestTaskPaneView_Recurring(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	styles := createTestStyles()
+
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	weekly, _ := store.AddTask("Weekly review", "", storage.PriorityNone, &nextWeek)
+	weekly.Recurrence = &storage.Recurrence{Kind: storage.RecurWeekly}
+	store.UpdateTask(*weekly)
+	daily, _ := store.AddTask("Stretch", "", storage.PriorityNone, nil)
+	daily.Recurrence = &storage.Recurrence{Kind: storage.RecurDaily}
+	store.UpdateTask(*daily)
+	store.AddTask("One-off", "", storage.PriorityNone, nil)
+
+	pane := NewTaskPane(store, styles)
+	pane.SetSize(40, 20)
+	pane.SetFocused(false)
+
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	output := pane.View()
+	assertGolden(t, "task_pane_recurring", output)
+}
+
 func TestTaskPaneView_NarrowWithPriorityAndDue(t *testing.T) {
 	setupTest(t)
 	store := createTestStorage(t)
@@ -239,7 +267,7 @@ func TestFormatDueDate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := pane.formatDueDate(tt.dueDate)
+			result := pane.formatDueDate(storage.Task{DueDate: tt.dueDate})
 			if tt.wantLen == 0 && result != "" {
 				t.Errorf("expected empty string, got %q", result)
 			}
@@ -252,6 +280,684 @@ func TestFormatDueDate(t *testing.T) {
 	}
 }
 
+// TestFormatDueDate_Timed tests relative rendering of exact deadlines.
+func TestFormatDueDate_Timed(t *testing.T) {
+	store := createTestStorage(t)
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return now })
+	pane := NewTaskPane(store, createTestStyles())
+
+	// A deadline set in another zone is the same instant here.
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name string
+		due  time.Time
+		want string
+	}{
+		{"in two hours", now.Add(2*time.Hour + 10*time.Minute), "in 2h"},
+		{"in minutes", now.Add(45 * time.Minute), "in 45m"},
+		{"overdue", now.Add(-30 * time.Minute), "overdue 30m"},
+		{"overdue days", now.Add(-50 * time.Hour), "overdue 2d"},
+		{"other zone", now.Add(3 * time.Hour).In(tokyo), "in 3h"},
+		{"beyond a day", now.AddDate(0, 0, 3), "3d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pane.formatDueDate(storage.Task{DueDate: &tt.due, DueTimed: true})
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("formatDueDate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskPane_EditMode(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+	styles := createTestStyles()
+
+	store.AddTask("Wirte tests", "", storage.PriorityNone, nil)
+
+	pane := NewTaskPane(store, styles)
+	pane.SetSize(40, 20)
+	pane.SetFocused(true)
+
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if !pane.IsEditing() {
+		t.Fatal("IsEditing() = false, want true after pressing 'e'")
+	}
+	if got := pane.input.Value(); got != "Wirte tests" {
+		t.Errorf("input prefilled with %q, want task text", got)
+	}
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	// Text, project, tags, priority, due date, start date, repeat.
+	pane.input.SetValue("Write tests")
+	pane.Update(enter)
+	pane.input.SetValue("today")
+	pane.Update(enter)
+	pane.input.SetValue("#Deep, client-x")
+	pane.Update(enter)
+	pane.input.SetValue("urgent")
+	pane.Update(enter)
+	if pane.editErr == "" || pane.editStep != editStepPriority {
+		t.Fatalf("invalid priority should keep the form on the priority step")
+	}
+	pane.input.SetValue("h")
+	pane.Update(enter)
+	pane.input.SetValue("")
+	pane.Update(enter)
+	pane.input.SetValue("")
+	pane.Update(enter)
+	pane.input.SetValue("weekly mon,thu")
+	cmd := pane.Update(enter)
+
+	if pane.IsEditing() {
+		t.Error("IsEditing() = true, want false after final step")
+	}
+	if cmd == nil {
+		t.Fatal("expected an update command after finishing the form")
+	}
+
+	msg, ok := cmd().(taskUpdatedMsg)
+	if !ok {
+		t.Fatalf("command returned %T, want taskUpdatedMsg", cmd())
+	}
+	if msg.err != nil {
+		t.Fatalf("update error: %v", msg.err)
+	}
+	if msg.before.Text != "Wirte tests" {
+		t.Errorf("before.Text = %q, want original text", msg.before.Text)
+	}
+
+	tasks, _ = store.LoadTasks()
+	got := tasks.Tasks[0]
+	if got.Text != "Write tests" || got.Project != "today" || got.Priority != storage.PriorityHigh {
+		t.Errorf("persisted task = %+v, want edited fields", got)
+	}
+	if strings.Join(got.Tags, ",") != "deep,client-x" {
+		t.Errorf("persisted tags = %v, want [deep client-x]", got.Tags)
+	}
+	if got.Recurrence == nil || got.Recurrence.String() != "weekly mon,thu" {
+		t.Errorf("persisted recurrence = %+v, want weekly mon,thu", got.Recurrence)
+	}
+}
+
+func TestTaskPane_EditDueInTaskZone(t *testing.T) {
+	setupTest(t)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("zone database unavailable: %v", err)
+	}
+	due := time.Date(2025, 3, 14, 17, 0, 0, 0, tokyo)
+	if _, offset := due.In(time.Local).Zone(); offset == 9*60*60 {
+		t.Skip("local zone has Tokyo's offset")
+	}
+
+	store := createTestStorage(t)
+	if _, err := store.CreateTask(storage.Task{Text: "Call Osaka office", DueDate: &due, DueTimed: true, DueZone: "Asia/Tokyo"}); err != nil {
+		t.Fatal(err)
+	}
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetFocused(true)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	pane.startEditMode(pane.tasks[0])
+	for pane.editStep != editStepDue {
+		pane.Update(enter)
+	}
+	if got := pane.input.Value(); got != "2025-03-14 17:00" {
+		t.Fatalf("due prefilled with %q, want the Tokyo wall clock", got)
+	}
+
+	// A new time is read in the task's zone too.
+	pane.input.SetValue("2025-03-14 18:00")
+	var cmd tea.Cmd
+	for pane.IsEditing() {
+		cmd = pane.Update(enter)
+	}
+	if cmd == nil {
+		t.Fatal("expected an update command after finishing the form")
+	}
+	if msg, ok := cmd().(taskUpdatedMsg); !ok || msg.err != nil {
+		t.Fatalf("update failed: %+v", msg)
+	}
+
+	tasks, _ = store.LoadTasks()
+	got := tasks.Tasks[0]
+	want := time.Date(2025, 3, 14, 18, 0, 0, 0, tokyo)
+	if got.DueDate == nil || !got.DueDate.Equal(want) || got.DueZone != "Asia/Tokyo" {
+		t.Errorf("due = %v in %q, want %v in Asia/Tokyo", got.DueDate, got.DueZone, want)
+	}
+}
+
+func TestTaskPane_EditModeCancel(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	store.AddTask("Keep me", "", storage.PriorityNone, nil)
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetFocused(true)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	pane.startEditMode(pane.tasks[0])
+	pane.input.SetValue("Changed")
+	if cmd := pane.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil {
+		t.Error("cancel should not return a command")
+	}
+	if pane.IsEditing() {
+		t.Error("IsEditing() = true, want false after cancel")
+	}
+
+	tasks, _ = store.LoadTasks()
+	if tasks.Tasks[0].Text != "Keep me" {
+		t.Errorf("task text = %q, want unchanged", tasks.Tasks[0].Text)
+	}
+}
+
+func TestTaskPane_Checklist(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	// Pending tasks sort newest first, so the checklist task is listed first
+	store.AddTask("Other task", "", storage.PriorityNone, nil)
+	task, _ := store.AddTask("Ship release 1.4", "", storage.PriorityNone, nil)
+	tag, _ := store.AddChecklistItem(task.ID, "Tag release")
+	store.AddChecklistItem(task.ID, "Publish notes")
+	store.SetChecklistItemDone(task.ID, tag.ID, true)
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(40, 20)
+	pane.SetFocused(true)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	// Collapsed: progress is shown but items are not
+	view := pane.View()
+	if !contains(view, "1/2") || contains(view, "Publish notes") {
+		t.Error("collapsed task should show 1/2 progress without items")
+	}
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	assertGolden(t, "task_pane_checklist", pane.View())
+
+	// Navigation walks into the checklist before the next task
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	pane.Update(down)
+	pane.Update(down)
+	if pane.cursor != 0 || pane.itemCursor != 1 {
+		t.Fatalf("selection = task %d item %d, want task 0 item 1", pane.cursor, pane.itemCursor)
+	}
+
+	// Toggling on an item row toggles the item, not the task
+	cmd := pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("expected a toggle command")
+	}
+	if _, ok := cmd().(checklistItemToggledMsg); !ok {
+		t.Fatal("toggle on item row should return checklistItemToggledMsg")
+	}
+	tasks, _ = store.LoadTasks()
+	if tasks.Tasks[1].Done {
+		t.Error("toggling an item should not complete the task")
+	}
+	if done, _ := tasks.Tasks[1].ChecklistProgress(); done != 2 {
+		t.Errorf("checklist done = %d, want 2", done)
+	}
+
+	pane.Update(down)
+	if pane.cursor != 1 || pane.itemCursor != -1 {
+		t.Errorf("selection = task %d item %d, want task 1", pane.cursor, pane.itemCursor)
+	}
+}
+
+func TestTaskPane_AddChecklistItems(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Pack", "", storage.PriorityNone, nil)
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetFocused(true)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	if !pane.IsAddingItem() {
+		t.Fatal("IsAddingItem() = false, want true after pressing 'A'")
+	}
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	for _, text := range []string{"Passport", "Charger"} {
+		pane.input.SetValue(text)
+		cmd := pane.Update(enter)
+		if cmd == nil {
+			t.Fatalf("expected add command for %q", text)
+		}
+		cmd()
+	}
+	if !pane.IsAddingItem() {
+		t.Error("adding items should continue until an empty entry")
+	}
+	pane.Update(enter)
+	if pane.IsAddingItem() {
+		t.Error("empty entry should finish adding items")
+	}
+
+	tasks, _ = store.LoadTasks()
+	if got := tasks.Tasks[0].Checklist; len(got) != 2 || got[1].Text != "Charger" {
+		t.Errorf("checklist = %+v, want Passport and Charger", got)
+	}
+	if !pane.expanded[task.ID] {
+		t.Error("task should be expanded after adding items")
+	}
+}
+
+func TestTaskPane_AddWithTags(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetFocused(true)
+
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	pane.input.SetValue("Call Alice #Client-A about issue #123 #phone")
+	cmd := pane.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected an add command")
+	}
+	msg := cmd().(taskAddedMsg)
+	if msg.err != nil {
+		t.Fatalf("add error: %v", msg.err)
+	}
+	if msg.task.Text != "Call Alice about issue #123" {
+		t.Errorf("task text = %q, want tags stripped", msg.task.Text)
+	}
+	if strings.Join(msg.task.Tags, ",") != "client-a,phone" {
+		t.Errorf("task tags = %v, want [client-a phone]", msg.task.Tags)
+	}
+}
+
+func TestTaskPane_TagFilter(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	store.AddTask("Invoice", "", storage.PriorityNone, nil, "client-a")
+	store.AddTask("Groceries", "", storage.PriorityNone, nil, "home")
+	store.AddTask("Kickoff call", "", storage.PriorityNone, nil, "client-a", "phone")
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(40, 20)
+	pane.SetFocused(false)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	pane.SetFocused(true)
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+	if !pane.IsFiltering() {
+		t.Fatal("IsFiltering() = false, want true after pressing '#'")
+	}
+	pane.input.SetValue("client-a")
+	pane.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if pane.TagFilter() != "client-a" || len(pane.tasks) != 2 {
+		t.Fatalf("filter %q shows %d tasks, want client-a with 2", pane.TagFilter(), len(pane.tasks))
+	}
+	if done, total := pane.Stats(); done != 0 || total != 3 {
+		t.Errorf("Stats() = %d/%d, want totals across all tasks", done, total)
+	}
+
+	pane.SetFocused(false)
+	assertGolden(t, "task_pane_tag_filter", pane.View())
+
+	// An empty filter shows everything again
+	pane.SetFocused(true)
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+	pane.input.SetValue("")
+	pane.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if pane.TagFilter() != "" || len(pane.tasks) != 3 {
+		t.Errorf("clearing the filter should show all 3 tasks, got %d", len(pane.tasks))
+	}
+}
+
+func TestTaskPane_NotesPreview(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	store.AddTask("Plain task", "", storage.PriorityNone, nil)
+	task, _ := store.AddTask("Write spec", "", storage.PriorityNone, nil)
+	task.Notes = "Acceptance criteria:\n\n- exports include notes\n- survives undo\n- shown in the list"
+	if err := store.UpdateTask(*task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(40, 20)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+	pane.SetFocused(true)
+
+	preview := pane.notesPreview()
+	if len(preview) != maxNotesPreviewLines || preview[len(preview)-1] != "… 2 more lines" {
+		t.Fatalf("notesPreview() = %q, want %d lines ending with a hint", preview, maxNotesPreviewLines)
+	}
+	assertGolden(t, "task_pane_notes", pane.View())
+
+	// Clicking the preview does nothing; rows below it are shifted down.
+	click := tea.MouseMsg{X: 10, Y: 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	pane.Update(click)
+	if pane.cursor != 0 {
+		t.Errorf("click on notes moved cursor to %d, want 0", pane.cursor)
+	}
+	click.Y = 2 + 1 + len(preview)
+	pane.Update(click)
+	if pane.cursor != 1 {
+		t.Errorf("click below notes selected %d, want 1", pane.cursor)
+	}
+	if pane.notesPreview() != nil {
+		t.Error("notesPreview() should be empty for a task without notes")
+	}
+}
+
+func TestTaskPane_BlockedBy(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	store.AddTask("Order laptops", "", storage.PriorityHigh, nil)
+	store.AddTask("Get budget approval", "", storage.PriorityNone, nil)
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(50, 20)
+	pane.SetFocused(true)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	// Select "Order laptops" (high priority, first) and pick its blocker
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if !pane.IsLinking() || !pane.InInputMode() {
+		t.Fatal("pressing 'b' should start choosing a blocker")
+	}
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	cmd := pane.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if pane.IsLinking() || cmd == nil {
+		t.Fatal("Enter should finish linking and return a command")
+	}
+	msg, ok := cmd().(blockerChangedMsg)
+	if !ok || msg.err != nil || !msg.added {
+		t.Fatalf("expected a successful blockerChangedMsg, got %+v", msg)
+	}
+
+	tasks, _ = store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+	if pane.tasks[0].Text != "Get budget approval" {
+		t.Errorf("blocked task should sort last, first is %q", pane.tasks[0].Text)
+	}
+
+	pane.SetFocused(false)
+	assertGolden(t, "task_pane_blocked", pane.View())
+
+	// Choosing the same blocker again removes the relation
+	pane.SetFocused(true)
+	pane.cursor = 1
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	msg = pane.Update(tea.KeyMsg{Type: tea.KeyEnter})().(blockerChangedMsg)
+	if msg.err != nil || msg.added {
+		t.Errorf("expected the blocker to be removed, got %+v", msg)
+	}
+}
+
+func TestTaskPane_ManualOrder(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	store.AddTask("Third", "", storage.PriorityNone, nil)
+	store.AddTask("Second", "", storage.PriorityNone, nil)
+	store.AddTask("First", "", storage.PriorityNone, nil)
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(40, 20)
+	pane.SetFocused(true)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+
+	order := func() string {
+		var texts []string
+		for _, task := range pane.tasks {
+			texts = append(texts, task.Text)
+		}
+		return strings.Join(texts, ",")
+	}
+
+	// Moving is refused while sorting automatically
+	cmd := pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	if msg, ok := cmd().(tasksReorderedMsg); !ok || msg.err == nil {
+		t.Fatal("moving a task with automatic sorting should report an error")
+	}
+
+	pane.SetManualSort(true)
+	cmd = pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	if order() != "Second,First,Third" || pane.cursor != 1 {
+		t.Fatalf("after J: order %s, cursor %d; want First moved down and selected", order(), pane.cursor)
+	}
+	msg := cmd().(tasksReorderedMsg)
+	if msg.err != nil || msg.text != "First" {
+		t.Fatalf("reorder failed: %+v", msg)
+	}
+
+	// The order persists across reloads
+	tasks, _ = store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+	if order() != "Second,First,Third" {
+		t.Errorf("reloaded order = %s", order())
+	}
+
+	// Drag "Third" (row 2) onto the top row
+	pane.Update(tea.MouseMsg{X: 10, Y: 4, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	cmd = pane.Update(tea.MouseMsg{X: 10, Y: 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	if cmd == nil || order() != "Third,Second,First" || pane.cursor != 0 {
+		t.Fatalf("after drag: order %s, cursor %d", order(), pane.cursor)
+	}
+	cmd()
+
+	// Undoing the first move restores the order from before it
+	action := NewReorderTasksAction(store, msg.before, msg.after, msg.text)
+	if action.Description != "Moved: First" {
+		t.Errorf("Unexpected description: %s", action.Description)
+	}
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	tasks, _ = store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+	if order() != "First,Second,Third" {
+		t.Errorf("restored order = %s, want First,Second,Third", order())
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	cmd := editorCommand("/tmp/notes.md")
+	if got := strings.Join(cmd.Args, " "); got != "code --wait /tmp/notes.md" {
+		t.Errorf("editorCommand() args = %q, want $EDITOR with its arguments", got)
+	}
+
+	t.Setenv("VISUAL", "nano")
+	if got := editorCommand("/tmp/notes.md").Args[0]; got != "nano" {
+		t.Errorf("editorCommand() = %q, want $VISUAL to take precedence", got)
+	}
+}
+
+func TestParseDueDateInput(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input   string
+		want    string // YYYY-MM-DD, or "" for nil
+		wantErr bool
+	}{
+		{"", "", false},
+		{"none", "", false},
+		{"today", "2025-03-10", false},
+		{"Tomorrow", "2025-03-11", false},
+		{"2025-04-01", "2025-04-01", false},
+		{"next week", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDueDateInput(tt.input, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDueDateInput(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		gotStr := ""
+		if got != nil {
+			gotStr = got.Format("2006-01-02")
+		}
+		if gotStr != tt.want {
+			t.Errorf("parseDueDateInput(%q) = %q, want %q", tt.input, gotStr, tt.want)
+		}
+	}
+}
+
+func TestParseDueInput(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input     string
+		want      string // YYYY-MM-DD HH:MM, or "" for nil
+		wantTimed bool
+		wantErr   bool
+	}{
+		{"", "", false, false},
+		{"tomorrow", "2025-03-11 00:00", false, false},
+		{"14:00", "2025-03-10 14:00", true, false},
+		{"tomorrow 9:30", "2025-03-11 09:30", true, false},
+		{"2025-04-01 17:45", "2025-04-01 17:45", true, false},
+		{"none 14:00", "", false, true},
+		{"2025-04-01 25:00", "", false, true},
+	}
+
+	for _, tt := range tests {
+		got, timed, err := parseDueInput(tt.input, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDueInput(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		gotStr := ""
+		if got != nil {
+			gotStr = got.Format("2006-01-02 15:04")
+		}
+		if gotStr != tt.want || timed != tt.wantTimed {
+			t.Errorf("parseDueInput(%q) = %q (timed %v), want %q (timed %v)", tt.input, gotStr, timed, tt.want, tt.wantTimed)
+		}
+	}
+}
+
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestTaskPane_TrackedTime(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	done, _ := store.AddTask("Tracked earlier", "", storage.PriorityNone, nil)
+	running, _ := store.AddTask("Tracking now", "", storage.PriorityNone, nil)
+	store.AddTask("Untracked", "", storage.PriorityNone, nil)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return now })
+	timer := &storage.TimerStore{
+		Entries: []storage.TimerEntry{
+			{Project: "work", TaskID: done.ID, StartedAt: now.Add(-3 * time.Hour), EndedAt: now.Add(-100 * time.Minute)},
+		},
+		Current: &storage.CurrentTimer{Project: "work", TaskID: running.ID, StartedAt: now.Add(-12 * time.Minute)},
+	}
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(50, 12)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+	pane.SetTimerStore(timer)
+	pane.SetFocused(true)
+
+	assertGolden(t, "task_pane_tracked_time", pane.View())
+
+	// t on the running task stops the timer; on another task it starts one
+	pane.cursor = 1
+	if msg := pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})(); msg != (timerStoppedMsg{}) {
+		t.Errorf("t on the tracked task = %#v, want timerStoppedMsg", msg)
+	}
+	pane.cursor = 0
+	msg := pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})()
+	if started, ok := msg.(timerStartedMsg); !ok || started.err != nil {
+		t.Fatalf("t on another task = %#v, want timerStartedMsg", msg)
+	}
+	ts, _ := store.LoadTimer()
+	if ts.Current == nil || ts.Current.TaskID != pane.tasks[0].ID {
+		t.Errorf("Current = %+v, want a timer on %q", ts.Current, pane.tasks[0].Text)
+	}
+}
+
+func TestTaskPane_DeferredTasks(t *testing.T) {
+	setupTest(t)
+	store := createTestStorage(t)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	store.SetNowFunc(func() time.Time { return now })
+
+	store.AddTask("Pay rent", "", storage.PriorityNone, nil)
+	later, _ := store.AddTask("File taxes", "", storage.PriorityNone, nil)
+	start := time.Date(2025, 3, 5, 0, 0, 0, 0, time.Local)
+	later.StartDate = &start
+	if err := store.UpdateTask(*later); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	pane := NewTaskPane(store, createTestStyles())
+	pane.SetSize(50, 12)
+	tasks, _ := store.LoadTasks()
+	pane.setTasks(tasks.Tasks)
+	pane.SetFocused(true)
+
+	if len(pane.tasks) != 1 || pane.hiddenDeferred != 1 {
+		t.Fatalf("visible = %d, hidden = %d, want 1 and 1", len(pane.tasks), pane.hiddenDeferred)
+	}
+	if done, total := pane.Stats(); done != 0 || total != 1 {
+		t.Errorf("Stats() = %d/%d, want deferred tasks left out", done, total)
+	}
+
+	// H reveals deferred tasks with their start date
+	pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'H'}})
+	if len(pane.tasks) != 2 {
+		t.Fatalf("visible = %d after H, want 2", len(pane.tasks))
+	}
+	pane.SetFocused(false)
+	assertGolden(t, "task_pane_deferred", pane.View())
+	pane.SetFocused(true)
+
+	// z on an actionable task defers it to tomorrow
+	pane.cursor = 1
+	msg, ok := pane.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})().(taskSnoozedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("z = %#v, want taskSnoozedMsg", msg)
+	}
+	tasks, _ = store.LoadTasks()
+	for _, task := range tasks.Tasks {
+		if task.ID == msg.after.ID && (task.StartDate == nil || task.StartDate.Day() != 2) {
+			t.Errorf("StartDate = %v, want Mar 2", task.StartDate)
+		}
+	}
+}
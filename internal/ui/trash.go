@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"today/internal/storage"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// TrashView lists deleted tasks and habits so they can be restored or purged
+type TrashView struct {
+	width         int
+	height        int
+	styles        *Styles
+	keys          TrashKeyMap
+	items         []storage.TrashItem
+	cursor        int
+	retentionDays int
+}
+
+// NewTrashView creates a new trash view. retentionDays is only shown as a
+// hint; purging expired items happens at startup.
+func NewTrashView(styles *Styles, keys TrashKeyMap, retentionDays int) *TrashView {
+	return &TrashView{
+		styles:        styles,
+		keys:          keys,
+		retentionDays: retentionDays,
+	}
+}
+
+// SetSize sets the view dimensions
+func (t *TrashView) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+}
+
+// SetItems replaces the listed items, keeping the cursor in range.
+func (t *TrashView) SetItems(items []storage.TrashItem) {
+	t.items = items
+	if t.cursor >= len(t.items) {
+		t.cursor = len(t.items) - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+}
+
+// Selected returns the item under the cursor.
+func (t *TrashView) Selected() (storage.TrashItem, bool) {
+	if t.cursor < 0 || t.cursor >= len(t.items) {
+		return storage.TrashItem{}, false
+	}
+	return t.items[t.cursor], true
+}
+
+// Update handles navigation keys.
+func (t *TrashView) Update(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, t.keys.Up):
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case key.Matches(msg, t.keys.Down):
+		if t.cursor < len(t.items)-1 {
+			t.cursor++
+		}
+	case key.Matches(msg, t.keys.Top):
+		t.cursor = 0
+	case key.Matches(msg, t.keys.Bottom):
+		t.cursor = max(0, len(t.items)-1)
+	}
+}
+
+// View renders the trash list
+func (t *TrashView) View() string {
+	overlayWidth := 70
+	if t.width > 0 {
+		overlayWidth = min(70, max(20, t.width-4))
+	}
+
+	overlayStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.styles.ColorPrimary).
+		Padding(1, 2).
+		Width(overlayWidth)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.styles.ColorPrimary).
+		MarginBottom(1)
+
+	kindStyle := lipgloss.NewStyle().
+		Foreground(t.styles.ColorAccent).
+		Width(7)
+
+	dateStyle := lipgloss.NewStyle().
+		Foreground(t.styles.ColorTextMuted)
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(t.styles.ColorTextMuted).
+		Italic(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Trash (%d)", len(t.items))))
+	b.WriteString("\n\n")
+
+	if len(t.items) == 0 {
+		b.WriteString(mutedStyle.Render("Trash is empty"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor visible when the list is taller than the screen.
+	visible := len(t.items)
+	if t.height > 0 {
+		visible = min(visible, max(1, t.height-12))
+	}
+	start := 0
+	if t.cursor >= visible {
+		start = t.cursor - visible + 1
+	}
+
+	// Content width inside border and padding, minus cursor, kind and date.
+	textWidth := max(10, overlayWidth-6-2-7-13)
+	for i := start; i < start+visible && i < len(t.items); i++ {
+		item := t.items[i]
+		cursor := "  "
+		textStyle := t.styles.TaskPendingStyle
+		if i == t.cursor {
+			cursor = "▸ "
+			textStyle = t.styles.TaskSelectedStyle
+		}
+		name := textStyle.Render(runewidth.FillRight(truncateText(item.Name(), textWidth), textWidth))
+		deleted := dateStyle.Render(" " + item.DeletedAt.Format("Jan 2 15:04"))
+		b.WriteString(cursor + kindStyle.Render(string(item.Kind)) + name + deleted + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("Deleted items are purged after %d days", t.retentionDays)))
+
+	content := overlayStyle.Render(b.String())
+	return lipgloss.Place(t.width, t.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -154,6 +154,33 @@ func NewDeleteTaskAction(store *storage.Storage, task storage.Task) *UndoableAct
 	}
 }
 
+// NewUpdateTaskAction creates an undoable action for a task edit.
+// Both snapshots are captured so the edit can be reverted and reapplied.
+func NewUpdateTaskAction(store *storage.Storage, before, after storage.Task) *UndoableAction {
+	return &UndoableAction{
+		Description: "Edited task: " + truncateText(after.Text, 20),
+		Undo: func() error {
+			return store.UpdateTask(before)
+		},
+		Redo: func() error {
+			return store.UpdateTask(after)
+		},
+	}
+}
+
+// NewSnoozeTaskAction creates an undoable action for snoozing a task.
+func NewSnoozeTaskAction(store *storage.Storage, before, after storage.Task) *UndoableAction {
+	return &UndoableAction{
+		Description: "Snoozed: " + truncateText(after.Text, 20),
+		Undo: func() error {
+			return store.UpdateTask(before)
+		},
+		Redo: func() error {
+			return store.UpdateTask(after)
+		},
+	}
+}
+
 // NewCompleteTaskAction creates an undoable action for task completion.
 func NewCompleteTaskAction(store *storage.Storage, taskID string, taskText string) *UndoableAction {
 	return &UndoableAction{
@@ -180,6 +207,85 @@ func NewUncompleteTaskAction(store *storage.Storage, taskID string, taskText str
 	}
 }
 
+// NewAddChecklistItemAction creates an undoable action for adding a checklist item.
+func NewAddChecklistItemAction(store *storage.Storage, taskID string, item storage.ChecklistItem) *UndoableAction {
+	return &UndoableAction{
+		Description: "Added item: " + truncateText(item.Text, 20),
+		Undo: func() error {
+			return store.DeleteChecklistItem(taskID, item.ID)
+		},
+		Redo: func() error {
+			return store.RestoreChecklistItem(taskID, item, -1)
+		},
+	}
+}
+
+// NewToggleChecklistItemAction creates an undoable action for checking or
+// unchecking a checklist item.
+func NewToggleChecklistItemAction(store *storage.Storage, taskID string, item storage.ChecklistItem, done bool) *UndoableAction {
+	desc := "Checked: "
+	if !done {
+		desc = "Unchecked: "
+	}
+	return &UndoableAction{
+		Description: desc + truncateText(item.Text, 20),
+		Undo: func() error {
+			return store.SetChecklistItemDone(taskID, item.ID, !done)
+		},
+		Redo: func() error {
+			return store.SetChecklistItemDone(taskID, item.ID, done)
+		},
+	}
+}
+
+// NewDeleteChecklistItemAction creates an undoable action for checklist item
+// deletion. The item is restored at its original position.
+func NewDeleteChecklistItemAction(store *storage.Storage, taskID string, item storage.ChecklistItem, index int) *UndoableAction {
+	return &UndoableAction{
+		Description: "Deleted item: " + truncateText(item.Text, 20),
+		Undo: func() error {
+			return store.RestoreChecklistItem(taskID, item, index)
+		},
+		Redo: func() error {
+			return store.DeleteChecklistItem(taskID, item.ID)
+		},
+	}
+}
+
+// NewBlockerAction creates an undoable action for adding or removing a
+// "blocked by" relation between two tasks.
+func NewBlockerAction(store *storage.Storage, taskID, blockerID, text string, added bool) *UndoableAction {
+	block := func() error { return store.AddBlocker(taskID, blockerID) }
+	unblock := func() error { return store.RemoveBlocker(taskID, blockerID) }
+	if added {
+		return &UndoableAction{
+			Description: "Blocked: " + truncateText(text, 20),
+			Undo:        unblock,
+			Redo:        block,
+		}
+	}
+	return &UndoableAction{
+		Description: "Unblocked: " + truncateText(text, 20),
+		Undo:        block,
+		Redo:        unblock,
+	}
+}
+
+// NewReorderTasksAction creates an undoable action for moving a task in the
+// manual order. Both complete orders are captured so undo restores the list
+// exactly.
+func NewReorderTasksAction(store *storage.Storage, before, after []string, text string) *UndoableAction {
+	return &UndoableAction{
+		Description: "Moved: " + truncateText(text, 20),
+		Undo: func() error {
+			return store.SetTaskOrder(before)
+		},
+		Redo: func() error {
+			return store.SetTaskOrder(after)
+		},
+	}
+}
+
 // NewDeleteHabitAction creates an undoable action for habit deletion.
 // Captures the habit and all its logs for full restoration.
 func NewDeleteHabitAction(store *storage.Storage, habit storage.Habit, logs []storage.HabitLog) *UndoableAction {
@@ -194,8 +300,62 @@ func NewDeleteHabitAction(store *storage.Storage, habit storage.Habit, logs []st
 	}
 }
 
-// NewToggleHabitAction creates an undoable action for habit toggle.
-func NewToggleHabitAction(store *storage.Storage, habitID string, habitName string, date string, wasCompleted bool) *UndoableAction {
+// NewUpdateHabitAction creates an undoable action for a habit edit.
+func NewUpdateHabitAction(store *storage.Storage, before, after storage.Habit) *UndoableAction {
+	return &UndoableAction{
+		Description: "Edited habit: " + truncateText(after.Name, 20),
+		Undo: func() error {
+			return store.UpdateHabit(before)
+		},
+		Redo: func() error {
+			return store.UpdateHabit(after)
+		},
+	}
+}
+
+// NewHabitStateAction creates an undoable action for pausing, archiving or
+// resuming a habit.
+func NewHabitStateAction(store *storage.Storage, before, after storage.Habit) *UndoableAction {
+	verb := "Resumed"
+	switch after.State {
+	case storage.HabitPaused:
+		verb = "Paused"
+	case storage.HabitArchived:
+		verb = "Archived"
+	}
+	return &UndoableAction{
+		Description: verb + " habit: " + truncateText(after.Name, 20),
+		Undo: func() error {
+			return store.RestoreHabitState(before)
+		},
+		Redo: func() error {
+			return store.RestoreHabitState(after)
+		},
+	}
+}
+
+// NewRestoreFromTrashAction creates an undoable action for restoring a task or
+// habit from the trash. Undo deletes it again, moving it back to the trash.
+func NewRestoreFromTrashAction(store *storage.Storage, item storage.TrashItem) *UndoableAction {
+	id := item.ID()
+	return &UndoableAction{
+		Description: "Restored: " + truncateText(item.Name(), 20),
+		Undo: func() error {
+			if item.Kind == storage.TrashKindHabit {
+				return store.DeleteHabit(id)
+			}
+			return store.DeleteTask(id)
+		},
+		Redo: func() error {
+			_, err := store.RestoreFromTrash(id)
+			return err
+		},
+	}
+}
+
+// NewToggleHabitAction creates an undoable action for habit toggle. Undo
+// restores wasValue, the amount logged before the toggle.
+func NewToggleHabitAction(store *storage.Storage, habitID string, habitName string, date string, wasCompleted bool, wasValue int) *UndoableAction {
 	desc := "Completed: " + truncateText(habitName, 20)
 	if wasCompleted {
 		desc = "Uncompleted: " + truncateText(habitName, 20)
@@ -203,7 +363,7 @@ func NewToggleHabitAction(store *storage.Storage, habitID string, habitName stri
 	return &UndoableAction{
 		Description: desc,
 		Undo: func() error {
-			return store.SetHabitDoneOnDate(habitID, date, wasCompleted)
+			return store.SetHabitValueOnDate(habitID, date, wasValue)
 		},
 		Redo: func() error {
 			return store.SetHabitDoneOnDate(habitID, date, !wasCompleted)
@@ -211,6 +371,43 @@ func NewToggleHabitAction(store *storage.Storage, habitID string, habitName stri
 	}
 }
 
+// NewSkipHabitAction creates an undoable action for skipping a habit on
+// date, or removing the skip mark. Undoing a skip restores wasValue, the
+// amount logged before it.
+func NewSkipHabitAction(store *storage.Storage, habitID, habitName, date string, skipped bool, wasValue int) *UndoableAction {
+	desc := "Skipped: " + truncateText(habitName, 20)
+	if !skipped {
+		desc = "Unskipped: " + truncateText(habitName, 20)
+	}
+	return &UndoableAction{
+		Description: desc,
+		Undo: func() error {
+			if skipped {
+				// Logging replaces the skip mark; a value of 0 just removes it
+				return store.SetHabitValueOnDate(habitID, date, wasValue)
+			}
+			return store.SetHabitSkippedOnDate(habitID, date, true)
+		},
+		Redo: func() error {
+			return store.SetHabitSkippedOnDate(habitID, date, skipped)
+		},
+	}
+}
+
+// NewLogHabitAction creates an undoable action for a change to the amount
+// logged for a habit on date.
+func NewLogHabitAction(store *storage.Storage, habit storage.Habit, date string, before, after int) *UndoableAction {
+	return &UndoableAction{
+		Description: "Logged: " + truncateText(habit.Name, 20) + " " + habit.ProgressString(after),
+		Undo: func() error {
+			return store.SetHabitValueOnDate(habit.ID, date, before)
+		},
+		Redo: func() error {
+			return store.SetHabitValueOnDate(habit.ID, date, after)
+		},
+	}
+}
+
 // truncateText shortens text to maxLen with ellipsis if needed.
 func truncateText(text string, maxLen int) string {
 	if maxLen <= 0 {
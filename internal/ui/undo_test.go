@@ -362,12 +362,54 @@ func TestNewCompleteTaskAction(t *testing.T) {
 	}
 }
 
+// TestNewUpdateTaskAction verifies task edit undo/redo.
+func TestNewUpdateTaskAction(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, err := store.AddTask("Buy mlik", "", storage.PriorityNone, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	before := *task
+	after := *task
+	after.Text = "Buy milk"
+	after.Priority = storage.PriorityHigh
+
+	if err := store.UpdateTask(after); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	action := NewUpdateTaskAction(store, before, after)
+	if action.Description != "Edited task: Buy milk" {
+		t.Errorf("Unexpected description: %s", action.Description)
+	}
+
+	// Undo should restore the original text and priority
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	tasks, _ := store.LoadTasks()
+	if tasks.Tasks[0].Text != "Buy mlik" || tasks.Tasks[0].Priority != storage.PriorityNone {
+		t.Errorf("Task after undo = %+v, want original fields", tasks.Tasks[0])
+	}
+
+	// Redo should reapply the edit
+	if err := action.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	tasks, _ = store.LoadTasks()
+	if tasks.Tasks[0].Text != "Buy milk" || tasks.Tasks[0].Priority != storage.PriorityHigh {
+		t.Errorf("Task after redo = %+v, want edited fields", tasks.Tasks[0])
+	}
+}
+
 // TestNewToggleHabitAction verifies toggle habit undo action.
 func TestNewToggleHabitAction(t *testing.T) {
 	store := createTestStorage(t)
 
 	// Add a habit
-	habit, err := store.AddHabit("Exercise", "🏃")
+	habit, err := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
 	if err != nil {
 		t.Fatalf("Failed to add habit: %v", err)
 	}
@@ -375,7 +417,7 @@ func TestNewToggleHabitAction(t *testing.T) {
 	date := time.Now().Format("2006-01-02")
 
 	// Create undo action (habit was not completed)
-	action := NewToggleHabitAction(store, habit.ID, habit.Name, date, false)
+	action := NewToggleHabitAction(store, habit.ID, habit.Name, date, false, 0)
 
 	// Toggle should mark as done
 	isDone, err := store.ToggleHabitToday(habit.ID)
@@ -399,3 +441,174 @@ func TestNewToggleHabitAction(t *testing.T) {
 		t.Error("Expected habit to be not done after undo")
 	}
 }
+
+// TestNewSkipHabitAction verifies that undoing a skip restores what was logged.
+func TestNewSkipHabitAction(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, err := store.CreateHabit(storage.Habit{Name: "Water", Icon: "🥤", Target: 8})
+	if err != nil {
+		t.Fatalf("Failed to add habit: %v", err)
+	}
+	date := store.Now().Format("2006-01-02")
+	store.SetHabitValueOnDate(habit.ID, date, 3)
+
+	action := NewSkipHabitAction(store, habit.ID, habit.Name, date, true, 3)
+	if _, err := store.ToggleHabitSkipToday(habit.ID); err != nil {
+		t.Fatalf("Failed to skip habit: %v", err)
+	}
+
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	habits, _ := store.LoadHabits()
+	if store.IsHabitSkippedOnDate(habits, habit.ID, date) || store.HabitValueOnDate(habits, habit.ID, date) != 3 {
+		t.Error("Expected undo to remove the skip and restore the amount logged")
+	}
+
+	if err := action.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	habits, _ = store.LoadHabits()
+	if !store.IsHabitSkippedOnDate(habits, habit.ID, date) {
+		t.Error("Expected redo to skip the habit again")
+	}
+}
+
+func TestNewHabitStateAction(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, err := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	if err != nil {
+		t.Fatalf("Failed to add habit: %v", err)
+	}
+	paused, err := store.SetHabitState(habit.ID, storage.HabitPaused)
+	if err != nil {
+		t.Fatalf("Failed to pause habit: %v", err)
+	}
+
+	action := NewHabitStateAction(store, *habit, *paused)
+	if action.Description != "Paused habit: Exercise" {
+		t.Errorf("Unexpected description: %s", action.Description)
+	}
+
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if h := mustFirstHabit(t, store); !h.IsActive() {
+		t.Errorf("Expected undo to resume the habit, got state %q", h.State)
+	}
+
+	if err := action.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if h := mustFirstHabit(t, store); h.State != storage.HabitPaused || h.StateSince != paused.StateSince {
+		t.Errorf("Expected redo to pause the habit again, got %q since %q", h.State, h.StateSince)
+	}
+}
+
+func TestNewDeleteChecklistItemAction(t *testing.T) {
+	store := createTestStorage(t)
+
+	task, _ := store.AddTask("Ship release", "", storage.PriorityNone, nil)
+	first, _ := store.AddChecklistItem(task.ID, "Tag release")
+	store.AddChecklistItem(task.ID, "Publish notes")
+
+	if err := store.DeleteChecklistItem(task.ID, first.ID); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+
+	action := NewDeleteChecklistItemAction(store, task.ID, *first, 0)
+	if action.Description != "Deleted item: Tag release" {
+		t.Errorf("Unexpected description: %s", action.Description)
+	}
+
+	// Undo should restore the item at its original position
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	tasks, _ := store.LoadTasks()
+	if items := tasks.Tasks[0].Checklist; len(items) != 2 || items[0].ID != first.ID {
+		t.Errorf("Checklist after undo = %+v, want item restored first", items)
+	}
+
+	// Redo should delete it again
+	if err := action.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	tasks, _ = store.LoadTasks()
+	if len(tasks.Tasks[0].Checklist) != 1 {
+		t.Errorf("Checklist after redo has %d items, want 1", len(tasks.Tasks[0].Checklist))
+	}
+}
+
+func TestNewBlockerAction(t *testing.T) {
+	store := createTestStorage(t)
+
+	blocker, _ := store.AddTask("Get approval", "", storage.PriorityNone, nil)
+	task, _ := store.AddTask("Order laptops", "", storage.PriorityNone, nil)
+	if err := store.AddBlocker(task.ID, blocker.ID); err != nil {
+		t.Fatalf("Failed to add blocker: %v", err)
+	}
+
+	action := NewBlockerAction(store, task.ID, blocker.ID, task.Text, true)
+	if action.Description != "Blocked: Order laptops" {
+		t.Errorf("Unexpected description: %s", action.Description)
+	}
+
+	blockedBy := func() []string {
+		tasks, _ := store.LoadTasks()
+		for _, t := range tasks.Tasks {
+			if t.ID == task.ID {
+				return t.BlockedBy
+			}
+		}
+		return nil
+	}
+
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if len(blockedBy()) != 0 {
+		t.Error("Undo should remove the blocker")
+	}
+	if err := action.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if got := blockedBy(); len(got) != 1 || got[0] != blocker.ID {
+		t.Errorf("BlockedBy after redo = %v, want [%s]", got, blocker.ID)
+	}
+}
+
+func TestNewRestoreFromTrashAction(t *testing.T) {
+	store := createTestStorage(t)
+
+	habit, _ := store.AddHabit("Exercise", "🏃", storage.FrequencyDaily)
+	store.DeleteHabit(habit.ID)
+	item, err := store.RestoreFromTrash(habit.ID)
+	if err != nil {
+		t.Fatalf("Failed to restore from trash: %v", err)
+	}
+
+	action := NewRestoreFromTrashAction(store, *item)
+	if action.Description != "Restored: Exercise" {
+		t.Errorf("Unexpected description: %s", action.Description)
+	}
+
+	if err := action.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	habits, _ := store.LoadHabits()
+	trash, _ := store.LoadTrash()
+	if len(habits.Habits) != 0 || len(trash.Items) != 1 {
+		t.Errorf("Undo should move the habit back to the trash (habits=%d, trash=%d)", len(habits.Habits), len(trash.Items))
+	}
+
+	if err := action.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	habits, _ = store.LoadHabits()
+	if len(habits.Habits) != 1 {
+		t.Errorf("Redo should restore the habit, got %d habits", len(habits.Habits))
+	}
+}